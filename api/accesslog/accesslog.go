@@ -0,0 +1,68 @@
+// Package accesslog logs every GraphQL operation served by the API, with the
+// request ID of the request it came in, its outcome and duration. Variables
+// are only logged at the debug level, with their secrets redacted.
+package accesslog
+
+import (
+	"context"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// GraphQL is the gqlgen extension logging operations.
+type GraphQL struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = GraphQL{}
+
+// ExtensionName names the extension in stats and logs.
+func (GraphQL) ExtensionName() string {
+	return "AccessLog"
+}
+
+// Validate accepts any schema.
+func (GraphQL) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptResponse logs the operation of each response.
+func (GraphQL) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	res := next(ctx)
+
+	if !graphql.HasOperationContext(ctx) {
+		return res
+	}
+
+	oc := graphql.GetOperationContext(ctx)
+	logger := logging.FromContext(ctx)
+
+	opType := "unknown"
+	if oc.Operation != nil {
+		opType = string(oc.Operation.Operation)
+	}
+
+	fields := []zap.Field{
+		zap.String("operation", oc.OperationName),
+		zap.String("type", opType),
+		zap.Duration("duration", time.Since(oc.Stats.OperationStart)),
+	}
+
+	if logger.Core().Enabled(zapcore.DebugLevel) {
+		fields = append(fields, zap.Any("variables", oc.Variables))
+	}
+
+	if res != nil && len(res.Errors) > 0 {
+		logger.Warn("GraphQL operation failed", append(fields, zap.String("errors", res.Errors.Error()))...)
+		return res
+	}
+
+	logger.Info("GraphQL operation", fields...)
+
+	return res
+}
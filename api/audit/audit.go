@@ -0,0 +1,152 @@
+// Package audit records security-relevant events in the audit log kept by the
+// User service, and tells the User service who is behind each call it gets so
+// that it can audit the calls itself.
+package audit
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	pb "github.com/allen-woods/the-supertask/services/user/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Actions audited by the API. The User service audits its own.
+const (
+	ActionLoggedOut            = "session.loggedOut"
+	ActionCSRFRejected         = "request.csrfRejected"
+	ActionImpersonationStarted = "impersonation.started"
+	ActionImpersonationStopped = "impersonation.stopped"
+	ActionImpersonationDenied  = "impersonation.denied"
+)
+
+// Metadata keys under which the caller of the User service is passed.
+const (
+	actorIDKey   = "audit-actor-id"
+	ipKey        = "audit-ip"
+	userAgentKey = "audit-user-agent"
+)
+
+// Recorder stores an audit event.
+type Recorder func(ctx context.Context, e *pb.AuditEvent) error
+
+// Until SetRecorder is called, events are only logged.
+var recorder Recorder = func(ctx context.Context, e *pb.AuditEvent) error {
+	logging.FromContext(ctx).Info("Audit event",
+		zap.String("action", e.GetAction()),
+		zap.String("targetId", e.GetTargetId()),
+		zap.Stringer("outcome", e.GetOutcome()),
+		zap.String("detail", e.GetDetail()),
+	)
+	return nil
+}
+
+// SetRecorder sets where audit events are stored.
+func SetRecorder(r Recorder) {
+	recorder = r
+}
+
+type contextKey struct {
+	name string
+}
+
+var clientCtxKey = &contextKey{"auditClient"}
+
+type client struct {
+	actorID   string
+	ip        string
+	userAgent string
+}
+
+// WithRequest returns a copy of ctx carrying the user acting through r and the
+// network details of r.
+func WithRequest(ctx context.Context, r *http.Request, actorID string) context.Context {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	return context.WithValue(ctx, clientCtxKey, &client{
+		actorID:   actorID,
+		ip:        ip,
+		userAgent: r.UserAgent(),
+	})
+}
+
+// Detach returns a background context carrying the caller and request ID in
+// ctx, for work that outlives the request that started it.
+func Detach(ctx context.Context) context.Context {
+	detached := context.WithValue(context.Background(), clientCtxKey, fromContext(ctx))
+
+	if id := logging.RequestID(ctx); id != "" {
+		detached = logging.WithRequestID(detached, id)
+	}
+
+	return detached
+}
+
+func fromContext(ctx context.Context) *client {
+	c, ok := ctx.Value(clientCtxKey).(*client)
+	if !ok {
+		return &client{}
+	}
+
+	return c
+}
+
+// Record stores an event about the given user, filling in the actor and
+// network details from ctx. Failing to audit an action does not fail the
+// action, so errors are only logged.
+func Record(ctx context.Context, action string, targetID string, outcome pb.AuditOutcome, detail string) {
+	c := fromContext(ctx)
+
+	e := &pb.AuditEvent{
+		Action:    action,
+		ActorId:   c.actorID,
+		TargetId:  targetID,
+		Ip:        c.ip,
+		UserAgent: c.userAgent,
+		Outcome:   outcome,
+		Detail:    detail,
+	}
+
+	err := recorder(ctx, e)
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to record audit event",
+			zap.String("action", action),
+			zap.String("targetId", targetID),
+			zap.Error(err),
+		)
+	}
+}
+
+func outgoingContext(ctx context.Context) context.Context {
+	c := fromContext(ctx)
+
+	pairs := []string{}
+	if c.actorID != "" {
+		pairs = append(pairs, actorIDKey, c.actorID)
+	}
+	if c.ip != "" {
+		pairs = append(pairs, ipKey, c.ip)
+	}
+	if c.userAgent != "" {
+		pairs = append(pairs, userAgentKey, c.userAgent)
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+// UnaryClientInterceptor passes the caller in ctx to the User service.
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(outgoingContext(ctx), method, req, reply, cc, opts...)
+}
+
+// StreamClientInterceptor passes the caller in ctx to the User service.
+func StreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(outgoingContext(ctx), desc, cc, method, opts...)
+}
@@ -1,35 +1,38 @@
 package auth
 
-import(
+import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
-	"fmt"
-	"log"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sync"
 	"time"
 
+	"github.com/allen-woods/the-supertask/api/audit"
+	"github.com/allen-woods/the-supertask/api/events"
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	pb "github.com/allen-woods/the-supertask/services/user/proto"
 	"github.com/go-redis/redis"
 	"github.com/gorilla/securecookie"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	uuid "github.com/satori/go.uuid"
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var authenticatedUserID struct {
-	id string
-}
-
-var deleteSessionAndCookie struct {
-	flag bool
-}
-
 var validCookies []securecookie.Codec
 
 var hashKeyData struct {
 	hash [][]byte
-	key [][]byte
+	key  [][]byte
 }
 
 var userIDCtxKey = &contextKey{"userID"}
@@ -56,13 +59,32 @@ func GenerateRandomString(s int) (string, error) {
 	return base64.URLEncoding.EncodeToString(b), err
 }
 
-func Roll() error {
-	err := rollFile(".hash")
+// Roll adds a key to the cookie keys kept in the .hash and .key files of dir,
+// which is created if need be, and loads them. The directory must be kept out
+// of the source tree and of images: whoever reads it can forge and read every
+// cookie of the API.
+func Roll(dir string) error {
+	err := os.MkdirAll(dir, 0700)
 	if err != nil {
 		return err
 	}
 
-	err = rollFile(".key")
+	hash, err := rollFile(filepath.Join(dir, ".hash"))
+	if err != nil {
+		return err
+	}
+
+	err = parseHashDataToMemory(hash)
+	if err != nil {
+		return err
+	}
+
+	key, err := rollFile(filepath.Join(dir, ".key"))
+	if err != nil {
+		return err
+	}
+
+	err = parseKeyDataToMemory(key)
 	if err != nil {
 		return err
 	}
@@ -75,29 +97,41 @@ func Roll() error {
 	return nil
 }
 
-func rollFile(fName string) error {
+// CheckKeyring reports whether the cookie keys have been rolled, without which
+// no session, CSRF or impersonation cookie can be read or written.
+func CheckKeyring() error {
+	if len(validCookies) == 0 {
+		return errors.New("cookie keys have not been rolled")
+	}
+
+	return nil
+}
+
+// rollFile appends a key to the file fName, dropping the oldest once it holds
+// 24, and returns its keys.
+func rollFile(fName string) ([]byte, error) {
 	var f *os.File
 
 	_, err := os.Stat(fName)
 	if os.IsNotExist(err) {
 		f, err = os.OpenFile(fName, os.O_CREATE|os.O_RDWR, 0600)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		err = f.Chown(os.Getuid(), os.Getgid())
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		err = os.Chtimes(fName, time.Now(), time.Now())
 		if err != nil {
-			return err
+			return nil, err
 		}
 	} else {
 		f, err = os.OpenFile(fName, os.O_RDWR, 0600)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -105,7 +139,7 @@ func rollFile(fName string) error {
 
 	fInfo, err := f.Stat()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	n := fInfo.Size()
@@ -116,49 +150,36 @@ func rollFile(fName string) error {
 		fData = make([]byte, n-32)
 
 		lenBytes, err := f.ReadAt(fData, 32)
-		if lenBytes != int(n-32 || err != nil {
-			return errors.New("Corruption of data in rolling encryption file:\nUnexpected number of bytes.")
+		if lenBytes != int(n-32) || err != nil {
+			return nil, errors.New("Corruption of data in rolling encryption file:\nUnexpected number of bytes.")
 		}
 	} else {
-		fData = make([]byte], n)
+		fData = make([]byte, n)
 
 		lenBytes, err := f.Read(fData)
 		if lenBytes != int(n) || err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	s, err := GenerateRandomString(24)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	fData = append(fData, []byte(s)...)
 
 	_, err = f.WriteAt(fData, 0)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	err = f.Sync()
 	if err != nil {
-		return err
-	}
-
-	switch fName {
-	case ".hash":
-		err = parseHashDataToMemory(fData)
-		if err != nil {
-			return err
-		}
-	case ".key":
-		err = parseKeyDataToMemory(fData)
-		if err != nil {
-			return err
-		}
+		return nil, err
 	}
 
-	return nil
+	return fData, nil
 }
 
 func parseHashDataToMemory(data []byte) error {
@@ -170,7 +191,7 @@ func parseHashDataToMemory(data []byte) error {
 	hashKeyData.hash = make([][]byte, 0, 24)
 
 	for k := 0; k < n; k++ {
-		h := data[k*32 : ((k*32)*32)-1]
+		h := data[k*32 : (k+1)*32]
 		hashKeyData.hash = append(hashKeyData.hash, h)
 	}
 
@@ -186,7 +207,7 @@ func parseKeyDataToMemory(data []byte) error {
 	hashKeyData.key = make([][]byte, 0, 24)
 
 	for m := 0; m < n; m++ {
-		l := data[m*32 : ((m*32)*32)-1]
+		l := data[m*32 : (m+1)*32]
 		hashKeyData.key = append(hashKeyData.key, l)
 	}
 
@@ -249,21 +270,100 @@ func ReadSessionIDFromCookie(w http.ResponseWriter, r *http.Request) (string, er
 	return validSessionID.String(), nil
 }
 
-func ReadFromRedis(sessionID map[string]string) (string, error) {
-	// TODO: Update this to use Docker URI.
-	client := redis.NewClient(&redis.Options{
-		Network: "backend"
-		Addr: fmt.Sprintf(
-			"%s:%d",
-			os.Getenv("REDIS_IP").(String),
-			os.Getenv("REDIS_PORT").(Int)
-		),
-		Username: os.Getenv("REDIS_ADMIN_USERNAME"),
-		Password: os.Getenv("REDIS_ADMIN_PASSWORD"),
-	})
-	defer client.Close()
+// redisOptions is how RedisClient connects, as set by SetRedisOptions.
+var redisOptions = redis.Options{}
+
+// SetRedisOptions sets the address and password of the Redis instance clients
+// connect to from now on.
+func SetRedisOptions(addr string, password string) {
+	redisOptions = redis.Options{
+		Addr:     addr,
+		Password: password,
+	}
+}
+
+// tracerName names the spans of the session store.
+const tracerName = "github.com/allen-woods/the-supertask/api/auth"
+
+// redisClient is shared by every request, as it holds a pool of connections.
+var (
+	redisMu     sync.Mutex
+	redisClient *redis.Client
+)
+
+// RedisClient returns the client of the Redis instance holding our sessions
+// and other short-lived data. It is shared, and must not be closed but by
+// CloseRedis.
+func RedisClient() *redis.Client {
+	redisMu.Lock()
+	defer redisMu.Unlock()
+
+	if redisClient == nil {
+		opts := redisOptions
+		redisClient = redis.NewClient(&opts)
+	}
 
-	userID, err := client.Do("HGET", sessionID["sessionID"], "userID").String()
+	return redisClient
+}
+
+// PingRedis reports whether Redis can be reached.
+func PingRedis(ctx context.Context) error {
+	return RedisClient().WithContext(ctx).Ping().Err()
+}
+
+// CloseRedis closes the shared Redis client, once nothing uses it anymore.
+func CloseRedis() error {
+	redisMu.Lock()
+	defer redisMu.Unlock()
+
+	if redisClient == nil {
+		return nil
+	}
+
+	err := redisClient.Close()
+	redisClient = nil
+
+	return err
+}
+
+// userSessionsKey is the Redis set indexing every session of a user.
+func userSessionsKey(userID string) string {
+	return "userSessions:" + userID
+}
+
+// sessionLookups counts the sessions looked up by Middleware, by whether they
+// were found in Redis.
+var sessionLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "api",
+	Subsystem: "sessions",
+	Name:      "lookups_total",
+	Help:      "Session lookups in Redis, by result: hit, miss (expired or revoked) or error.",
+}, []string{"result"})
+
+// startRedisSpan starts the span of a session store operation on Redis.
+func startRedisSpan(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, "redis "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemRedis, semconv.DBOperationKey.String(operation)),
+	)
+}
+
+// endRedisSpan ends span, recording err unless it is a missing key.
+func endRedisSpan(span trace.Span, err error) {
+	if err != nil && err != redis.Nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+	span.End()
+}
+
+func ReadFromRedis(ctx context.Context, sessionID map[string]string) (userID string, err error) {
+	_, span := startRedisSpan(ctx, "ReadSession")
+	defer func() { endRedisSpan(span, err) }()
+
+	client := RedisClient()
+
+	userID, err = client.Do("HGET", sessionID["sessionID"], "userID").String()
 	if err != nil {
 		return "", err
 	}
@@ -271,21 +371,13 @@ func ReadFromRedis(sessionID map[string]string) (string, error) {
 	return userID, nil
 }
 
-func WriteToRedis(sessionID map[string]string, userID string, ttl time.Time) error {
-	client := redis.NewClient(&redis.Options{
-		Network: "backend"
-		Addr: fmt.Sprintf(
-			"%s:%d",
-			os.Getenv("REDIS_IP").(String),
-			os.Getenv("REDIS_PORT").(Int)
-		),
-		Username: os.Getenv("REDIS_ADMIN_USERNAME"),
-		Password: os.Getenv("REDIS_ADMIN_PASSWORD"),
-	})
+func WriteToRedis(ctx context.Context, sessionID map[string]string, userID string, ttl time.Time) (err error) {
+	_, span := startRedisSpan(ctx, "WriteSession")
+	defer func() { endRedisSpan(span, err) }()
 
-	defer client.Close()
+	client := RedisClient()
 
-	_, err := client.Do("HMSET", sessionID["sessionID"], "userID", userID).Result()
+	_, err = client.Do("HMSET", sessionID["sessionID"], "userID", userID).Result()
 	if err != nil {
 		return err
 	}
@@ -295,139 +387,309 @@ func WriteToRedis(sessionID map[string]string, userID string, ttl time.Time) err
 		return err
 	}
 
+	if userID == "" {
+		return nil
+	}
+
+	// Index the session under its user so all of them can be revoked at once.
+	_, err = client.SAdd(userSessionsKey(userID), sessionID["sessionID"]).Result()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.ExpireAt(userSessionsKey(userID), ttl).Result()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Session describes a session of a user without revealing its ID. It has
+// the label of its events in the session history.
+type Session struct {
+	Session   string    `json:"session"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// UserSessions lists the sessions of the given user that have not expired.
+func UserSessions(ctx context.Context, userID string) (sessions []Session, err error) {
+	_, span := startRedisSpan(ctx, "ListSessions")
+	defer func() { endRedisSpan(span, err) }()
+
+	client := RedisClient()
+
+	sessionIDs, err := client.SMembers(userSessionsKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions = []Session{}
+
+	for _, sessionID := range sessionIDs {
+		// Sessions that have expired, been revoked or logged out linger in
+		// the set until it expires.
+		owner, err := client.HGet(sessionID, "userID").Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if owner != userID {
+			continue
+		}
+
+		ttl, err := client.TTL(sessionID).Result()
+		if err != nil {
+			return nil, err
+		}
+		if ttl < 0 {
+			continue
+		}
+
+		sessions = append(sessions, Session{
+			Session:   sessionLabel(sessionID),
+			ExpiresAt: time.Now().Add(ttl).UTC().Truncate(time.Second),
+		})
+	}
+
+	return sessions, nil
+}
+
+// Reasons for revoking the sessions of a user, as told to the subscribers of
+// their revocation.
+const (
+	RevokedAccountSuspended = "ACCOUNT_SUSPENDED"
+	RevokedAccountDeleted   = "ACCOUNT_DELETED"
+)
+
+// RevokeUserSessions deletes every session belonging to the given user, tells
+// the open tabs of the user why and records it in their session history.
+func RevokeUserSessions(ctx context.Context, userID string, reason string) (err error) {
+	_, span := startRedisSpan(ctx, "RevokeSessions")
+	defer func() { endRedisSpan(span, err) }()
+
+	client := RedisClient()
+
+	sessionIDs, err := client.SMembers(userSessionsKey(userID)).Result()
+	if err != nil {
+		return err
+	}
+
+	// Only the sessions that are still live are told of in the history.
+	live := []string{}
+	for _, sessionID := range sessionIDs {
+		owner, err := client.HGet(sessionID, "userID").Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		if owner == userID {
+			live = append(live, sessionID)
+		}
+	}
+
+	_, err = client.Del(append(sessionIDs, userSessionsKey(userID))...).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, sessionID := range live {
+		e := newSessionEvent(sessionID, SessionRevoked, nil)
+		e.Reason = reason
+		recordSessionEvent(ctx, userID, e)
+	}
+
+	// The sessions are revoked whether or not the tabs hear of it, as they
+	// are anonymous from their next request on.
+	err = events.Publish(ctx, events.SessionsRevokedTopic(userID), events.SessionsRevoked{
+		UserID:    userID,
+		Reason:    reason,
+		RevokedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		logging.FromContext(ctx).Warn("Unable to publish the revocation of sessions", zap.Error(err))
+	}
+
 	return nil
 }
 
-// Middleware is the authentication middleware function of our API.
+// sessionDuration is how long a session lasts after its last request.
+const sessionDuration = 24 * time.Hour
+
+var sessionCtxKey = &contextKey{"session"}
+
+// requestSession is the session a request is made under, which LogIn and
+// LogOut replace.
+type requestSession struct {
+	id     string
+	userID string
+	w      http.ResponseWriter
+	r      *http.Request
+}
+
+// writeSession binds sessionID to userID, or to no one, for sessionDuration
+// from now on, and sets the cookies of the session on w.
+func writeSession(ctx context.Context, w http.ResponseWriter, r *http.Request, sessionID string, userID string) error {
+	value := map[string]string{"sessionID": sessionID}
+	expiration := time.Now().Add(sessionDuration)
+
+	err := WriteToRedis(ctx, value, userID, expiration)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := securecookie.EncodeMulti("sid", value, validCookies[len(validCookies)-1])
+	if err != nil {
+		return err
+	}
+
+	maxAge := int(sessionDuration.Seconds())
+
+	http.SetCookie(w, newCookie("sid", encoded, true, maxAge, expiration))
+
+	return issueCSRFToken(w, r, value, maxAge, expiration)
+}
+
+// deleteSession removes the session with the given ID from Redis, and from
+// the index of the sessions of its user.
+func deleteSession(ctx context.Context, sessionID string, userID string) (err error) {
+	_, span := startRedisSpan(ctx, "DeleteSession")
+	defer func() { endRedisSpan(span, err) }()
+
+	client := RedisClient()
+
+	_, err = client.Del(sessionID).Result()
+	if err != nil {
+		return err
+	}
+
+	if userID == "" {
+		return nil
+	}
+
+	_, err = client.SRem(userSessionsKey(userID), sessionID).Result()
+	return err
+}
+
+// Middleware is the authentication middleware function of our API. The user
+// of the session cookie is put in the context of the request, for ForContext
+// to return. Requests without a cookie, or with one that is invalid, expired
+// or revoked, are anonymous and get a new session.
 func Middleware() func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// TODO:
-			//
-			// Gqlgen no longer uses these handler types, so must migrate
-			// this code to use the new standard.
-			// Also, this code needs to get cleaned up by refactoring.
-			var maxAge int
-			var expiration time.Time
-
-			del := deleteSessionAndCookie.flag
-			if del == true {
-				maxAge = 0
-				expiration = time.Now().addDate(0,0,-1)
-			} else {
-				maxAge = 24 * 60 * 60
-				expiration = time.Now().Add(24 * time.Hour)
+			logger := logging.FromContext(r.Context())
+
+			s := &requestSession{w: w, r: r}
+
+			sessionID, err := ReadSessionIDFromCookie(w, r)
+			if err == nil {
+				s.id = sessionID
+				s.userID, err = ReadFromRedis(r.Context(), map[string]string{"sessionID": sessionID})
 			}
 
-			if len(authenticatedUserID.id) == 0 {
-				c, err := r.Cookie("sid")
-
-				if c == nil || err != nil {
-					sessionID := map[string]string{
-						"sessionID": uuid.NewV4().String(),
-					}
-
-					err = WriteToRedis(sessionID, authenticatedUserID.id, expiration)
-					if err != nil {
-						log.Fatalln("Unable to write sessionID to Redis:", err)
-					}
-
-					persistedID, err = ReadFromRedis(sessionID)
-					if err != nil {
-						log.Fatalln("Unable to read sessionID from Redis:", err)
-					}
-
-					if persistedID == authenticatedUserID.id {
-						encoded, err := securecookie.EncodeMulti(
-							"sid",
-							sessionID,
-							validCookies[len(validCookies)-1],
-						)
-						if err != nil {
-							log.Fatalln("Failed to encode sessionID.")
-						}
-
-						cookie := &http.Cookie{
-							Name: "sid",
-							Value: encoded,
-							HttpOnly: true,
-							Path: "/",
-							MaxAge: maxAge,
-							Expires: expiration,
-						}
-
-						http.SetCookie(w, cookie)
-
-						ctx := context.WithValue(r.Context(), userIDCtxKey, authenticatedUserID.id)
-						r = r.WithContext(ctx)
-						next.ServeHTTP(w, r)
-						return
-					}
-				}
-				cookie, err := r.Cookie("sid")
-				if cookie == nil || err != nil {
-					log.Fatalln("Unable to find cookie for logged in User:", err)
-				}
-
-				sessionID := make(map[string]string)
-
-				err = securecookie.DecodeMulti("sid", cookie.Value, &sessionID, validCookies...)
-				if err != nil {
-					log.Fatalln("The session cookie has been tampered with:", err)
-				}
-
-				userID, err := ReadFromRedis(sessionID)
-				if err != nil {
-					log.Fatalln("Unable to read from Redis:", err)
-				}
-
-				err = WriteToRedis(sessionID, userID, expiration)
-				if err != nil {
-					log.Fatalln("Unable to write sessionID to Redis:", err)
-				}
-
-				persistedID, err := ReadFromRedis(sessionID)
-				if err != nil && del != true {
-					log.Fatalln("Unable to read userID from Redis:", err)
-				}
-
-				cookie.HttpOnly = true
-
-				if del == true {
-					cookie.Value = ""
-				}
-
-				cookie.Path = "/"
-				cookie.MaxAge = maxAge
-				cookie.Expires = expiration
-
-				http.SetCookie(w, cookie)
-
-				if del == true {
-					authenticatedUserID.id = ""
-					deleteSessionAndCookie.flag = false
-				} else {
-					authenticatedUserID.id = persistedID
-				}
-
-				ctx := context.WithValue(r.Context(), userIDCtxKey, authenticatedUserID.id)
-				r = r.WithContext(ctx)
-				next.ServeHTTP(w, r)
+			switch {
+			case s.id == "":
+				s.id = uuid.NewV4().String()
+				err = writeSession(r.Context(), w, r, s.id, "")
+			case err == redis.Nil:
+				sessionLookups.WithLabelValues("miss").Inc()
+				err = writeSession(r.Context(), w, r, s.id, "")
+			case err != nil:
+				// The session is left as it is, for it to be found again
+				// once Redis is back, and the request is anonymous.
+				sessionLookups.WithLabelValues("error").Inc()
+				logger.Error("Unable to read the session from Redis", zap.Error(err))
+				s.userID = ""
+				err = nil
+			default:
+				sessionLookups.WithLabelValues("hit").Inc()
+				err = writeSession(r.Context(), w, r, s.id, s.userID)
+			}
+			if err != nil {
+				logger.Error("Unable to write the session", zap.Error(err))
 			}
+
+			ctx := context.WithValue(r.Context(), userIDCtxKey, s.userID)
+			ctx = context.WithValue(ctx, sessionCtxKey, s)
+			ctx = audit.WithRequest(ctx, r, s.userID)
+			ctx = context.WithValue(ctx, responseWriterCtxKey, w)
+			ctx = withImpersonation(ctx, w, r, s.userID)
+			r = r.WithContext(ctx)
+			next.ServeHTTP(w, r)
 		})
 	}
 }
 
-func InsertUserID(userID string) {
-	authenticatedUserID.id = userID
+// LogIn binds a new session to the given user, in place of the session of the
+// request, so that a session ID known before logging in is worthless after.
+// The user is the one ForContext returns from the next request on.
+func LogIn(ctx context.Context, userID string) error {
+	s, ok := ctx.Value(sessionCtxKey).(*requestSession)
+	if !ok {
+		return errors.New("logging in requires the authentication middleware")
+	}
+
+	err := deleteSession(ctx, s.id, s.userID)
+	if err != nil {
+		return err
+	}
+
+	if s.userID != "" {
+		recordSessionEvent(ctx, s.userID, newSessionEvent(s.id, SessionLoggedOut, s.r))
+	}
+
+	s.id = uuid.NewV4().String()
+	s.userID = userID
+
+	err = writeSession(ctx, s.w, s.r, s.id, userID)
+	if err != nil {
+		return err
+	}
+
+	recordSessionEvent(ctx, userID, newSessionEvent(s.id, SessionCreated, s.r))
+
+	return nil
 }
 
-func SetLogOutFlag(value bool) {
-	deleteSessionAndCookie.flag = value
+// LogOut ends the session of the request, which is replaced by an anonymous
+// one.
+func LogOut(ctx context.Context) error {
+	s, ok := ctx.Value(sessionCtxKey).(*requestSession)
+	if !ok {
+		return errors.New("logging out requires the authentication middleware")
+	}
+
+	userID := s.userID
+
+	err := deleteSession(ctx, s.id, userID)
+	if err != nil {
+		return err
+	}
+
+	if userID != "" {
+		recordSessionEvent(ctx, userID, newSessionEvent(s.id, SessionLoggedOut, s.r))
+	}
+
+	s.id = uuid.NewV4().String()
+	s.userID = ""
+
+	err = writeSession(ctx, s.w, s.r, s.id, "")
+	if err != nil {
+		return err
+	}
+
+	if userID != "" {
+		audit.Record(ctx, audit.ActionLoggedOut, userID, pb.AuditOutcome_AUDIT_OUTCOME_SUCCESS, "")
+	}
+
+	return nil
 }
 
+// ForContext returns the user the request is made by, or "" when it is
+// anonymous.
 func ForContext(ctx context.Context) string {
-	// TODO: There should be error handling here.
 	raw, _ := ctx.Value(userIDCtxKey).(string)
 	return raw
-}
\ No newline at end of file
+}
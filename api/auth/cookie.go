@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CookieOptions are the attributes applied to every cookie set by the API.
+type CookieOptions struct {
+	Domain   string
+	Secure   bool
+	SameSite http.SameSite
+}
+
+// Production defaults are the strictest settings that still work for a
+// front end served from the same site as the API.
+var cookieOptions = CookieOptions{
+	Secure:   true,
+	SameSite: http.SameSiteStrictMode,
+}
+
+// SetCookieOptions replaces the attributes used for cookies set from now on.
+func SetCookieOptions(opts CookieOptions) {
+	cookieOptions = opts
+}
+
+// ParseSameSite reads a SameSite attribute: strict, lax or none.
+func ParseSameSite(v string) (http.SameSite, error) {
+	switch strings.ToLower(v) {
+	case "strict":
+		return http.SameSiteStrictMode, nil
+	case "lax":
+		return http.SameSiteLaxMode, nil
+	case "none":
+		return http.SameSiteNoneMode, nil
+	}
+
+	return http.SameSiteDefaultMode, errors.New("SameSite must be one of strict, lax or none")
+}
+
+func newCookie(name string, value string, httpOnly bool, maxAge int, expiration time.Time) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Domain:   cookieOptions.Domain,
+		MaxAge:   maxAge,
+		Expires:  expiration,
+		Secure:   cookieOptions.Secure,
+		HttpOnly: httpOnly,
+		SameSite: cookieOptions.SameSite,
+	}
+}
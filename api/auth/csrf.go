@@ -0,0 +1,187 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/allen-woods/the-supertask/api/audit"
+	pb "github.com/allen-woods/the-supertask/services/user/proto"
+	"github.com/gorilla/securecookie"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// CSRFHeader is the request header the front end must copy the CSRF cookie into.
+const CSRFHeader = "X-CSRF-Token"
+
+const csrfCookieName = "csrf"
+
+var csrfCtxKey = &contextKey{"csrf"}
+
+// csrfCheck records why a request failed CSRF validation, if it did.
+type csrfCheck struct {
+	reason string
+}
+
+// issueCSRFToken sets a double-submit token bound to the session. The cookie is
+// readable by JavaScript so the front end can echo it back in CSRFHeader.
+// A token that is still valid for the session is kept, so that concurrent
+// requests from the same tab do not race each other's tokens.
+func issueCSRFToken(w http.ResponseWriter, r *http.Request, sessionID map[string]string, maxAge int, expiration time.Time) error {
+	token := ""
+
+	if c, err := r.Cookie(csrfCookieName); err == nil && csrfTokenMatchesSession(c.Value, sessionID["sessionID"]) {
+		token = c.Value
+	} else {
+		nonce, err := GenerateRandomString(32)
+		if err != nil {
+			return err
+		}
+
+		token, err = securecookie.EncodeMulti(
+			csrfCookieName,
+			map[string]string{
+				"sessionID": sessionID["sessionID"],
+				"nonce":     nonce,
+			},
+			validCookies[len(validCookies)-1],
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	http.SetCookie(w, newCookie(csrfCookieName, token, false, maxAge, expiration))
+
+	return nil
+}
+
+func csrfTokenMatchesSession(token string, sessionID string) bool {
+	value := make(map[string]string)
+
+	err := securecookie.DecodeMulti(csrfCookieName, token, &value, validCookies...)
+	if err != nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(value["sessionID"]), []byte(sessionID)) == 1
+}
+
+// CSRFMiddleware checks the origin and double-submit token of every request and
+// stores the outcome in the context. Requests are not rejected here because
+// queries are safe to serve; CSRFOperationMiddleware rejects the mutations.
+func CSRFMiddleware(allowedOrigins []string) func(next http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, o := range allowedOrigins {
+		allowed[o] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			check := &csrfCheck{}
+
+			if !originAllowed(r, allowed) {
+				check.reason = "request origin is not allowed"
+			} else if !csrfTokenValid(r) {
+				check.reason = "missing or invalid CSRF token"
+			}
+
+			ctx := context.WithValue(r.Context(), csrfCtxKey, check)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// originAllowed accepts same-origin requests and those from the allowlist.
+// The Referer is only consulted when the browser did not send an Origin, and
+// requests carrying neither are not from a browser, so they cannot be forged.
+func originAllowed(r *http.Request, allowed map[string]bool) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		ref, err := url.Parse(r.Referer())
+		if err != nil || ref.Host == "" {
+			return r.Referer() == ""
+		}
+		origin = ref.Scheme + "://" + ref.Host
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	return u.Host == r.Host || allowed[origin]
+}
+
+func csrfTokenValid(r *http.Request) bool {
+	header := r.Header.Get(CSRFHeader)
+	if header == "" {
+		return false
+	}
+
+	c, err := r.Cookie(csrfCookieName)
+	if err != nil || subtle.ConstantTimeCompare([]byte(header), []byte(c.Value)) != 1 {
+		return false
+	}
+
+	sessionID, err := ReadSessionIDFromCookie(nil, r)
+	if err != nil {
+		return false
+	}
+
+	return csrfTokenMatchesSession(c.Value, sessionID)
+}
+
+// CSRFOperationMiddleware rejects mutations from requests that failed the checks
+// made by CSRFMiddleware.
+func CSRFOperationMiddleware(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	oc := graphql.GetOperationContext(ctx)
+	if oc.Operation == nil || oc.Operation.Operation != ast.Mutation {
+		return next(ctx)
+	}
+
+	check, ok := ctx.Value(csrfCtxKey).(*csrfCheck)
+	if !ok {
+		return graphql.OneShot(graphql.ErrorResponse(ctx, "CSRF protection is not configured"))
+	}
+
+	if check.reason != "" {
+		userID := ForContext(ctx)
+		audit.Record(ctx, audit.ActionCSRFRejected, userID, pb.AuditOutcome_AUDIT_OUTCOME_FAILURE, oc.OperationName+": "+check.reason)
+
+		return graphql.OneShot(graphql.ErrorResponse(ctx, "Forbidden: %s", check.reason))
+	}
+
+	return next(ctx)
+}
+
+// CSRFRequestMiddleware rejects the requests with unsafe methods that failed
+// the checks made by CSRFMiddleware, for the handlers that are not GraphQL.
+func CSRFRequestMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		check, ok := r.Context().Value(csrfCtxKey).(*csrfCheck)
+		if !ok {
+			http.Error(w, "CSRF protection is not configured", http.StatusInternalServerError)
+			return
+		}
+
+		if check.reason != "" {
+			userID := ForContext(r.Context())
+			audit.Record(r.Context(), audit.ActionCSRFRejected, userID, pb.AuditOutcome_AUDIT_OUTCOME_FAILURE, r.Method+" "+r.URL.Path+": "+check.reason)
+
+			http.Error(w, "Forbidden: "+check.reason, http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	"github.com/go-redis/redis"
+	"go.uber.org/zap"
+)
+
+// Events of the session history of a user.
+const (
+	SessionCreated   = "created"
+	SessionLoggedOut = "loggedOut"
+	SessionRevoked   = "revoked"
+)
+
+// sessionHistoryLength is how many events the session history of a user
+// keeps, the oldest being dropped first.
+const sessionHistoryLength = 200
+
+// sessionHistoryRetention is how long the session history of a user is kept
+// after its last event.
+const sessionHistoryRetention = 90 * 24 * time.Hour
+
+// SessionEvent is an event of the session history of a user. Sessions are
+// told apart by a label that does not reveal their ID.
+type SessionEvent struct {
+	Session   string    `json:"session"`
+	Event     string    `json:"event"`
+	Reason    string    `json:"reason,omitempty"`
+	At        time.Time `json:"at"`
+	IP        string    `json:"ip,omitempty"`
+	UserAgent string    `json:"userAgent,omitempty"`
+}
+
+// sessionHistoryKey is the Redis list of the session events of a user, newest
+// first.
+func sessionHistoryKey(userID string) string {
+	return "sessionHistory:" + userID
+}
+
+// sessionLabel tells the session with the given ID apart from the others of
+// its user.
+func sessionLabel(sessionID string) string {
+	sum := sha256.Sum256([]byte(sessionID))
+	return hex.EncodeToString(sum[:8])
+}
+
+// newSessionEvent describes event of the session with the given ID, made
+// through r unless it is nil.
+func newSessionEvent(sessionID string, event string, r *http.Request) SessionEvent {
+	e := SessionEvent{
+		Session: sessionLabel(sessionID),
+		Event:   event,
+		At:      time.Now().UTC().Truncate(time.Second),
+	}
+
+	if r != nil {
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+
+		e.IP = ip
+		e.UserAgent = r.UserAgent()
+	}
+
+	return e
+}
+
+// recordSessionEvent adds e to the session history of the given user. Failing
+// to do so does not fail what happened to the session, so errors are only
+// logged.
+func recordSessionEvent(ctx context.Context, userID string, e SessionEvent) {
+	_, span := startRedisSpan(ctx, "RecordSessionEvent")
+
+	data, err := json.Marshal(e)
+	if err == nil {
+		_, err = RedisClient().TxPipelined(func(pipe redis.Pipeliner) error {
+			pipe.LPush(sessionHistoryKey(userID), data)
+			pipe.LTrim(sessionHistoryKey(userID), 0, sessionHistoryLength-1)
+			pipe.Expire(sessionHistoryKey(userID), sessionHistoryRetention)
+			return nil
+		})
+	}
+
+	endRedisSpan(span, err)
+
+	if err != nil {
+		logging.FromContext(ctx).Warn("Unable to record a session event", zap.String("event", e.Event), zap.Error(err))
+	}
+}
+
+// SessionHistory returns the session events of the given user, oldest first.
+func SessionHistory(ctx context.Context, userID string) (events []SessionEvent, err error) {
+	_, span := startRedisSpan(ctx, "ListSessionEvents")
+	defer func() { endRedisSpan(span, err) }()
+
+	values, err := RedisClient().LRange(sessionHistoryKey(userID), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	events = make([]SessionEvent, len(values))
+
+	for i, value := range values {
+		err := json.Unmarshal([]byte(value), &events[len(values)-1-i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return events, nil
+}
+
+// DeleteSessionHistory removes the session history of the given user, such as
+// when their account is purged.
+func DeleteSessionHistory(ctx context.Context, userID string) (err error) {
+	_, span := startRedisSpan(ctx, "DeleteSessionEvents")
+	defer func() { endRedisSpan(span, err) }()
+
+	return RedisClient().Del(sessionHistoryKey(userID)).Err()
+}
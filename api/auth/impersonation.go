@@ -0,0 +1,200 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/allen-woods/the-supertask/api/audit"
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	pb "github.com/allen-woods/the-supertask/services/user/proto"
+	"github.com/go-redis/redis"
+	"github.com/gorilla/securecookie"
+	uuid "github.com/satori/go.uuid"
+	"go.uber.org/zap"
+)
+
+// DefaultImpersonationDuration is how long an impersonation lasts, unless
+// SetImpersonationDuration says otherwise. It cannot be extended.
+const DefaultImpersonationDuration = 30 * time.Minute
+
+// An impersonation is a session of its own, kept next to the session of the
+// admin in a cookie of its own. It ends when either of them does.
+const impersonationCookieName = "isid"
+
+var impersonationDuration = DefaultImpersonationDuration
+
+var impersonationCtxKey = &contextKey{"impersonation"}
+var responseWriterCtxKey = &contextKey{"responseWriter"}
+
+// ErrImpersonating is returned for what cannot be done while impersonating.
+var ErrImpersonating = errors.New("not allowed while impersonating a user")
+
+// SetImpersonationDuration sets how long impersonations started from now on
+// last.
+func SetImpersonationDuration(d time.Duration) {
+	impersonationDuration = d
+}
+
+// Impersonation describes an admin acting as another user.
+type Impersonation struct {
+	ImpersonatorID string
+	UserID         string
+	Reason         string
+	EndsAt         time.Time
+
+	key string
+}
+
+func impersonationKey(id string) string {
+	return "impersonation:" + id
+}
+
+// ImpersonationForContext returns the impersonation the request is made under,
+// or nil. ForContext returns the impersonated user during one.
+func ImpersonationForContext(ctx context.Context) *Impersonation {
+	raw, _ := ctx.Value(impersonationCtxKey).(*Impersonation)
+	return raw
+}
+
+// withImpersonation makes the user impersonated through the request, if any,
+// the one ForContext returns. The impersonation only holds while the request
+// is also authenticated as the admin who started it; the auditing context
+// keeps the admin as the actor.
+func withImpersonation(ctx context.Context, w http.ResponseWriter, r *http.Request, userID string) context.Context {
+	cookie, err := r.Cookie(impersonationCookieName)
+	if err != nil {
+		return ctx
+	}
+
+	value := make(map[string]string)
+
+	err = securecookie.DecodeMulti(impersonationCookieName, cookie.Value, &value, validCookies...)
+	if err != nil {
+		clearImpersonationCookie(w)
+		return ctx
+	}
+
+	client := RedisClient()
+
+	fields, err := client.HGetAll(impersonationKey(value["id"])).Result()
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to read impersonation from Redis", zap.Error(err))
+		return ctx
+	}
+
+	if len(fields) == 0 || userID == "" || fields["impersonatorID"] != userID {
+		clearImpersonationCookie(w)
+		return ctx
+	}
+
+	endsAt, err := time.Parse(time.RFC3339, fields["endsAt"])
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to parse the end of an impersonation", zap.Error(err))
+		return ctx
+	}
+
+	i := &Impersonation{
+		ImpersonatorID: userID,
+		UserID:         fields["userID"],
+		Reason:         fields["reason"],
+		EndsAt:         endsAt,
+		key:            impersonationKey(value["id"]),
+	}
+
+	ctx = context.WithValue(ctx, userIDCtxKey, i.UserID)
+	ctx = context.WithValue(ctx, impersonationCtxKey, i)
+
+	return ctx
+}
+
+// StartImpersonation has the admin behind ctx act as the given user until
+// the returned impersonation ends or is stopped.
+func StartImpersonation(ctx context.Context, userID string, reason string) (*Impersonation, error) {
+	if ImpersonationForContext(ctx) != nil {
+		return nil, ErrImpersonating
+	}
+
+	adminID := ForContext(ctx)
+	if adminID == "" {
+		return nil, errors.New("not authenticated")
+	}
+	if adminID == userID {
+		return nil, errors.New("cannot impersonate yourself")
+	}
+
+	w, ok := ctx.Value(responseWriterCtxKey).(http.ResponseWriter)
+	if !ok {
+		return nil, errors.New("impersonation requires the authentication middleware")
+	}
+
+	id := uuid.NewV4().String()
+
+	i := &Impersonation{
+		ImpersonatorID: adminID,
+		UserID:         userID,
+		Reason:         reason,
+		EndsAt:         time.Now().Add(impersonationDuration).UTC().Truncate(time.Second),
+		key:            impersonationKey(id),
+	}
+
+	client := RedisClient()
+
+	_, err := client.HMSet(i.key, map[string]interface{}{
+		"impersonatorID": i.ImpersonatorID,
+		"userID":         i.UserID,
+		"reason":         i.Reason,
+		"endsAt":         i.EndsAt.Format(time.RFC3339),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = client.ExpireAt(i.key, i.EndsAt).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := securecookie.EncodeMulti(
+		impersonationCookieName,
+		map[string]string{"id": id},
+		validCookies[len(validCookies)-1],
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	http.SetCookie(w, newCookie(impersonationCookieName, encoded, true, int(time.Until(i.EndsAt).Seconds()), i.EndsAt))
+
+	audit.Record(ctx, audit.ActionImpersonationStarted, userID, pb.AuditOutcome_AUDIT_OUTCOME_SUCCESS, "Reason: "+reason+" (until "+i.EndsAt.Format(time.RFC3339)+")")
+
+	return i, nil
+}
+
+// StopImpersonation ends the impersonation the request is made under, if any.
+func StopImpersonation(ctx context.Context) error {
+	i := ImpersonationForContext(ctx)
+	if i == nil {
+		return nil
+	}
+
+	client := RedisClient()
+
+	_, err := client.Del(i.key).Result()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+
+	if w, ok := ctx.Value(responseWriterCtxKey).(http.ResponseWriter); ok {
+		clearImpersonationCookie(w)
+	}
+
+	audit.Record(ctx, audit.ActionImpersonationStopped, i.UserID, pb.AuditOutcome_AUDIT_OUTCOME_SUCCESS, "")
+
+	return nil
+}
+
+func clearImpersonationCookie(w http.ResponseWriter) {
+	http.SetCookie(w, newCookie(impersonationCookieName, "", true, -1, time.Unix(0, 0)))
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/go-redis/redis"
+	"github.com/gorilla/securecookie"
+)
+
+// SessionTokenKey is the key of the connection init payload under which
+// WebSocket clients that cannot send cookies pass the value of the session
+// cookie instead.
+const SessionTokenKey = "sessionToken"
+
+// errInvalidSession is returned for a session token that does not decode to a
+// session that is still live.
+var errInvalidSession = errors.New("invalid or expired session")
+
+// WebsocketInit authenticates a GraphQL WebSocket connection when it is
+// initialized. The user is the one of the session cookie of the upgrade
+// request, as set by Middleware, unless the init payload carries a session
+// token, which then takes precedence.
+func WebsocketInit(ctx context.Context, payload transport.InitPayload) (context.Context, error) {
+	token := payload.GetString(SessionTokenKey)
+	if token == "" {
+		return ctx, nil
+	}
+
+	sessionID := make(map[string]string)
+
+	err := securecookie.DecodeMulti("sid", token, &sessionID, validCookies...)
+	if err != nil {
+		return nil, errInvalidSession
+	}
+
+	userID, err := ReadFromRedis(ctx, sessionID)
+	if err == redis.Nil || (err == nil && userID == "") {
+		return nil, errInvalidSession
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The connection acts as the user of the token, without the
+	// impersonation the upgrade request may have been made under.
+	ctx = context.WithValue(ctx, userIDCtxKey, userID)
+	ctx = context.WithValue(ctx, impersonationCtxKey, (*Impersonation)(nil))
+
+	return ctx, nil
+}
+
+// WebsocketOriginChecker accepts WebSocket upgrades from the same origin and
+// those from the allowlist, as CSRFMiddleware does, for browsers do not
+// restrict cross-origin WebSockets themselves.
+func WebsocketOriginChecker(allowedOrigins []string) func(r *http.Request) bool {
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, o := range allowedOrigins {
+		allowed[o] = true
+	}
+
+	return func(r *http.Request) bool {
+		return originAllowed(r, allowed)
+	}
+}
@@ -0,0 +1,135 @@
+// Package avatar turns the images users upload into the pictures of their
+// profile: square JPEGs of a few sizes, stripped of metadata, kept in blob
+// storage under the ID of the avatar so that a new one never shows the
+// cached images of the last.
+package avatar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/allen-woods/the-supertask/api/blob"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel"
+)
+
+// Sizes of the images of an avatar, in pixels along each side.
+const (
+	SizeSmall  = 64
+	SizeMedium = 256
+	SizeLarge  = 512
+)
+
+// Sizes are every size an avatar is stored in.
+var Sizes = []int{SizeSmall, SizeMedium, SizeLarge}
+
+// DefaultMaxSize is the size of the largest upload accepted, in bytes, unless
+// SetMaxSize says otherwise.
+const DefaultMaxSize = 5 << 20
+
+// tracerName names the spans of the processing of images.
+const tracerName = "github.com/allen-woods/the-supertask/api/avatar"
+
+// ErrTooLarge is returned for uploads over the maximum size.
+var ErrTooLarge = errors.New("the avatar file is too large")
+
+var (
+	store     blob.Store
+	publicURL = blob.PathPrefix
+	maxSize   = int64(DefaultMaxSize)
+)
+
+// processing bounds how many images are decoded at once, as each may take
+// hundreds of megabytes.
+var processing = make(chan struct{}, 2)
+
+// SetStore sets where the images of avatars are stored from now on.
+func SetStore(s blob.Store) {
+	store = s
+}
+
+// SetPublicURL sets the URL the blobs are served from, which the URLs of the
+// images of avatars start with.
+func SetPublicURL(url string) {
+	if !strings.HasSuffix(url, "/") {
+		url += "/"
+	}
+	publicURL = url
+}
+
+// SetMaxSize sets the size of the largest upload accepted from now on.
+func SetMaxSize(n int64) {
+	maxSize = n
+}
+
+// MaxSize is the size of the largest upload accepted.
+func MaxSize() int64 {
+	return maxSize
+}
+
+func prefix(userID string) string {
+	return "avatars/" + userID + "/"
+}
+
+func key(userID string, id string, size int) string {
+	return fmt.Sprintf("%s%s/%d.jpg", prefix(userID), id, size)
+}
+
+// URL is where the image of the given size of an avatar is served.
+func URL(userID string, id string, size int) string {
+	return publicURL + key(userID, id, size)
+}
+
+// Save stores the image read from r as a new avatar of the user, in every
+// size, and returns its ID. Images that are too large, or not of an accepted
+// type, are refused with an error that can be shown to the user.
+func Save(ctx context.Context, userID string, r io.Reader) (string, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(data)) > maxSize {
+		return "", ErrTooLarge
+	}
+
+	select {
+	case processing <- struct{}{}:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+
+	_, span := otel.Tracer(tracerName).Start(ctx, "process")
+	variants, err := process(data, Sizes)
+	span.End()
+	<-processing
+	if err != nil {
+		return "", err
+	}
+
+	id := primitive.NewObjectID().Hex()
+
+	for size, image := range variants {
+		err := store.Put(ctx, key(userID, id, size), image, "image/jpeg")
+		if err != nil {
+			// Leave no image of an avatar that was never saved.
+			Delete(ctx, userID, id)
+			return "", err
+		}
+	}
+
+	return id, nil
+}
+
+// Delete removes the images of an avatar of the user.
+func Delete(ctx context.Context, userID string, id string) error {
+	return store.DeletePrefix(ctx, prefix(userID)+id+"/")
+}
+
+// DeleteAll removes the images of every avatar of the user.
+func DeleteAll(ctx context.Context, userID string) error {
+	return store.DeletePrefix(ctx, prefix(userID))
+}
@@ -0,0 +1,149 @@
+package avatar
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"net/http"
+
+	// Decoders of the accepted formats.
+	_ "image/gif"
+	_ "image/png"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// maxPixels bounds the dimensions of the images decoded, so that a small
+// file cannot claim gigabytes of memory once decoded.
+const maxPixels = 40 * 1000 * 1000
+
+// jpegQuality is the quality the images of avatars are encoded with.
+const jpegQuality = 85
+
+// acceptedTypes are the content types of the images accepted, as sniffed
+// from their first bytes.
+var acceptedTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// Errors returned for images that are not accepted, which are safe to show
+// to users.
+var (
+	ErrUnsupportedType = errors.New("the avatar must be a JPEG, PNG or GIF image")
+	ErrTooManyPixels   = errors.New("the avatar has too many pixels")
+	ErrInvalidImage    = errors.New("the avatar is not a valid image")
+)
+
+// process decodes the image in data and returns it as a square JPEG of each
+// size, keyed by size. Only the pixels are encoded, so metadata such as the
+// location of a photo is left behind; the orientation it gives is applied
+// first.
+func process(data []byte, sizes []int) (map[int][]byte, error) {
+	if !acceptedTypes[http.DetectContentType(data)] {
+		return nil, ErrUnsupportedType
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	if format == "jpeg" {
+		src = orient(src, jpegOrientation(data))
+	}
+
+	square := centerSquare(src)
+
+	variants := make(map[int][]byte, len(sizes))
+
+	for _, size := range sizes {
+		// JPEG has no transparency, so images are laid over white.
+		dst := image.NewRGBA(image.Rect(0, 0, size, size))
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, square, draw.Over, nil)
+
+		var buf bytes.Buffer
+		err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: jpegQuality})
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode the %dpx avatar: %w", size, err)
+		}
+
+		variants[size] = buf.Bytes()
+	}
+
+	return variants, nil
+}
+
+// centerSquare is the largest square at the center of the image.
+func centerSquare(img image.Image) image.Rectangle {
+	b := img.Bounds()
+
+	side := b.Dx()
+	if b.Dy() < side {
+		side = b.Dy()
+	}
+
+	x := b.Min.X + (b.Dx()-side)/2
+	y := b.Min.Y + (b.Dy()-side)/2
+
+	return image.Rect(x, y, x+side, y+side)
+}
+
+// orient turns the image as an EXIF orientation says it is to be displayed.
+func orient(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	// Orientations 5 to 8 swap the width and the height.
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+
+			switch orientation {
+			case 2: // Mirrored horizontally.
+				dx, dy = w-1-x, y
+			case 3: // Rotated 180°.
+				dx, dy = w-1-x, h-1-y
+			case 4: // Mirrored vertically.
+				dx, dy = x, h-1-y
+			case 5: // Mirrored along the top left diagonal.
+				dx, dy = y, x
+			case 6: // Rotated 90° clockwise.
+				dx, dy = h-1-y, x
+			case 7: // Mirrored along the top right diagonal.
+				dx, dy = h-1-y, w-1-x
+			case 8: // Rotated 90° counterclockwise.
+				dx, dy = y, w-1-x
+			}
+
+			dst.Set(dx, dy, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+
+	return dst
+}
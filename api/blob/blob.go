@@ -0,0 +1,68 @@
+// Package blob stores the files the API keeps, such as the images of avatars,
+// by key: on the local filesystem, from which the API serves them itself, or
+// in an S3 compatible bucket, such as MinIO.
+//
+// Keys are slash separated paths. They are never reused for other content,
+// so blobs are served as immutable.
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// PathPrefix is where the API serves the blobs of local storage.
+const PathPrefix = "/blobs/"
+
+// CacheControl is the Cache-Control of the blobs as served.
+const CacheControl = "public, max-age=31536000, immutable"
+
+// PrivatePrefix starts the keys of the blobs that are only ever served through
+// the API to whom they belong, such as data exports. Local storage never
+// serves them, and buckets must not grant public access to them either.
+const PrivatePrefix = "private/"
+
+// privateCacheControl is the Cache-Control of private blobs.
+const privateCacheControl = "private, no-store"
+
+// ErrNotFound is returned when no blob has the requested key.
+var ErrNotFound = errors.New("blob not found")
+
+// Store stores blobs by key.
+type Store interface {
+	// Put stores data under key, replacing any blob there.
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// PutReader stores what is read from r under key, like Put, without
+	// holding all of it in memory. Nothing is stored when reading r fails.
+	PutReader(ctx context.Context, key string, r io.Reader, contentType string) error
+	// Get returns the blob under key and its content type.
+	Get(ctx context.Context, key string) ([]byte, string, error)
+	// Open returns a reader of the blob under key, which the caller must
+	// close, and its content type.
+	Open(ctx context.Context, key string) (io.ReadCloser, string, error)
+	// Delete removes the blob under key, if any.
+	Delete(ctx context.Context, key string) error
+	// DeletePrefix removes every blob whose key starts with prefix.
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// validKey checks that key is a clean relative path, so that it cannot escape
+// the directory or bucket it is stored in.
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	return nil
+}
+
+// cacheControl is the Cache-Control of the blob under key.
+func cacheControl(key string) string {
+	if strings.HasPrefix(key, PrivatePrefix) {
+		return privateCacheControl
+	}
+	return CacheControl
+}
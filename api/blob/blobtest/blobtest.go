@@ -0,0 +1,233 @@
+// Package blobtest checks that implementations of blob.Store have the
+// semantics the API relies on, so that every storage behaves the same.
+package blobtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/allen-woods/the-supertask/api/blob"
+)
+
+// checkTimeout bounds each check, so that an unreachable store fails it rather
+// than hanging.
+const checkTimeout = time.Minute
+
+// TestStore runs every check of a blob store as a subtest of t, each against
+// a new, empty store returned by newStore.
+func TestStore(t *testing.T, newStore func(t *testing.T) blob.Store) {
+	checks := []struct {
+		name  string
+		check func(*testing.T, context.Context, blob.Store)
+	}{
+		{"put and get", checkPutAndGet},
+		{"put reader and open", checkPutReaderAndOpen},
+		{"replace", checkReplace},
+		{"delete", checkDelete},
+		{"delete prefix", checkDeletePrefix},
+		{"invalid keys", checkInvalidKeys},
+	}
+
+	for _, c := range checks {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+			defer cancel()
+
+			c.check(t, ctx, newStore(t))
+		})
+	}
+}
+
+// wantBlob checks that the blob under key holds data.
+func wantBlob(t *testing.T, ctx context.Context, s blob.Store, key string, data []byte) {
+	t.Helper()
+
+	got, _, err := s.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get %s: %v", key, err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("Get %s returned %q, want %q", key, got, data)
+	}
+}
+
+// wantNone checks that no blob is under key.
+func wantNone(t *testing.T, ctx context.Context, s blob.Store, key string) {
+	t.Helper()
+
+	_, _, err := s.Get(ctx, key)
+	if err != blob.ErrNotFound {
+		t.Fatalf("Get %s returned %v, want ErrNotFound", key, err)
+	}
+}
+
+func checkPutAndGet(t *testing.T, ctx context.Context, s blob.Store) {
+	data := []byte("\xff\xd8\xff not quite a JPEG")
+
+	err := s.Put(ctx, "a/b/c.jpg", data, "image/jpeg")
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	got, contentType, err := s.Get(ctx, "a/b/c.jpg")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("Get returned %q, want %q", got, data)
+	}
+	if contentType != "image/jpeg" {
+		t.Fatalf("Get returned content type %q, want image/jpeg", contentType)
+	}
+
+	wantNone(t, ctx, s, "a/b/missing.jpg")
+}
+
+// failingReader returns some data, then fails.
+type failingReader struct {
+	read bool
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.read {
+		return 0, errors.New("read failed")
+	}
+	r.read = true
+	return copy(p, "partial"), nil
+}
+
+// readBlob reads the blob under key through Open.
+func readBlob(t *testing.T, ctx context.Context, s blob.Store, key string) ([]byte, string) {
+	t.Helper()
+
+	r, contentType, err := s.Open(ctx, key)
+	if err != nil {
+		t.Fatalf("Open %s: %v", key, err)
+	}
+	defer r.Close()
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("reading what Open %s returned: %v", key, err)
+	}
+
+	return data, contentType
+}
+
+func checkPutReaderAndOpen(t *testing.T, ctx context.Context, s blob.Store) {
+	small := []byte("PK\x03\x04 not quite a zip")
+	// Larger than a part of a multipart upload. The fake of S3 drops the
+	// content type of those, so only the content is compared.
+	large := bytes.Repeat([]byte("0123456789abcdef"), 6<<20/16)
+
+	for key, data := range map[string][]byte{"a/b/small.zip": small, "a/b/large.zip": large} {
+		err := s.PutReader(ctx, key, bytes.NewReader(data), "application/zip")
+		if err != nil {
+			t.Fatalf("PutReader %s: %v", key, err)
+		}
+
+		got, _ := readBlob(t, ctx, s, key)
+		if !bytes.Equal(got, data) {
+			t.Fatalf("Open %s returned %d bytes, not the %d put", key, len(got), len(data))
+		}
+	}
+
+	_, contentType := readBlob(t, ctx, s, "a/b/small.zip")
+	if contentType != "application/zip" {
+		t.Fatalf("Open returned content type %q, want application/zip", contentType)
+	}
+
+	_, _, err := s.Open(ctx, "a/b/missing.zip")
+	if err != blob.ErrNotFound {
+		t.Fatalf("Open of a missing blob returned %v, want ErrNotFound", err)
+	}
+
+	err = s.PutReader(ctx, "failed.zip", &failingReader{}, "application/zip")
+	if err == nil {
+		t.Fatal("PutReader of a failing reader succeeded")
+	}
+
+	wantNone(t, ctx, s, "failed.zip")
+}
+
+func checkReplace(t *testing.T, ctx context.Context, s blob.Store) {
+	for _, data := range []string{"first", "second"} {
+		err := s.Put(ctx, "replaced.jpg", []byte(data), "image/jpeg")
+		if err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+
+	wantBlob(t, ctx, s, "replaced.jpg", []byte("second"))
+}
+
+func checkDelete(t *testing.T, ctx context.Context, s blob.Store) {
+	err := s.Put(ctx, "deleted.jpg", []byte("data"), "image/jpeg")
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	err = s.Delete(ctx, "deleted.jpg")
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	wantNone(t, ctx, s, "deleted.jpg")
+
+	err = s.Delete(ctx, "deleted.jpg")
+	if err != nil {
+		t.Fatalf("Delete of a missing blob: %v", err)
+	}
+}
+
+func checkDeletePrefix(t *testing.T, ctx context.Context, s blob.Store) {
+	deleted := []string{"users/1/a/small.jpg", "users/1/a/large.jpg", "users/1/b/small.jpg"}
+	kept := []string{"users/10/a/small.jpg", "users/2/a/small.jpg", "other.jpg"}
+
+	for _, key := range append(append([]string{}, deleted...), kept...) {
+		err := s.Put(ctx, key, []byte(key), "image/jpeg")
+		if err != nil {
+			t.Fatalf("Put %s: %v", key, err)
+		}
+	}
+
+	err := s.DeletePrefix(ctx, "users/1/")
+	if err != nil {
+		t.Fatalf("DeletePrefix: %v", err)
+	}
+
+	var left []string
+	for _, key := range deleted {
+		if _, _, err := s.Get(ctx, key); err != blob.ErrNotFound {
+			left = append(left, key)
+		}
+	}
+	if len(left) > 0 {
+		sort.Strings(left)
+		t.Fatalf("DeletePrefix left %s", strings.Join(left, ", "))
+	}
+
+	for _, key := range kept {
+		wantBlob(t, ctx, s, key, []byte(key))
+	}
+
+	err = s.DeletePrefix(ctx, "users/1/")
+	if err != nil {
+		t.Fatalf("DeletePrefix of nothing: %v", err)
+	}
+}
+
+func checkInvalidKeys(t *testing.T, ctx context.Context, s blob.Store) {
+	for _, key := range []string{"", "/absolute.jpg", "../escape.jpg", "a/../../escape.jpg", "a//b.jpg"} {
+		if err := s.Put(ctx, key, []byte("data"), "image/jpeg"); err == nil {
+			t.Errorf("Put accepted the key %q", key)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Local stores blobs as files under a directory. The content type of a blob
+// is had from the extension of its key.
+type Local struct {
+	dir string
+}
+
+// NewLocal returns a store of the blobs under dir, which is created if need
+// be.
+func NewLocal(dir string) (*Local, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Local{dir: filepath.Clean(dir)}, nil
+}
+
+func (l *Local) path(key string) string {
+	return filepath.Join(l.dir, filepath.FromSlash(key))
+}
+
+// Put writes data to the file of key, through a temporary file so that the
+// blob is never seen half written.
+func (l *Local) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	return l.PutReader(ctx, key, bytes.NewReader(data), contentType)
+}
+
+// PutReader copies r to the file of key, through a temporary file like Put.
+func (l *Local) PutReader(ctx context.Context, key string, r io.Reader, contentType string) error {
+	err := validKey(key)
+	if err != nil {
+		return err
+	}
+
+	p := l.path(key)
+
+	err = os.MkdirAll(filepath.Dir(p), 0755)
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(p), ".blob-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = io.Copy(f, r)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(f.Name(), 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), p)
+}
+
+// Get reads the file of key.
+func (l *Local) Get(ctx context.Context, key string) ([]byte, string, error) {
+	err := validKey(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	data, err := ioutil.ReadFile(l.path(key))
+	if os.IsNotExist(err) {
+		return nil, "", ErrNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, mime.TypeByExtension(path.Ext(key)), nil
+}
+
+// Open opens the file of key.
+func (l *Local) Open(ctx context.Context, key string) (io.ReadCloser, string, error) {
+	err := validKey(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	f, err := os.Open(l.path(key))
+	if os.IsNotExist(err) {
+		return nil, "", ErrNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return f, mime.TypeByExtension(path.Ext(key)), nil
+}
+
+// Delete removes the file of key.
+func (l *Local) Delete(ctx context.Context, key string) error {
+	err := validKey(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(l.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+// DeletePrefix removes the files whose key starts with prefix. Directories
+// left empty are removed too.
+func (l *Local) DeletePrefix(ctx context.Context, prefix string) error {
+	return filepath.Walk(l.dir, func(p string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(l.dir, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+
+		if info.IsDir() {
+			// Only directories that may hold matching keys are walked.
+			if key != "." && !strings.HasPrefix(key+"/", prefix) && !strings.HasPrefix(prefix, key+"/") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		err = os.Remove(p)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		l.removeEmptyDirs(filepath.Dir(p))
+		return nil
+	})
+}
+
+// removeEmptyDirs removes dir and its parents, up to the root of the store,
+// for as long as they are empty.
+func (l *Local) removeEmptyDirs(dir string) {
+	for dir != l.dir && strings.HasPrefix(dir, l.dir) {
+		if os.Remove(dir) != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// Handler serves the blobs under PathPrefix, but for private ones.
+// Directories are not listed.
+func (l *Local) Handler() http.Handler {
+	files := http.StripPrefix(PathPrefix, http.FileServer(http.Dir(l.dir)))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The file server cleans the path, so it is checked once cleaned.
+		key := strings.TrimPrefix(path.Clean("/"+strings.TrimPrefix(r.URL.Path, PathPrefix)), "/")
+
+		if strings.HasSuffix(r.URL.Path, "/") || strings.HasPrefix(key+"/", PrivatePrefix) {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Cache-Control", CacheControl)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		files.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,77 @@
+package blob_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/allen-woods/the-supertask/api/blob"
+	"github.com/allen-woods/the-supertask/api/blob/blobtest"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// tempDir returns a temporary directory, and a function removing it.
+func tempDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "blobtest")
+	if err != nil {
+		t.Fatalf("Could not create a temporary directory: %v", err)
+	}
+
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func TestLocal(t *testing.T) {
+	root, remove := tempDir(t)
+	defer remove()
+
+	blobtest.TestStore(t, func(t *testing.T) blob.Store {
+		l, err := blob.NewLocal(filepath.Join(root, primitive.NewObjectID().Hex()))
+		if err != nil {
+			t.Fatalf("Could not create store: %v", err)
+		}
+
+		return l
+	})
+}
+
+func TestLocalHandler(t *testing.T) {
+	dir, remove := tempDir(t)
+	defer remove()
+
+	l, err := blob.NewLocal(dir)
+	if err != nil {
+		t.Fatalf("Could not create store: %v", err)
+	}
+
+	for key, contentType := range map[string]string{"avatars/a.jpg": "image/jpeg", blob.PrivatePrefix + "exports/a.zip": "application/zip"} {
+		err := l.Put(context.Background(), key, []byte("data"), contentType)
+		if err != nil {
+			t.Fatalf("Put %s: %v", key, err)
+		}
+	}
+
+	cases := []struct {
+		path string
+		want int
+	}{
+		{blob.PathPrefix + "avatars/a.jpg", http.StatusOK},
+		{blob.PathPrefix + "avatars/missing.jpg", http.StatusNotFound},
+		{blob.PathPrefix + "avatars/", http.StatusNotFound},
+		{blob.PathPrefix + blob.PrivatePrefix + "exports/a.zip", http.StatusNotFound},
+		{blob.PathPrefix + "avatars/../" + blob.PrivatePrefix + "exports/a.zip", http.StatusNotFound},
+		{blob.PathPrefix + "private", http.StatusNotFound},
+	}
+
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		l.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, c.path, nil))
+
+		if w.Code != c.want {
+			t.Errorf("GET %s returned %d, want %d", c.path, w.Code, c.want)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// S3Options locate and authenticate an S3 compatible bucket.
+type S3Options struct {
+	// Endpoint is the URL of the S3 API, such as http://localhost:9000 for
+	// MinIO; empty for AWS.
+	Endpoint string
+	Region   string
+	Bucket   string
+	// AccessKey and SecretKey authenticate the requests; the default
+	// credentials of the AWS SDK are used when both are empty.
+	AccessKey string
+	SecretKey string
+	// PathStyle addresses the bucket in the path of URLs rather than in
+	// their host, as MinIO expects.
+	PathStyle bool
+}
+
+// S3 stores blobs as the objects of a bucket.
+type S3 struct {
+	client *s3.S3
+	bucket string
+}
+
+// NewS3 returns a store of the blobs in the bucket. The bucket is not reached
+// until used.
+func NewS3(opts S3Options) (*S3, error) {
+	cfg := aws.NewConfig().
+		WithRegion(opts.Region).
+		WithS3ForcePathStyle(opts.PathStyle)
+
+	if opts.Endpoint != "" {
+		cfg = cfg.WithEndpoint(opts.Endpoint)
+	}
+	if opts.AccessKey != "" || opts.SecretKey != "" {
+		cfg = cfg.WithCredentials(credentials.NewStaticCredentials(opts.AccessKey, opts.SecretKey, ""))
+	}
+
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3{client: s3.New(sess), bucket: opts.Bucket}, nil
+}
+
+// EnsureBucket creates the bucket unless it exists, for development and
+// conformance checks; production buckets are provisioned beforehand.
+func (s *S3) EnsureBucket(ctx context.Context) error {
+	_, err := s.client.HeadBucketWithContext(ctx, &s3.HeadBucketInput{Bucket: aws.String(s.bucket)})
+	if err == nil {
+		return nil
+	}
+
+	_, err = s.client.CreateBucketWithContext(ctx, &s3.CreateBucketInput{Bucket: aws.String(s.bucket)})
+	return err
+}
+
+// DeleteBucket empties the bucket and deletes it, for conformance checks.
+func (s *S3) DeleteBucket(ctx context.Context) error {
+	err := s.DeletePrefix(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.DeleteBucketWithContext(ctx, &s3.DeleteBucketInput{Bucket: aws.String(s.bucket)})
+	return err
+}
+
+// Put uploads data as the object of key.
+func (s *S3) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	err := validKey(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:       aws.String(s.bucket),
+		Key:          aws.String(key),
+		Body:         bytes.NewReader(data),
+		ContentType:  aws.String(contentType),
+		CacheControl: aws.String(cacheControl(key)),
+	})
+
+	return err
+}
+
+// PutReader uploads what is read from r as the object of key, in parts, as
+// its size is not known beforehand. The upload is aborted when reading fails.
+func (s *S3) PutReader(ctx context.Context, key string, r io.Reader, contentType string) error {
+	err := validKey(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = s3manager.NewUploaderWithClient(s.client).UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket:       aws.String(s.bucket),
+		Key:          aws.String(key),
+		Body:         r,
+		ContentType:  aws.String(contentType),
+		CacheControl: aws.String(cacheControl(key)),
+	})
+
+	return err
+}
+
+// Get downloads the object of key.
+func (s *S3) Get(ctx context.Context, key string) ([]byte, string, error) {
+	err := validKey(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	out, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if isNotFound(err) {
+		return nil, "", ErrNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	defer out.Body.Close()
+
+	data, err := ioutil.ReadAll(out.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, aws.StringValue(out.ContentType), nil
+}
+
+// Open downloads the object of key as it is read.
+func (s *S3) Open(ctx context.Context, key string) (io.ReadCloser, string, error) {
+	err := validKey(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	out, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if isNotFound(err) {
+		return nil, "", ErrNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return out.Body, aws.StringValue(out.ContentType), nil
+}
+
+// Delete removes the object of key. Deleting a missing object succeeds.
+func (s *S3) Delete(ctx context.Context, key string) error {
+	err := validKey(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+
+	return err
+}
+
+// DeletePrefix removes the objects whose key starts with prefix, a page of
+// them at a time.
+func (s *S3) DeletePrefix(ctx context.Context, prefix string) error {
+	var deleteErr error
+
+	err := s.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, last bool) bool {
+		if len(page.Contents) == 0 {
+			return true
+		}
+
+		objects := make([]*s3.ObjectIdentifier, 0, len(page.Contents))
+		for _, o := range page.Contents {
+			objects = append(objects, &s3.ObjectIdentifier{Key: o.Key})
+		}
+
+		_, deleteErr = s.client.DeleteObjectsWithContext(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(s.bucket),
+			Delete: &s3.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+
+		return deleteErr == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return deleteErr
+}
+
+// isNotFound reports whether err says that an object does not exist.
+func isNotFound(err error) bool {
+	if aerr, ok := err.(awserr.RequestFailure); ok && aerr.StatusCode() == http.StatusNotFound {
+		return true
+	}
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+		return true
+	}
+	return false
+}
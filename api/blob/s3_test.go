@@ -0,0 +1,86 @@
+package blob_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/allen-woods/the-supertask/api/blob"
+	"github.com/allen-woods/the-supertask/api/blob/blobtest"
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// testS3 runs the checks against S3 stores, each in a bucket of its own,
+// deleted once every check has run.
+func testS3(t *testing.T, opts blob.S3Options) {
+	buckets := []*blob.S3{}
+
+	defer func() {
+		for _, s := range buckets {
+			err := s.DeleteBucket(context.Background())
+			if err != nil {
+				t.Errorf("Could not delete a bucket: %v", err)
+			}
+		}
+	}()
+
+	blobtest.TestStore(t, func(t *testing.T) blob.Store {
+		bucketOpts := opts
+		bucketOpts.Bucket = "blobtest-" + primitive.NewObjectID().Hex()
+
+		s, err := blob.NewS3(bucketOpts)
+		if err != nil {
+			t.Fatalf("Could not create store: %v", err)
+		}
+
+		err = s.EnsureBucket(context.Background())
+		if err != nil {
+			t.Fatalf("Could not create bucket: %v", err)
+		}
+
+		buckets = append(buckets, s)
+		return s
+	})
+}
+
+// TestS3Fake checks the S3 store against an in-process fake of S3.
+func TestS3Fake(t *testing.T) {
+	fake := httptest.NewServer(gofakes3.New(s3mem.New()).Server())
+	defer fake.Close()
+
+	testS3(t, blob.S3Options{
+		Endpoint:  fake.URL,
+		Region:    "us-east-1",
+		AccessKey: "fake",
+		SecretKey: "fake",
+		PathStyle: true,
+	})
+}
+
+// TestS3 checks the S3 store against the S3 compatible service named by
+// BLOBTEST_S3_ENDPOINT, if any, such as a local MinIO container:
+//
+//	docker run --rm -d -p 9000:9000 -e MINIO_ROOT_USER=minio -e MINIO_ROOT_PASSWORD=minio123 minio/minio server /data
+//	BLOBTEST_S3_ENDPOINT=http://localhost:9000 BLOBTEST_S3_ACCESS_KEY=minio BLOBTEST_S3_SECRET_KEY=minio123 go test ./blob
+func TestS3(t *testing.T) {
+	endpoint := os.Getenv("BLOBTEST_S3_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("BLOBTEST_S3_ENDPOINT is not set")
+	}
+
+	region := os.Getenv("BLOBTEST_S3_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	testS3(t, blob.S3Options{
+		Endpoint:  endpoint,
+		Region:    region,
+		AccessKey: os.Getenv("BLOBTEST_S3_ACCESS_KEY"),
+		SecretKey: os.Getenv("BLOBTEST_S3_SECRET_KEY"),
+		PathStyle: true,
+	})
+}
@@ -0,0 +1,193 @@
+// Command allowlist manages the operations the API runs in allowlist mode,
+// from the persisted query manifest generated by the front end build:
+//
+//	allowlist diff -manifest persisted-query-manifest.json
+//	allowlist register -manifest persisted-query-manifest.json -prune
+//	allowlist list
+//
+// diff exits with status 1 when the manifest differs from the registered
+// operations. register adds the operations of the manifest, and with -prune
+// removes those left out of it, which breaks the clients of older builds.
+//
+// Redis is reached as the API reaches it, from REDIS_IP, REDIS_PORT and
+// REDIS_ADMIN_PASSWORD, unless -redis says otherwise.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/allen-woods/the-supertask/api/persisted"
+	"github.com/go-redis/redis"
+)
+
+const usage = `Usage:
+  allowlist diff -manifest <file> [-redis <host:port>]
+  allowlist register -manifest <file> [-prune] [-redis <host:port>]
+  allowlist list [-redis <host:port>]
+`
+
+// timeout bounds every command.
+const timeout = 30 * time.Second
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "diff":
+		var differs bool
+		differs, err = diff(os.Args[2:])
+		if err == nil && differs {
+			os.Exit(1)
+		}
+	case "register":
+		err = register(os.Args[2:])
+	case "list":
+		err = list(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// diff prints the operations that registering the manifest with -prune would
+// add and remove, and reports whether there are any.
+func diff(args []string) (bool, error) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	manifestPath := fs.String("manifest", "", "persisted query manifest of the front end")
+	redisAddr := redisFlag(fs)
+	fs.Parse(args)
+
+	m, err := readManifest(*manifestPath)
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	registered, err := store(*redisAddr).List(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	added, removed := persisted.Diff(registered, m.Operations)
+	for _, op := range added {
+		fmt.Printf("+ %s %s %s\n", op.ID, op.Type, op.Name)
+	}
+	for _, op := range removed {
+		fmt.Printf("- %s %s %s\n", op.ID, op.Type, op.Name)
+	}
+
+	return len(added) > 0 || len(removed) > 0, nil
+}
+
+// register adds the operations of the manifest to the allowlist.
+func register(args []string) error {
+	fs := flag.NewFlagSet("register", flag.ExitOnError)
+	manifestPath := fs.String("manifest", "", "persisted query manifest of the front end")
+	prune := fs.Bool("prune", false, "remove the operations left out of the manifest")
+	redisAddr := redisFlag(fs)
+	fs.Parse(args)
+
+	m, err := readManifest(*manifestPath)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	s := store(*redisAddr)
+
+	registered, err := s.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	added, removed := persisted.Diff(registered, m.Operations)
+	if !*prune {
+		removed = nil
+	}
+
+	err = s.Register(ctx, m, *prune)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Registered %d operations, %d of them new, and removed %d\n", len(m.Operations), len(added), len(removed))
+	return nil
+}
+
+// list prints every registered operation.
+func list(args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	redisAddr := redisFlag(fs)
+	fs.Parse(args)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ops, err := store(*redisAddr).List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, op := range ops {
+		fmt.Printf("%s %s %s\n", op.ID, op.Type, op.Name)
+	}
+
+	return nil
+}
+
+// redisFlag defines the -redis flag, defaulting to the Redis of the API.
+func redisFlag(fs *flag.FlagSet) *string {
+	host, port := os.Getenv("REDIS_IP"), os.Getenv("REDIS_PORT")
+	if host == "" {
+		host = "localhost"
+	}
+	if port == "" {
+		port = "6379"
+	}
+
+	return fs.String("redis", net.JoinHostPort(host, port), "host:port of Redis")
+}
+
+// store returns the store of the operations in the Redis at addr.
+func store(addr string) persisted.Store {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: os.Getenv("REDIS_ADMIN_PASSWORD"),
+	})
+
+	return persisted.Store{Redis: func() *redis.Client { return client }}
+}
+
+func readManifest(path string) (*persisted.Manifest, error) {
+	if path == "" {
+		return nil, errors.New("A -manifest is required")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return persisted.ReadManifest(f)
+}
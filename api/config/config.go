@@ -0,0 +1,532 @@
+// Package config holds the settings of the API. They are read, by increasing
+// precedence, from their defaults, a YAML file, environment variables and
+// command line flags; see config.example.yaml for every setting.
+package config
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/allen-woods/the-supertask/api/auth"
+	"github.com/allen-woods/the-supertask/api/avatar"
+	"github.com/allen-woods/the-supertask/api/blob"
+	"github.com/allen-woods/the-supertask/api/export"
+	"github.com/allen-woods/the-supertask/api/limits"
+	"github.com/allen-woods/the-supertask/api/persisted"
+	"github.com/allen-woods/the-supertask/api/ratelimit"
+	"github.com/allen-woods/the-supertask/pkg/lifecycle"
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	"github.com/allen-woods/the-supertask/pkg/mtls"
+	"github.com/allen-woods/the-supertask/pkg/settings"
+	"github.com/allen-woods/the-supertask/pkg/tracing"
+)
+
+// FileEnv is the environment variable naming the configuration file, unless
+// given by --config.
+const FileEnv = "API_CONFIG"
+
+// Secret is a setting that is never printed, given literally or as a "file:"
+// or "env:" reference.
+type Secret = settings.Secret
+
+// Config holds every setting of the API.
+type Config struct {
+	// Env is "development" for local development; anything else gets the
+	// production settings.
+	Env string `yaml:"env" env:"API_ENV" flag:"env" usage:"environment, \"development\" or \"production\""`
+
+	HTTP          HTTPConfig          `yaml:"http"`
+	GraphQL       GraphQLConfig       `yaml:"graphql"`
+	RateLimit     RateLimitConfig     `yaml:"rateLimit"`
+	Cookie        CookieConfig        `yaml:"cookie"`
+	Redis         RedisConfig         `yaml:"redis"`
+	UserService   UserServiceConfig   `yaml:"userService"`
+	Accounts      AccountsConfig      `yaml:"accounts"`
+	DataExport    DataExportConfig    `yaml:"dataExport"`
+	Blobs         BlobsConfig         `yaml:"blobs"`
+	Avatars       AvatarsConfig       `yaml:"avatars"`
+	Impersonation ImpersonationConfig `yaml:"impersonation"`
+	Logging       LoggingConfig       `yaml:"logging"`
+	Tracing       TracingConfig       `yaml:"tracing"`
+	Shutdown      ShutdownConfig      `yaml:"shutdown"`
+
+	loader *settings.Loader
+}
+
+// HTTPConfig is where the API is served, and to whom.
+type HTTPConfig struct {
+	Port int `yaml:"port" env:"GRAPHQL_API_PORT" flag:"port" usage:"port to serve the API on"`
+	// AllowedOrigins are the cross-origin callers of the API. The React dev
+	// server is allowed by default in development, and none otherwise.
+	AllowedOrigins []string `yaml:"allowedOrigins" env:"API_ALLOWED_ORIGINS" flag:"allowed-origins" usage:"comma separated origins allowed to call the API"`
+}
+
+// GraphQLConfig limits the operations the API runs. Operations are measured
+// as described in the limits package, against the limits of the user making
+// them; anonymous users get those of users, and admins their own.
+type GraphQLConfig struct {
+	MaxDepth           int `yaml:"maxDepth" env:"GRAPHQL_MAX_DEPTH" usage:"deepest nesting of fields an operation may select"`
+	MaxComplexity      int `yaml:"maxComplexity" env:"GRAPHQL_MAX_COMPLEXITY" usage:"highest complexity an operation may have"`
+	AdminMaxDepth      int `yaml:"adminMaxDepth" env:"GRAPHQL_ADMIN_MAX_DEPTH" usage:"deepest nesting of fields an operation of an admin may select"`
+	AdminMaxComplexity int `yaml:"adminMaxComplexity" env:"GRAPHQL_ADMIN_MAX_COMPLEXITY" usage:"highest complexity an operation of an admin may have"`
+	// Introspection reveals the schema, which the playground needs.
+	Introspection bool `yaml:"introspection" env:"GRAPHQL_INTROSPECTION" flag:"introspection" usage:"allow introspection of the schema"`
+	// PersistedQueries is off, apq or allowlist, as described in the
+	// persisted package. Allowlist mode only runs the operations registered
+	// from the manifest of the front end, with cmd/allowlist.
+	PersistedQueries string `yaml:"persistedQueries" env:"GRAPHQL_PERSISTED_QUERIES" flag:"persisted-queries" usage:"persisted queries: off, apq or allowlist"`
+	// PersistedQueryTTL is how long automatic persisted queries are cached.
+	PersistedQueryTTL time.Duration `yaml:"persistedQueryTTL" env:"GRAPHQL_PERSISTED_QUERY_TTL" usage:"how long automatic persisted queries are cached"`
+}
+
+// RateLimitConfig limits how much each client may ask of the API, as described
+// in the ratelimit package. Rates are in complexity per minute, and bursts in
+// complexity.
+type RateLimitConfig struct {
+	Enabled   bool             `yaml:"enabled" env:"RATE_LIMIT_ENABLED" flag:"rate-limit" usage:"rate limit GraphQL operations"`
+	Anonymous ratelimit.Limits `yaml:"anonymous"`
+	User      ratelimit.Limits `yaml:"user"`
+	Admin     ratelimit.Limits `yaml:"admin"`
+	// AccessTokensFile lists the access tokens with limits of their own;
+	// their rates default to those of users.
+	AccessTokensFile string `yaml:"accessTokensFile" env:"RATE_LIMIT_ACCESS_TOKENS_FILE" usage:"YAML file of the access tokens with limits of their own"`
+}
+
+// CookieConfig holds the attributes of the cookies set by the API. Secure and
+// SameSite default to the strictest settings that work in the environment.
+type CookieConfig struct {
+	Domain   string `yaml:"domain" env:"COOKIE_DOMAIN" usage:"domain of the cookies"`
+	Secure   bool   `yaml:"secure" env:"COOKIE_SECURE" usage:"send cookies over HTTPS only"`
+	SameSite string `yaml:"sameSite" env:"COOKIE_SAME_SITE" usage:"SameSite attribute: strict, lax or none"`
+	// KeyDir holds the keys cookies are signed and encrypted with. It must
+	// be kept out of the source tree, and of images.
+	KeyDir string `yaml:"keyDir" env:"COOKIE_KEY_DIR" usage:"directory of the keys of the cookies, outside the source tree"`
+}
+
+// RedisConfig is the Redis instance holding sessions and other short-lived
+// data.
+type RedisConfig struct {
+	Host     string `yaml:"host" env:"REDIS_IP" flag:"redis-host" usage:"Redis host"`
+	Port     int    `yaml:"port" env:"REDIS_PORT" flag:"redis-port" usage:"Redis port"`
+	Password Secret `yaml:"password" env:"REDIS_ADMIN_PASSWORD" usage:"Redis password, or a file: or env: reference to it"`
+}
+
+// UserServiceConfig is where the User gRPC microservice is dialed.
+type UserServiceConfig struct {
+	Address string `yaml:"address" env:"USER_SERVICE_ADDRESS" flag:"user-service-address" usage:"host:port of the User service"`
+	// Token authenticates the API to the User service, which restricts
+	// most of its methods to the API.
+	Token Secret               `yaml:"token" env:"USER_SERVICE_TOKEN" usage:"service token presented to the User service, or a file: or env: reference to it"`
+	TLS   UserServiceTLSConfig `yaml:"tls"`
+	// Insecure dials without TLS, for local development only.
+	Insecure bool `yaml:"insecure" env:"USER_SERVICE_INSECURE" flag:"user-service-insecure" usage:"dial the User service without TLS, for development only"`
+}
+
+// UserServiceTLSConfig secures the calls to the User service with mutual
+// TLS. The files are reloaded when they change.
+type UserServiceTLSConfig struct {
+	Cert string `yaml:"cert" env:"API_TLS_CERT" usage:"PEM client certificate presented to the User service"`
+	Key  string `yaml:"key" env:"API_TLS_KEY" usage:"PEM private key of the client certificate"`
+	CA   string `yaml:"ca" env:"API_TLS_CA" usage:"PEM certificates of the authorities signing the certificate of the User service"`
+	// ServerName defaults to the host of the address.
+	ServerName string `yaml:"serverName" env:"USER_SERVICE_SERVER_NAME" usage:"name expected in the certificate of the User service"`
+}
+
+// AccountsConfig drives the upkeep of user accounts.
+type AccountsConfig struct {
+	PurgeInterval time.Duration `yaml:"purgeInterval" env:"ACCOUNT_PURGE_INTERVAL" usage:"how often accounts past their deletion grace period are purged"`
+}
+
+// DataExportConfig drives the GDPR data exports.
+type DataExportConfig struct {
+	Retention time.Duration `yaml:"retention" env:"DATA_EXPORT_RETENTION" usage:"how long export archives can be downloaded"`
+}
+
+// BlobsConfig is where the files the API keeps, such as the images of avatars,
+// are stored, and served from.
+type BlobsConfig struct {
+	// Storage is local, for files under Dir served by the API itself, or s3.
+	Storage string `yaml:"storage" env:"BLOB_STORAGE" usage:"where files are stored: local or s3"`
+	Dir     string `yaml:"dir" env:"BLOB_DIR" usage:"directory of local storage"`
+	// PublicURL is where browsers fetch the files from, such as the bucket
+	// or a CDN in front of it. It defaults to the API itself in development.
+	PublicURL string       `yaml:"publicURL" env:"BLOB_PUBLIC_URL" usage:"URL the stored files are served from"`
+	S3        BlobS3Config `yaml:"s3"`
+}
+
+// BlobS3Config locates an S3 compatible bucket, such as MinIO.
+type BlobS3Config struct {
+	Endpoint  string `yaml:"endpoint" env:"BLOB_S3_ENDPOINT" usage:"URL of the S3 API; empty for AWS"`
+	Region    string `yaml:"region" env:"BLOB_S3_REGION" usage:"region of the bucket"`
+	Bucket    string `yaml:"bucket" env:"BLOB_S3_BUCKET" usage:"bucket the files are stored in"`
+	AccessKey Secret `yaml:"accessKey" env:"BLOB_S3_ACCESS_KEY" usage:"access key, or a file: or env: reference to it"`
+	SecretKey Secret `yaml:"secretKey" env:"BLOB_S3_SECRET_KEY" usage:"secret key, or a file: or env: reference to it"`
+	PathStyle bool   `yaml:"pathStyle" env:"BLOB_S3_PATH_STYLE" usage:"address the bucket in the path of URLs, as MinIO expects"`
+}
+
+// AvatarsConfig drives the pictures of profiles.
+type AvatarsConfig struct {
+	MaxSize int `yaml:"maxSize" env:"AVATAR_MAX_SIZE" usage:"size of the largest avatar upload, in bytes"`
+}
+
+// ImpersonationConfig drives admin impersonation.
+type ImpersonationConfig struct {
+	Duration time.Duration `yaml:"duration" env:"IMPERSONATION_DURATION" usage:"how long an impersonation lasts"`
+}
+
+// LoggingConfig drives the structured logs of the API.
+type LoggingConfig struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL" flag:"log-level" usage:"lowest level logged: debug, info, warn or error"`
+	Format string `yaml:"format" env:"LOG_FORMAT" usage:"json, or console for human-readable lines"`
+}
+
+// TracingConfig says where the OpenTelemetry spans of the API are exported.
+type TracingConfig struct {
+	Exporter string `yaml:"exporter" env:"TRACING_EXPORTER" flag:"tracing-exporter" usage:"where spans are exported: none, otlp or stdout"`
+	Endpoint string `yaml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT" usage:"host:port of the OpenTelemetry collector, for the otlp exporter"`
+	Insecure bool   `yaml:"insecure" env:"TRACING_INSECURE" usage:"send spans to the collector without TLS"`
+	// File is where the stdout exporter writes, for local development; empty
+	// writes to standard output.
+	File        string  `yaml:"file" env:"TRACING_FILE" usage:"file the stdout exporter writes to"`
+	SampleRatio float64 `yaml:"sampleRatio" env:"TRACING_SAMPLE_RATIO" usage:"share of new traces that are sampled, from 0 to 1"`
+}
+
+// ShutdownConfig drives the graceful shutdown of the API.
+type ShutdownConfig struct {
+	DrainTimeout  time.Duration `yaml:"drainTimeout" env:"SHUTDOWN_DRAIN_TIMEOUT" usage:"how long in-flight requests are given to complete on shutdown"`
+	NotReadyDelay time.Duration `yaml:"notReadyDelay" env:"SHUTDOWN_NOT_READY_DELAY" usage:"how long the API keeps serving after reporting not ready on shutdown"`
+	CloseTimeout  time.Duration `yaml:"closeTimeout" env:"SHUTDOWN_CLOSE_TIMEOUT" usage:"how long each client is given to close on shutdown, after draining"`
+}
+
+// devOrigin is the React dev server, which runs on its own port and so is a
+// cross-origin caller.
+const devOrigin = "http://localhost:3000"
+
+// Default returns the settings used when nothing else is given.
+func Default() *Config {
+	return &Config{
+		Env: "production",
+		HTTP: HTTPConfig{
+			Port: 8080,
+		},
+		GraphQL: GraphQLConfig{
+			MaxDepth:           10,
+			MaxComplexity:      250,
+			AdminMaxDepth:      15,
+			AdminMaxComplexity: 2000,
+			Introspection:      true,
+			PersistedQueries:   persisted.ModeAPQ,
+			PersistedQueryTTL:  persisted.DefaultCacheTTL,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Anonymous: ratelimit.Limits{
+				Query:        ratelimit.Rate{PerMinute: 600, Burst: 600},
+				Mutation:     ratelimit.Rate{PerMinute: 60, Burst: 60},
+				Subscription: ratelimit.Rate{PerMinute: 10, Burst: 10},
+			},
+			User: ratelimit.Limits{
+				Query:        ratelimit.Rate{PerMinute: 1200, Burst: 1200},
+				Mutation:     ratelimit.Rate{PerMinute: 300, Burst: 300},
+				Subscription: ratelimit.Rate{PerMinute: 30, Burst: 30},
+			},
+			Admin: ratelimit.Limits{
+				Query:        ratelimit.Rate{PerMinute: 6000, Burst: 6000},
+				Mutation:     ratelimit.Rate{PerMinute: 1200, Burst: 1200},
+				Subscription: ratelimit.Rate{PerMinute: 60, Burst: 60},
+			},
+		},
+		Cookie: CookieConfig{
+			Secure:   true,
+			SameSite: "strict",
+			KeyDir:   defaultKeyDir(),
+		},
+		Redis: RedisConfig{
+			Host: "0.0.0.0",
+			Port: 6379,
+		},
+		UserService: UserServiceConfig{
+			Address: "0.0.0.0:50051",
+		},
+		Accounts: AccountsConfig{
+			PurgeInterval: time.Hour,
+		},
+		DataExport: DataExportConfig{
+			Retention: export.DefaultRetention,
+		},
+		Blobs: BlobsConfig{
+			Storage: "local",
+			Dir:     "blobs",
+			S3: BlobS3Config{
+				Region: "us-east-1",
+			},
+		},
+		Avatars: AvatarsConfig{
+			MaxSize: avatar.DefaultMaxSize,
+		},
+		Impersonation: ImpersonationConfig{
+			Duration: auth.DefaultImpersonationDuration,
+		},
+		Logging: LoggingConfig{
+			Level:  "info",
+			Format: logging.FormatJSON,
+		},
+		Tracing: TracingConfig{
+			Exporter:    tracing.ExporterNone,
+			Endpoint:    "localhost:4317",
+			SampleRatio: 1,
+		},
+		Shutdown: ShutdownConfig{
+			DrainTimeout: lifecycle.DefaultDrainTimeout,
+			CloseTimeout: lifecycle.DefaultCloseTimeout,
+		},
+	}
+}
+
+// Load reads the settings from args, the environment and the configuration
+// file, and validates them.
+func Load(args []string) (*Config, error) {
+	cfg := Default()
+	cfg.loader = &settings.Loader{Name: "api", FileEnv: FileEnv}
+
+	err := cfg.loader.Load(cfg, args)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.applyEnvDefaults()
+
+	err = cfg.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// applyEnvDefaults relaxes the defaults left unset for local development,
+// which is served over plain HTTP, with the React dev server on another port
+// of the same host (same site), and logs for humans to read.
+func (c *Config) applyEnvDefaults() {
+	if c.Env != "development" {
+		return
+	}
+
+	if !c.loader.IsSet("http.allowedOrigins") {
+		c.HTTP.AllowedOrigins = []string{devOrigin}
+	}
+	if !c.loader.IsSet("cookie.secure") {
+		c.Cookie.Secure = false
+	}
+	if !c.loader.IsSet("cookie.sameSite") {
+		c.Cookie.SameSite = "lax"
+	}
+	if !c.loader.IsSet("logging.format") {
+		c.Logging.Format = logging.FormatConsole
+	}
+	if !c.loader.IsSet("blobs.publicURL") {
+		c.Blobs.PublicURL = fmt.Sprintf("http://localhost:%d%s", c.HTTP.Port, blob.PathPrefix)
+	}
+}
+
+func (c *Config) validate() error {
+	check := c.loader.Check
+
+	check("http.port", c.HTTP.Port > 0 && c.HTTP.Port < 65536, "must be between 1 and 65535, not %d", c.HTTP.Port)
+
+	for _, origin := range c.HTTP.AllowedOrigins {
+		u, err := url.Parse(origin)
+		check("http.allowedOrigins", err == nil && u.Scheme != "" && u.Host != "", "%q is not an origin such as https://example.com", origin)
+	}
+
+	check("graphql.maxDepth", c.GraphQL.MaxDepth > 0, "must be positive")
+	check("graphql.maxComplexity", c.GraphQL.MaxComplexity > 0, "must be positive")
+	check("graphql.adminMaxDepth", c.GraphQL.AdminMaxDepth >= c.GraphQL.MaxDepth, "must be at least graphql.maxDepth")
+	check("graphql.adminMaxComplexity", c.GraphQL.AdminMaxComplexity >= c.GraphQL.MaxComplexity, "must be at least graphql.maxComplexity")
+
+	switch c.GraphQL.PersistedQueries {
+	case persisted.ModeOff, persisted.ModeAPQ, persisted.ModeAllowlist:
+	default:
+		check("graphql.persistedQueries", false, "must be off, apq or allowlist, not %q", c.GraphQL.PersistedQueries)
+	}
+	check("graphql.persistedQueryTTL", c.GraphQL.PersistedQueryTTL > 0, "must be positive")
+
+	for _, role := range []struct {
+		name   string
+		limits ratelimit.Limits
+	}{
+		{"anonymous", c.RateLimit.Anonymous},
+		{"user", c.RateLimit.User},
+		{"admin", c.RateLimit.Admin},
+	} {
+		for _, op := range []struct {
+			name string
+			rate ratelimit.Rate
+		}{
+			{"query", role.limits.Query},
+			{"mutation", role.limits.Mutation},
+			{"subscription", role.limits.Subscription},
+		} {
+			path := "rateLimit." + role.name + "." + op.name
+			check(path+".perMinute", op.rate.PerMinute > 0, "must be positive")
+			check(path+".burst", op.rate.Burst > 0, "must be positive")
+		}
+	}
+	_, err := c.RateLimitAccessTokens()
+	check("rateLimit.accessTokensFile", err == nil, "%v", err)
+
+	_, err = auth.ParseSameSite(c.Cookie.SameSite)
+	check("cookie.sameSite", err == nil, "must be strict, lax or none, not %q", c.Cookie.SameSite)
+
+	// Browsers reject SameSite=None cookies that are not also Secure.
+	check("cookie.sameSite", !strings.EqualFold(c.Cookie.SameSite, "none") || c.Cookie.Secure, "none requires cookie.secure")
+	check("cookie.keyDir", c.Cookie.KeyDir != "", "must be set")
+
+	check("redis.host", c.Redis.Host != "", "must be set")
+	check("redis.port", c.Redis.Port > 0 && c.Redis.Port < 65536, "must be between 1 and 65535, not %d", c.Redis.Port)
+
+	_, _, err = net.SplitHostPort(c.UserService.Address)
+	check("userService.address", err == nil, "must be host:port, not %q", c.UserService.Address)
+	if !c.UserService.Insecure {
+		check("userService.tls.cert", c.UserService.TLS.Cert != "", "must be set, unless userService.insecure is set for development")
+		check("userService.tls.key", c.UserService.TLS.Key != "", "must be set, unless userService.insecure is set for development")
+		check("userService.tls.ca", c.UserService.TLS.CA != "", "must be set, unless userService.insecure is set for development")
+	}
+
+	check("accounts.purgeInterval", c.Accounts.PurgeInterval > 0, "must be positive")
+	check("dataExport.retention", c.DataExport.Retention > 0, "must be positive")
+	switch c.Blobs.Storage {
+	case "local":
+		check("blobs.dir", c.Blobs.Dir != "", "must be set for local storage")
+	case "s3":
+		check("blobs.s3.bucket", c.Blobs.S3.Bucket != "", "must be set for s3 storage")
+		check("blobs.s3.region", c.Blobs.S3.Region != "", "must be set for s3 storage")
+	default:
+		check("blobs.storage", false, "must be local or s3, not %q", c.Blobs.Storage)
+	}
+	publicURL, err := url.Parse(c.Blobs.PublicURL)
+	check("blobs.publicURL", err == nil && publicURL.Scheme != "" && publicURL.Host != "", "must be an absolute URL such as https://cdn.example.com/, not %q", c.Blobs.PublicURL)
+	check("avatars.maxSize", c.Avatars.MaxSize > 0, "must be positive")
+
+	check("impersonation.duration", c.Impersonation.Duration > 0, "must be positive")
+	check("logging.level", logging.ValidLevel(c.Logging.Level), "must be debug, info, warn or error, not %q", c.Logging.Level)
+	check("logging.format", logging.ValidFormat(c.Logging.Format), "must be json or console, not %q", c.Logging.Format)
+
+	check("tracing.exporter", tracing.ValidExporter(c.Tracing.Exporter), "must be none, otlp or stdout, not %q", c.Tracing.Exporter)
+	if c.Tracing.Exporter == tracing.ExporterOTLP {
+		_, _, err := net.SplitHostPort(c.Tracing.Endpoint)
+		check("tracing.endpoint", err == nil, "must be host:port, not %q", c.Tracing.Endpoint)
+	}
+	check("tracing.sampleRatio", c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "must be between 0 and 1, not %g", c.Tracing.SampleRatio)
+	check("shutdown.drainTimeout", c.Shutdown.DrainTimeout > 0, "must be positive")
+	check("shutdown.notReadyDelay", c.Shutdown.NotReadyDelay >= 0 && c.Shutdown.NotReadyDelay < c.Shutdown.DrainTimeout, "must be less than shutdown.drainTimeout")
+	check("shutdown.closeTimeout", c.Shutdown.CloseTimeout > 0, "must be positive")
+
+	return c.loader.Err()
+}
+
+// UserServiceTLSFiles are the files calls to the User service are secured
+// with, unless insecure.
+func (c *Config) UserServiceTLSFiles() mtls.Files {
+	return mtls.Files{
+		Cert: c.UserService.TLS.Cert,
+		Key:  c.UserService.TLS.Key,
+		CA:   c.UserService.TLS.CA,
+	}
+}
+
+// UserServiceName is the name expected in the certificate of the User service.
+func (c *Config) UserServiceName() string {
+	if c.UserService.TLS.ServerName != "" {
+		return c.UserService.TLS.ServerName
+	}
+
+	host, _, _ := net.SplitHostPort(c.UserService.Address)
+	return host
+}
+
+// GraphQLLimits are the limits of the operations of users and of admins.
+func (c *Config) GraphQLLimits() (user limits.Limits, admin limits.Limits) {
+	user = limits.Limits{MaxDepth: c.GraphQL.MaxDepth, MaxComplexity: c.GraphQL.MaxComplexity}
+	admin = limits.Limits{MaxDepth: c.GraphQL.AdminMaxDepth, MaxComplexity: c.GraphQL.AdminMaxComplexity}
+	return user, admin
+}
+
+// RateLimitAccessTokens are the access tokens with limits of their own, as
+// read from rateLimit.accessTokensFile.
+func (c *Config) RateLimitAccessTokens() ([]ratelimit.AccessToken, error) {
+	return ratelimit.ReadAccessTokensFile(c.RateLimit.AccessTokensFile, c.RateLimit.User)
+}
+
+// BlobStore is the store of the files the API keeps.
+func (c *Config) BlobStore() (blob.Store, error) {
+	if c.Blobs.Storage == "s3" {
+		return blob.NewS3(blob.S3Options{
+			Endpoint:  c.Blobs.S3.Endpoint,
+			Region:    c.Blobs.S3.Region,
+			Bucket:    c.Blobs.S3.Bucket,
+			AccessKey: c.Blobs.S3.AccessKey.Value(),
+			SecretKey: c.Blobs.S3.SecretKey.Value(),
+			PathStyle: c.Blobs.S3.PathStyle,
+		})
+	}
+
+	return blob.NewLocal(c.Blobs.Dir)
+}
+
+// TracingOptions says how the API exports its spans.
+func (c *Config) TracingOptions() tracing.Options {
+	return tracing.Options{
+		ServiceName: "api",
+		Exporter:    c.Tracing.Exporter,
+		Endpoint:    c.Tracing.Endpoint,
+		Insecure:    c.Tracing.Insecure,
+		File:        c.Tracing.File,
+		SampleRatio: c.Tracing.SampleRatio,
+	}
+}
+
+// PrintOnly is whether --print-config was given, in which case the API should
+// print its configuration and exit rather than start.
+func (c *Config) PrintOnly() bool {
+	return c.loader != nil && c.loader.PrintOnly()
+}
+
+// Print writes the settings to w as YAML, with secrets redacted.
+func (c *Config) Print(w io.Writer) error {
+	return settings.Print(w, c)
+}
+
+// RedisAddress is the host:port of Redis.
+func (c *Config) RedisAddress() string {
+	return net.JoinHostPort(c.Redis.Host, fmt.Sprint(c.Redis.Port))
+}
+
+// defaultKeyDir is where the keys of the cookies are kept by default, in the
+// configuration directory of the user running the API, or nowhere when there
+// is none.
+func defaultKeyDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "the-supertask", "cookie-keys")
+}
+
+// CookieOptions are the attributes of the cookies set by the API.
+func (c *Config) CookieOptions() auth.CookieOptions {
+	sameSite, _ := auth.ParseSameSite(c.Cookie.SameSite)
+
+	return auth.CookieOptions{
+		Domain:   c.Cookie.Domain,
+		Secure:   c.Cookie.Secure,
+		SameSite: sameSite,
+	}
+}
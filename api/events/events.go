@@ -0,0 +1,103 @@
+// Package events carries events between the replicas of the API over Redis
+// pub/sub, so that a subscription is fed whichever replica the event happened
+// on.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	"github.com/go-redis/redis"
+	"go.uber.org/zap"
+)
+
+// channelPrefix namespaces the Redis channels of the events.
+const channelPrefix = "events:"
+
+// Topics of the events, as published by the API.
+const (
+	TopicUserSignedUp = "userSignedUp"
+)
+
+// SessionsRevokedTopic is the topic of the revocations of the sessions of the
+// given user.
+func SessionsRevokedTopic(userID string) string {
+	return "sessionsRevoked:" + userID
+}
+
+// SessionsRevoked is published when every session of a user is revoked.
+type SessionsRevoked struct {
+	UserID    string    `json:"userId"`
+	Reason    string    `json:"reason"`
+	RevokedAt time.Time `json:"revokedAt"`
+}
+
+// UserSignedUp is published when someone signs up.
+type UserSignedUp struct {
+	UserID string `json:"userId"`
+}
+
+// redisClient returns the client events go through, as set by SetRedisClient.
+var redisClient func() *redis.Client
+
+// SetRedisClient sets how the Redis client that events go through is had.
+func SetRedisClient(client func() *redis.Client) {
+	redisClient = client
+}
+
+// Publish sends the event to the subscribers of the topic on every replica.
+// Events published without subscribers are lost.
+func Publish(ctx context.Context, topic string, event interface{}) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return redisClient().WithContext(ctx).Publish(channelPrefix+topic, data).Err()
+}
+
+// Subscribe returns the events published to the topic from now on, as JSON,
+// until ctx is done, at which point the channel is closed.
+func Subscribe(ctx context.Context, topic string) (<-chan json.RawMessage, error) {
+	ps := redisClient().Subscribe(channelPrefix + topic)
+
+	// Wait for the subscription to be confirmed, so that no event published
+	// after Subscribe returns is missed.
+	_, err := ps.Receive()
+	if err != nil {
+		ps.Close()
+		return nil, err
+	}
+
+	events := make(chan json.RawMessage)
+
+	go func() {
+		defer close(events)
+		defer ps.Close()
+
+		messages := ps.Channel()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case m, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				select {
+				case events <- json.RawMessage(m.Payload):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	logging.FromContext(ctx).Debug("Subscribed to events", zap.String("topic", topic))
+
+	return events, nil
+}
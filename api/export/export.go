@@ -0,0 +1,438 @@
+// Package export assembles archives of everything we store about a user, keeps
+// them in blob storage for a limited time and serves them to their owner. The
+// exports themselves are described in Redis.
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/allen-woods/the-supertask/api/auth"
+	"github.com/allen-woods/the-supertask/api/blob"
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	pb "github.com/allen-woods/the-supertask/services/user/proto"
+	"github.com/go-redis/redis"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// Statuses of an export.
+const (
+	StatusPending = "pending"
+	StatusReady   = "ready"
+	StatusFailed  = "failed"
+)
+
+// DefaultRetention is how long an export is kept once requested, unless
+// SetRetention says otherwise.
+const DefaultRetention = 48 * time.Hour
+
+// BuildTimeout bounds the assembly of an archive. An export still pending
+// after it is considered failed, as its build was lost with its API instance.
+const BuildTimeout = 10 * time.Minute
+
+// ErrPending is returned when a user requests an export while another one is
+// still being assembled.
+var ErrPending = errors.New("a data export is already being prepared")
+
+// sweepInterval is how often archives are checked for expiry.
+const sweepInterval = time.Hour
+
+var (
+	retention = DefaultRetention
+	store     blob.Store
+)
+
+// SetRetention sets how long exports requested from now on are kept.
+func SetRetention(d time.Duration) {
+	retention = d
+}
+
+// SetStore sets where archives are stored from now on. Only the API may serve
+// them, so they are stored as private blobs.
+func SetStore(s blob.Store) {
+	store = s
+}
+
+// Export describes an archive of the data of a user.
+type Export struct {
+	ID          string
+	UserID      string
+	Status      string
+	RequestedAt time.Time
+	ExpiresAt   time.Time
+}
+
+func exportKey(id string) string {
+	return "dataExport:" + id
+}
+
+// userArchivesPrefix starts the blob keys of the archives of a user.
+func userArchivesPrefix(userID string) string {
+	return blob.PrivatePrefix + "exports/" + userID + "/"
+}
+
+// archiveKey is the blob key of the archive of e.
+func archiveKey(e *Export) string {
+	return userArchivesPrefix(e.UserID) + e.ID + ".zip"
+}
+
+// archiveExpiriesKey is the Redis sorted set of the blob keys of archives, by
+// when they expire.
+const archiveExpiriesKey = "dataExportArchiveExpiries"
+
+func userExportsKey(userID string) string {
+	return "dataExports:" + userID
+}
+
+// Create records a new pending export for the given user. The archive is
+// assembled by Build.
+func Create(userID string) (*Export, error) {
+	exports, err := List(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range exports {
+		if e.Status == StatusPending {
+			return nil, ErrPending
+		}
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	e := &Export{
+		ID:          primitive.NewObjectID().Hex(),
+		UserID:      userID,
+		Status:      StatusPending,
+		RequestedAt: now,
+		ExpiresAt:   now.Add(retention),
+	}
+
+	client := auth.RedisClient()
+
+	_, err = client.HMSet(exportKey(e.ID), map[string]interface{}{
+		"userID":      e.UserID,
+		"status":      e.Status,
+		"requestedAt": e.RequestedAt.Format(time.RFC3339),
+		"expiresAt":   e.ExpiresAt.Format(time.RFC3339),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = client.ExpireAt(exportKey(e.ID), e.ExpiresAt).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = client.SAdd(userExportsKey(userID), e.ID).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = client.ExpireAt(userExportsKey(userID), e.ExpiresAt).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Get returns the export with the given ID, or nil when it has expired.
+func Get(id string) (*Export, error) {
+	client := auth.RedisClient()
+
+	return get(client, id)
+}
+
+func get(client *redis.Client, id string) (*Export, error) {
+	fields, err := client.HGetAll(exportKey(id)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	e := &Export{
+		ID:     id,
+		UserID: fields["userID"],
+		Status: fields["status"],
+	}
+
+	e.RequestedAt, err = time.Parse(time.RFC3339, fields["requestedAt"])
+	if err != nil {
+		return nil, err
+	}
+
+	e.ExpiresAt, err = time.Parse(time.RFC3339, fields["expiresAt"])
+	if err != nil {
+		return nil, err
+	}
+
+	if e.Status == StatusPending && time.Since(e.RequestedAt) > BuildTimeout {
+		e.Status = StatusFailed
+	}
+
+	return e, nil
+}
+
+// List returns the unexpired exports of the given user, oldest first.
+func List(userID string) ([]*Export, error) {
+	client := auth.RedisClient()
+
+	ids, err := client.SMembers(userExportsKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	exports := []*Export{}
+
+	for _, id := range ids {
+		e, err := get(client, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if e == nil {
+			client.SRem(userExportsKey(userID), id)
+			continue
+		}
+
+		exports = append(exports, e)
+	}
+
+	sort.Slice(exports, func(i, j int) bool {
+		return exports[i].RequestedAt.Before(exports[j].RequestedAt)
+	})
+
+	return exports, nil
+}
+
+// Build assembles the archive of e from the data streamed by the User service
+// and the sessions of the user, writing it to blob storage as it goes, where
+// it is kept until e expires. The export is marked failed when any of it
+// cannot be gathered, and nothing is stored then.
+func Build(ctx context.Context, e *Export, c pb.UserCRUDClient) error {
+	client := auth.RedisClient()
+
+	// The archive is known to expire before it is stored, so that it is
+	// swept whatever happens next.
+	err := client.ZAdd(archiveExpiriesKey, redis.Z{Score: float64(e.ExpiresAt.Unix()), Member: archiveKey(e)}).Err()
+	if err == nil {
+		err = storeArchive(ctx, e, c)
+	}
+
+	if err != nil {
+		setStatus(client, e, StatusFailed)
+		return err
+	}
+
+	return setStatus(client, e, StatusReady)
+}
+
+// storeArchive streams the archive of e to blob storage as it is assembled.
+func storeArchive(ctx context.Context, e *Export, c pb.UserCRUDClient) error {
+	pr, pw := io.Pipe()
+	assembled := make(chan error, 1)
+
+	go func() {
+		err := assemble(ctx, e, c, pw)
+		pw.CloseWithError(err)
+		assembled <- err
+	}()
+
+	err := store.PutReader(ctx, archiveKey(e), pr, "application/zip")
+	// Stops the assembly if the upload failed first.
+	pr.CloseWithError(err)
+
+	assembleErr := <-assembled
+	if err == nil {
+		err = assembleErr
+	}
+
+	return err
+}
+
+// Fail marks e as failed, for when its build could not even be started.
+func Fail(e *Export) error {
+	client := auth.RedisClient()
+
+	return setStatus(client, e, StatusFailed)
+}
+
+func setStatus(client *redis.Client, e *Export, status string) error {
+	e.Status = status
+
+	// Do not resurrect an export that expired or was deleted meanwhile.
+	if client.Exists(exportKey(e.ID)).Val() == 0 {
+		return nil
+	}
+
+	return client.HSet(exportKey(e.ID), "status", status).Err()
+}
+
+// assemble writes the archive to out as a zip holding one JSON array per
+// collection of the User service, plus the sessions of the user.
+func assemble(ctx context.Context, e *Export, c pb.UserCRUDClient, out io.Writer) error {
+	zw := zip.NewWriter(out)
+
+	stream, err := c.ExportUserData(ctx, &pb.ExportUserDataReq{Id: e.UserID})
+	if err != nil {
+		return err
+	}
+
+	// Documents arrive grouped by collection, so each file is written out
+	// in a single pass.
+	var w io.Writer
+	collection := ""
+
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		sep := ","
+		if w == nil || res.GetCollection() != collection {
+			if w != nil {
+				io.WriteString(w, "]\n")
+			}
+
+			collection = res.GetCollection()
+
+			w, err = zw.Create(collection + ".json")
+			if err != nil {
+				return err
+			}
+
+			sep = "["
+		}
+
+		_, err = io.WriteString(w, sep+res.GetDocument())
+		if err != nil {
+			return err
+		}
+	}
+
+	if w != nil {
+		io.WriteString(w, "]\n")
+	}
+
+	sessions, err := auth.UserSessions(ctx, e.UserID)
+	if err != nil {
+		return err
+	}
+
+	history, err := auth.SessionHistory(ctx, e.UserID)
+	if err != nil {
+		return err
+	}
+
+	w, err = zw.Create("sessions.json")
+	if err != nil {
+		return err
+	}
+
+	err = json.NewEncoder(w).Encode(struct {
+		Active  []auth.Session      `json:"active"`
+		History []auth.SessionEvent `json:"history"`
+	}{sessions, history})
+	if err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// Open returns a reader of the archive of e, which the caller must close, or
+// nil when it has expired.
+func Open(ctx context.Context, e *Export) (io.ReadCloser, error) {
+	r, _, err := store.Open(ctx, archiveKey(e))
+	if err == blob.ErrNotFound {
+		return nil, nil
+	}
+
+	return r, err
+}
+
+// DeleteUserExports removes every export of the given user, such as when
+// their account is purged.
+func DeleteUserExports(ctx context.Context, userID string) error {
+	client := auth.RedisClient()
+
+	ids, err := client.SMembers(userExportsKey(userID)).Result()
+	if err != nil {
+		return err
+	}
+
+	err = store.DeletePrefix(ctx, userArchivesPrefix(userID))
+	if err != nil {
+		return err
+	}
+
+	keys := []string{userExportsKey(userID)}
+	for _, id := range ids {
+		keys = append(keys, exportKey(id))
+	}
+
+	return client.Del(keys...).Err()
+}
+
+// DeleteExpired deletes the archives of expired exports once immediately and
+// then every hour, until ctx is done.
+func DeleteExpired(ctx context.Context) {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		err := deleteExpired(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Error("Unable to delete expired data exports", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deleteExpired deletes the archives that expired before now. Replicas may
+// sweep at the same time, as deleting is idempotent.
+func deleteExpired(ctx context.Context, now time.Time) error {
+	client := auth.RedisClient()
+
+	keys, err := client.ZRangeByScore(archiveExpiriesKey, redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now.Unix(), 10),
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		err := store.Delete(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		err = client.ZRem(archiveExpiriesKey, key).Err()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
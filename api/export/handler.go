@@ -0,0 +1,82 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+
+	"github.com/allen-woods/the-supertask/api/auth"
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// PathPrefix is where archives are downloaded from, followed by the export ID.
+const PathPrefix = "/exports/"
+
+// DownloadURL is the path the archive of e is downloaded from.
+func DownloadURL(e *Export) string {
+	return PathPrefix + e.ID
+}
+
+// Handler serves the archives of ready exports to the users they belong to. It
+// must be wrapped by auth.Middleware.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID := auth.ForContext(r.Context())
+		if userID == "" {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		// Only the user may take their data out, not staff acting as them.
+		if auth.ImpersonationForContext(r.Context()) != nil {
+			http.Error(w, auth.ErrImpersonating.Error(), http.StatusForbidden)
+			return
+		}
+
+		e, err := Get(path.Base(r.URL.Path))
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Unable to read data export", zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		// Exports of other users are not acknowledged to exist.
+		if e == nil || e.UserID != userID {
+			http.NotFound(w, r)
+			return
+		}
+
+		if e.Status != StatusReady {
+			http.Error(w, "The data export is "+e.Status, http.StatusConflict)
+			return
+		}
+
+		archive, err := Open(r.Context(), e)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Unable to read data export archive", zap.String("exportId", e.ID), zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if archive == nil {
+			http.NotFound(w, r)
+			return
+		}
+		defer archive.Close()
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"the-supertask-data-%s.zip\"", e.RequestedAt.Format("2006-01-02")))
+		w.Header().Set("Cache-Control", "no-store")
+
+		_, err = io.Copy(w, archive)
+		if err != nil {
+			logging.FromContext(r.Context()).Warn("Unable to send data export archive", zap.String("exportId", e.ID), zap.Error(err))
+		}
+	})
+}
@@ -4,13 +4,32 @@ go 1.13
 
 require (
 	github.com/99designs/gqlgen v0.12.2
-	github.com/allen-woods/the-supertask/services/user v0.0.0-20200923071118-de6b4fbe444f // indirect
+	github.com/allen-woods/the-supertask/pkg v0.0.0-00010101000000-000000000000
+	github.com/allen-woods/the-supertask/services/user v0.0.0-20200923071118-de6b4fbe444f
+	github.com/aws/aws-sdk-go v1.38.40
 	github.com/go-redis/redis v6.15.9+incompatible
+	github.com/golang/protobuf v1.5.2
 	github.com/gorilla/securecookie v1.1.1
+	github.com/gorilla/websocket v1.4.2
+	github.com/grpc-ecosystem/grpc-gateway v1.16.0
+	github.com/johannesboyne/gofakes3 v0.0.0-20210217223559-02ffa763be97
+	github.com/mitchellh/mapstructure v0.0.0-20180203102830-a4e142e9c047
+	github.com/prometheus/client_golang v1.7.1
+	github.com/rs/cors v1.7.0
 	github.com/satori/go.uuid v1.2.0
 	github.com/vektah/gqlparser/v2 v2.0.1
 	go.mongodb.org/mongo-driver v1.4.1
-	golang.org/x/crypto v0.0.0-20191011191535-87dc89f01550
-	google.golang.org/grpc v1.32.0
-	google.golang.org/protobuf v1.25.0 // indirect
+	go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc v0.20.0
+	go.opentelemetry.io/otel v0.20.0
+	go.opentelemetry.io/otel/trace v0.20.0
+	go.uber.org/zap v1.16.0
+	golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9
+	golang.org/x/image v0.0.0-20210220032944-ac19c3e999fb
+	google.golang.org/grpc v1.37.0
+	google.golang.org/protobuf v1.26.0
+	gopkg.in/yaml.v2 v2.2.8
 )
+
+replace github.com/allen-woods/the-supertask/services/user => ../services/user/app
+
+replace github.com/allen-woods/the-supertask/pkg => ../pkg
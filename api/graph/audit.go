@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/allen-woods/the-supertask/api/audit"
+	"github.com/allen-woods/the-supertask/api/graph/model"
+	pb "github.com/allen-woods/the-supertask/services/user/proto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var auditOutcomes = map[pb.AuditOutcome]model.AuditOutcome{
+	pb.AuditOutcome_AUDIT_OUTCOME_SUCCESS: model.AuditOutcomeSuccess,
+	pb.AuditOutcome_AUDIT_OUTCOME_FAILURE: model.AuditOutcomeFailure,
+}
+
+// RecordAuditEvent stores an audit event of the API in the audit log kept by
+// the User service. It is meant for audit.SetRecorder.
+func RecordAuditEvent(ctx context.Context, e *pb.AuditEvent) error {
+	// The event is recorded even if the request is cancelled meanwhile.
+	ctx, cancel := context.WithTimeout(audit.Detach(ctx), time.Second)
+	defer cancel()
+
+	c, err := userServiceClient()
+	if err != nil {
+		return err
+	}
+
+	_, err = c.RecordAuditEvent(ctx, &pb.RecordAuditEventReq{Event: e})
+
+	return err
+}
+
+// auditEventFromMessage converts an AuditEvent from the User service to its
+// GraphQL model.
+func auditEventFromMessage(e *pb.AuditEvent) (*model.AuditEvent, error) {
+	id, err := primitive.ObjectIDFromHex(e.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	event := &model.AuditEvent{
+		ID:      id,
+		Action:  e.GetAction(),
+		Time:    e.GetTime().AsTime(),
+		Outcome: auditOutcomes[e.GetOutcome()],
+	}
+
+	// The actor of a system action, or an unauthenticated one, is unset.
+	if actorID, err := primitive.ObjectIDFromHex(e.GetActorId()); err == nil {
+		event.ActorID = &actorID
+	}
+	if targetID, err := primitive.ObjectIDFromHex(e.GetTargetId()); err == nil {
+		event.TargetID = &targetID
+	}
+
+	if e.GetIp() != "" {
+		ip := e.GetIp()
+		event.IP = &ip
+	}
+	if e.GetUserAgent() != "" {
+		userAgent := e.GetUserAgent()
+		event.UserAgent = &userAgent
+	}
+	if e.GetDetail() != "" {
+		detail := e.GetDetail()
+		event.Detail = &detail
+	}
+
+	return event, nil
+}
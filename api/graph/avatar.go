@@ -0,0 +1,53 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/allen-woods/the-supertask/api/avatar"
+	"github.com/allen-woods/the-supertask/api/graph/model"
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	pb "github.com/allen-woods/the-supertask/services/user/proto"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+var avatarSizes = map[model.AvatarSize]int{
+	model.AvatarSizeSmall:  avatar.SizeSmall,
+	model.AvatarSizeMedium: avatar.SizeMedium,
+	model.AvatarSizeLarge:  avatar.SizeLarge,
+}
+
+// setAvatar points the user at the avatar with the given ID, or at none.
+func setAvatar(ctx context.Context, userID string, avatarID string) (*model.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	c, err := userServiceClient()
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+
+	res, err := c.UpdateUser(ctx, &pb.UpdateUserReq{
+		User:       &pb.EditUser{Id: userID, Avatar: avatarID},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"avatar"}},
+	})
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+
+	return userFromMessage(res.GetUser())
+}
+
+// deleteAvatar removes the images of an avatar the user is no longer pointed
+// at. Images left behind only take space, so failures are only logged.
+func deleteAvatar(ctx context.Context, userID string, avatarID string) {
+	if avatarID == "" {
+		return
+	}
+
+	err := avatar.Delete(ctx, userID, avatarID)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Unable to delete the images of an avatar", zap.String("avatarId", avatarID), zap.Error(err))
+	}
+}
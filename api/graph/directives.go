@@ -0,0 +1,93 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/allen-woods/the-supertask/api/audit"
+	"github.com/allen-woods/the-supertask/api/auth"
+	"github.com/allen-woods/the-supertask/api/graph/model"
+	pb "github.com/allen-woods/the-supertask/services/user/proto"
+)
+
+// HasRole implements the @hasRole directive. The role is read from the User
+// service on every use, so that demotions and suspensions apply at once.
+func HasRole(ctx context.Context, obj interface{}, next graphql.Resolver, role model.Role) (interface{}, error) {
+	err := requireRole(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+
+	return next(ctx)
+}
+
+// IsAdmin reports whether the request is made by an active admin, even when
+// impersonating another user.
+func IsAdmin(ctx context.Context) bool {
+	return requireRole(ctx, model.RoleAdmin) == nil
+}
+
+// errNotAuthenticated and errNotAuthorized are returned when a role is
+// required of the user.
+var (
+	errNotAuthenticated = errors.New("not authenticated")
+	errNotAuthorized    = errors.New("not authorized")
+)
+
+// requireRole checks that the user is active and has the given role. During
+// an impersonation, roles are those of the admin behind it, never of the
+// impersonated user.
+func requireRole(ctx context.Context, role model.Role) error {
+	userID := auth.ForContext(ctx)
+	if i := auth.ImpersonationForContext(ctx); i != nil {
+		userID = i.ImpersonatorID
+	}
+	if userID == "" {
+		return errNotAuthenticated
+	}
+
+	readCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	c, err := userServiceClient()
+	if err != nil {
+		return userServiceError(err)
+	}
+
+	res, err := c.ReadUser(readCtx, &pb.ReadUserReq{Id: userID})
+	if err != nil {
+		return userServiceError(err)
+	}
+
+	if res.GetUser().GetStatus() != pb.Status_STATUS_ACTIVE || roles[res.GetUser().GetRole()] != role {
+		return errNotAuthorized
+	}
+
+	return nil
+}
+
+// NoImpersonation implements the @noImpersonation directive. Refusals are
+// audited, as they may be attempts to act on behalf of the user.
+func NoImpersonation(ctx context.Context, obj interface{}, next graphql.Resolver) (interface{}, error) {
+	err := refuseImpersonation(ctx, graphql.GetFieldContext(ctx).Field.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return next(ctx)
+}
+
+// refuseImpersonation fails with auth.ErrImpersonating, and audits the attempt
+// at the named operation, if the request is made under an impersonation.
+func refuseImpersonation(ctx context.Context, operation string) error {
+	i := auth.ImpersonationForContext(ctx)
+	if i == nil {
+		return nil
+	}
+
+	audit.Record(ctx, audit.ActionImpersonationDenied, i.UserID, pb.AuditOutcome_AUDIT_OUTCOME_FAILURE, operation)
+
+	return auth.ErrImpersonating
+}
@@ -0,0 +1,18 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/allen-woods/the-supertask/api/events"
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	"go.uber.org/zap"
+)
+
+// publishUserSignedUp tells the subscribers of userSignedUp about a new user.
+// The sign up stands whether or not they hear of it.
+func publishUserSignedUp(ctx context.Context, userID string) {
+	err := events.Publish(ctx, events.TopicUserSignedUp, events.UserSignedUp{UserID: userID})
+	if err != nil {
+		logging.FromContext(ctx).Warn("Unable to publish a sign up", zap.Error(err))
+	}
+}
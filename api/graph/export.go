@@ -0,0 +1,59 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/allen-woods/the-supertask/api/audit"
+	"github.com/allen-woods/the-supertask/api/export"
+	"github.com/allen-woods/the-supertask/api/graph/model"
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+var dataExportStatuses = map[string]model.DataExportStatus{
+	export.StatusPending: model.DataExportStatusPending,
+	export.StatusReady:   model.DataExportStatusReady,
+	export.StatusFailed:  model.DataExportStatusFailed,
+}
+
+// buildDataExport assembles the archive of e outside of ctx, the request that
+// asked for it, as gathering everything we store can take a while.
+func buildDataExport(ctx context.Context, e *export.Export) {
+	ctx, cancel := context.WithTimeout(audit.Detach(ctx), export.BuildTimeout)
+	defer cancel()
+
+	c, err := userServiceClient()
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to build data export", zap.String("exportId", e.ID), zap.Error(err))
+		export.Fail(e)
+		return
+	}
+
+	err = export.Build(ctx, e, c)
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to build data export", zap.String("exportId", e.ID), zap.Error(err))
+	}
+}
+
+// dataExportFromExport converts an export to its GraphQL model.
+func dataExportFromExport(e *export.Export) (*model.DataExport, error) {
+	id, err := primitive.ObjectIDFromHex(e.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	dataExport := &model.DataExport{
+		ID:          id,
+		Status:      dataExportStatuses[e.Status],
+		RequestedAt: e.RequestedAt,
+		ExpiresAt:   e.ExpiresAt,
+	}
+
+	if e.Status == export.StatusReady {
+		url := export.DownloadURL(e)
+		dataExport.DownloadURL = &url
+	}
+
+	return dataExport, nil
+}
@@ -0,0 +1,383 @@
+package graph
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/allen-woods/the-supertask/api/auth"
+	"github.com/allen-woods/the-supertask/api/graph/model"
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	pb "github.com/allen-woods/the-supertask/services/user/proto"
+	"github.com/golang/protobuf/proto"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// GatewayPathPrefix is where the REST gateway to the User service is served.
+const GatewayPathPrefix = "/v1/"
+
+// OpenAPIPath is where the OpenAPI v2 spec of the REST gateway is served.
+const OpenAPIPath = GatewayPathPrefix + "openapi.json"
+
+// PasswordHeader carries the current password that DELETE /v1/users/{id}, and
+// PATCH /v1/users/{user.id} changing the email or password, re-authenticate
+// the user with, as the GraphQL deleteUser mutation does.
+const PasswordHeader = "X-Password"
+
+var passwordCtxKey = &contextKey{"password"}
+
+type contextKey struct {
+	name string
+}
+
+// editableFields are the fields of a user that PATCH /v1/users/{user.id} may
+// change, and whether changing them requires the current password, as they
+// would let an unattended session take the account over.
+var editableFields = map[string]bool{
+	"email":    true,
+	"name":     false,
+	"userName": false,
+	"password": true,
+}
+
+// GatewayHandler serves the HTTP routes of UserCRUD as JSON, by calling the
+// User service under the same rules as the GraphQL schema. The users listed by
+// GET /v1/users are streamed as newline delimited JSON.
+func GatewayHandler() http.Handler {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{OrigName: true, EmitDefaults: true}),
+		// No request header is passed on to the User service, for the
+		// service token and cookies must not be forged or leaked.
+		runtime.WithIncomingHeaderMatcher(func(string) (string, bool) {
+			return "", false
+		}),
+		runtime.WithForwardResponseOption(streamContentType),
+	)
+
+	// The client only calls the User service when called, so this cannot fail.
+	err := pb.RegisterUserCRUDHandlerClient(context.Background(), mux, gatewayClient{})
+	if err != nil {
+		panic(err)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == OpenAPIPath {
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, pb.OpenAPIv2)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), passwordCtxKey, r.Header.Get(PasswordHeader))
+		mux.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// streamContentType marks streamed responses as newline delimited JSON. The
+// gateway only passes no message when it starts a stream.
+func streamContentType(ctx context.Context, w http.ResponseWriter, m proto.Message) error {
+	if m == nil {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	return nil
+}
+
+// gatewayClient calls the User service on behalf of the REST gateway, applying
+// the rules of the GraphQL schema first. Only the methods with HTTP routes are
+// ever called by the gateway.
+type gatewayClient struct {
+	pb.UserCRUDClient
+}
+
+// CreateUser signs the user up and logs them in, as signUpUser does.
+func (gatewayClient) CreateUser(ctx context.Context, in *pb.CreateUserReq, opts ...grpc.CallOption) (*pb.CreateUserRes, error) {
+	user := in.GetUser()
+	if user == nil {
+		return nil, status.Error(codes.InvalidArgument, "a user is required")
+	}
+
+	// Hashing is deliberately slow, so it gets a span of its own.
+	_, span := otel.Tracer(tracerName).Start(ctx, "bcrypt")
+	securePassword, err := auth.HashAndSalt(user.GetPassword())
+	span.End()
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to hash and salt password", zap.Error(err))
+		return nil, status.Error(codes.Internal, "unable to create the user")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	c, err := userServiceClient()
+	if err != nil {
+		return nil, gatewayError(err)
+	}
+
+	res, err := c.CreateUser(ctx, &pb.CreateUserReq{
+		User: &pb.NewUser{
+			Email:    user.GetEmail(),
+			Name:     user.GetName(),
+			UserName: user.GetUserName(),
+			Password: securePassword,
+		},
+	}, opts...)
+	if err != nil {
+		return nil, gatewayError(err)
+	}
+
+	err = auth.LogIn(ctx, res.GetUser().GetId())
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to log in a new user", zap.Error(err))
+		return nil, status.Error(codes.Internal, "signed up, but unable to log in")
+	}
+
+	publishUserSignedUp(ctx, res.GetUser().GetId())
+
+	return res, nil
+}
+
+// ReadUser reads the user themself, or anyone for an admin.
+func (gatewayClient) ReadUser(ctx context.Context, in *pb.ReadUserReq, opts ...grpc.CallOption) (*pb.ReadUserRes, error) {
+	err := requireSelfOrAdmin(ctx, in.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	c, err := userServiceClient()
+	if err != nil {
+		return nil, gatewayError(err)
+	}
+
+	res, err := c.ReadUser(ctx, in, opts...)
+	if err != nil {
+		return nil, gatewayError(err)
+	}
+
+	return res, nil
+}
+
+// UpdateUser changes the fields of the user themself that are given in the
+// body, or named by the update mask. The password is hashed first. Changing
+// the email or password requires the current password in PasswordHeader.
+func (gatewayClient) UpdateUser(ctx context.Context, in *pb.UpdateUserReq, opts ...grpc.CallOption) (*pb.UpdateUserRes, error) {
+	user := in.GetUser()
+
+	err := requireSelf(ctx, user.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = refuseImpersonation(ctx, "UpdateUser")
+	if err != nil {
+		return nil, gatewayError(err)
+	}
+
+	// The User service changes every field given no mask, which would clear
+	// those left out of a partial update.
+	mask := in.GetUpdateMask().GetPaths()
+	if len(mask) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one field must be updated")
+	}
+
+	req := &pb.UpdateUserReq{
+		User: &pb.EditUser{
+			Id:       user.GetId(),
+			Email:    user.GetEmail(),
+			Name:     user.GetName(),
+			UserName: user.GetUserName(),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{},
+	}
+
+	sensitive := false
+
+	for _, field := range mask {
+		requiresPassword, ok := editableFields[field]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "%s cannot be updated", field)
+		}
+		sensitive = sensitive || requiresPassword
+		req.UpdateMask.Paths = append(req.UpdateMask.Paths, field)
+
+		if field == "password" {
+			_, span := otel.Tracer(tracerName).Start(ctx, "bcrypt")
+			req.User.Password, err = auth.HashAndSalt(user.GetPassword())
+			span.End()
+			if err != nil {
+				logging.FromContext(ctx).Error("Failed to hash and salt password", zap.Error(err))
+				return nil, status.Error(codes.Internal, "unable to update the user")
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	c, err := userServiceClient()
+	if err != nil {
+		return nil, gatewayError(err)
+	}
+
+	if sensitive {
+		err = reauthenticate(ctx, c, user.GetId())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := c.UpdateUser(ctx, req, opts...)
+	if err != nil {
+		return nil, gatewayError(err)
+	}
+
+	return res, nil
+}
+
+// DeleteUser marks the account of the user themself for deletion, once they
+// have given their password in PasswordHeader, as deleteUser does.
+func (gatewayClient) DeleteUser(ctx context.Context, in *pb.DeleteUserReq, opts ...grpc.CallOption) (*pb.DeleteUserRes, error) {
+	err := requireSelf(ctx, in.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = refuseImpersonation(ctx, "DeleteUser")
+	if err != nil {
+		return nil, gatewayError(err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	c, err := userServiceClient()
+	if err != nil {
+		return nil, gatewayError(err)
+	}
+
+	err = reauthenticate(ctx, c, in.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.DeleteUser(ctx, in, opts...)
+	if err != nil {
+		return nil, gatewayError(err)
+	}
+
+	// The account is no longer active, so every session of it ends now.
+	err = auth.RevokeUserSessions(ctx, in.GetId(), auth.RevokedAccountDeleted)
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to revoke the sessions of a deleted user", zap.Error(err))
+		return nil, status.Error(codes.Internal, "unable to log the user out")
+	}
+
+	return res, nil
+}
+
+// ListUsers streams every user to admins.
+func (gatewayClient) ListUsers(ctx context.Context, in *pb.ListUsersReq, opts ...grpc.CallOption) (pb.UserCRUD_ListUsersClient, error) {
+	err := requireRole(ctx, model.RoleAdmin)
+	if err != nil {
+		return nil, gatewayError(err)
+	}
+
+	c, err := userServiceClient()
+	if err != nil {
+		return nil, gatewayError(err)
+	}
+
+	// The gateway cancels ctx once the response has been written, whether
+	// the stream was read to its end or not, which ends the stream.
+	stream, err := c.ListUsers(ctx, in, opts...)
+	if err != nil {
+		return nil, gatewayError(err)
+	}
+
+	return gatewayStream{stream}, nil
+}
+
+// gatewayStream sanitizes the errors of a stream of users.
+type gatewayStream struct {
+	pb.UserCRUD_ListUsersClient
+}
+
+// Recv returns the next user, io.EOF at the end, or a sanitized error.
+func (s gatewayStream) Recv() (*pb.ListUsersRes, error) {
+	res, err := s.UserCRUD_ListUsersClient.Recv()
+	if err != nil && err != io.EOF {
+		return nil, gatewayError(err)
+	}
+	return res, err
+}
+
+// reauthenticate checks the password given in PasswordHeader against the
+// user with the given ID, so that an unattended session cannot take over or
+// delete the account.
+func reauthenticate(ctx context.Context, c pb.UserCRUDClient, id string) error {
+	password, _ := ctx.Value(passwordCtxKey).(string)
+	if password == "" {
+		return status.Errorf(codes.Unauthenticated, "the current password must be given in %s", PasswordHeader)
+	}
+
+	_, err := c.AuthenticateUser(ctx, &pb.AuthenticateUserReq{
+		Id:       id,
+		Password: password,
+	})
+	return gatewayError(err)
+}
+
+// requireSelf checks that the request is made by the user with the given ID.
+func requireSelf(ctx context.Context, id string) error {
+	userID := auth.ForContext(ctx)
+	if userID == "" {
+		return gatewayError(errNotAuthenticated)
+	}
+	if userID != id {
+		return gatewayError(errNotAuthorized)
+	}
+	return nil
+}
+
+// requireSelfOrAdmin checks that the request is made by the user with the
+// given ID, or by an admin.
+func requireSelfOrAdmin(ctx context.Context, id string) error {
+	userID := auth.ForContext(ctx)
+	if userID != "" && userID == id {
+		return nil
+	}
+	return gatewayError(requireRole(ctx, model.RoleAdmin))
+}
+
+// gatewayError converts an error into a status that is safe to return to REST
+// clients, keeping the code of the errors of the User service.
+func gatewayError(err error) error {
+	switch err {
+	case nil:
+		return nil
+	case errNotAuthenticated:
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errNotAuthorized, auth.ErrImpersonating:
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return status.Error(codes.Unavailable, userServiceError(err).Error())
+	}
+
+	switch s.Code() {
+	case codes.NotFound, codes.Unauthenticated, codes.InvalidArgument, codes.AlreadyExists, codes.FailedPrecondition, codes.PermissionDenied:
+		return status.Error(s.Code(), userServiceError(err).Error())
+	}
+
+	return status.Error(codes.Unavailable, userServiceError(err).Error())
+}
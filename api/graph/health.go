@@ -0,0 +1,32 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// userCRUDServiceName is the name the User service reports the health of the
+// UserCRUD service under.
+const userCRUDServiceName = "user.UserCRUD"
+
+// CheckUserService reports whether the User service is serving, as told by its
+// grpc.health.v1 service.
+func CheckUserService(ctx context.Context) error {
+	conn, err := ConnectUserService()
+	if err != nil {
+		return err
+	}
+
+	res, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: userCRUDServiceName})
+	if err != nil {
+		return err
+	}
+
+	if res.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("the user service reports %s", res.GetStatus())
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	pb "github.com/allen-woods/the-supertask/services/user/proto"
+	"github.com/vektah/gqlparser/v2/ast"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Batches of users are read once loaderWait has passed since their first user
+// was asked for, or once they hold loaderMaxBatch users, which is as many as
+// BatchReadUsers takes.
+const (
+	loaderWait     = 2 * time.Millisecond
+	loaderMaxBatch = 100
+)
+
+var userLoaderCtxKey = &contextKey{"userLoader"}
+
+// UserLoaderOperationMiddleware gives every query and mutation a user loader
+// of its own, so that the users it reads are read together and only once.
+// Subscriptions get none, as they outlive what should be cached.
+func UserLoaderOperationMiddleware(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	oc := graphql.GetOperationContext(ctx)
+	if oc.Operation != nil && oc.Operation.Operation == ast.Subscription {
+		return next(ctx)
+	}
+
+	return next(context.WithValue(ctx, userLoaderCtxKey, newUserLoader(ctx)))
+}
+
+// userLoaderFor returns the user loader of the operation ctx belongs to, or
+// nil outside of one.
+func userLoaderFor(ctx context.Context) *userLoader {
+	l, _ := ctx.Value(userLoaderCtxKey).(*userLoader)
+	return l
+}
+
+// userLoader coalesces the users read at about the same time into a single
+// BatchReadUsers call, and caches them, failures included.
+type userLoader struct {
+	// ctx is the context of the operation, which the batches are read under
+	// whichever resolver started them.
+	ctx context.Context
+
+	mu      sync.Mutex
+	results map[string]*userResult
+	batch   *userBatch
+}
+
+// userResult is a user being read, which is ready once done is closed.
+type userResult struct {
+	done chan struct{}
+	user *pb.User
+	err  error
+}
+
+// userBatch is a set of users read together.
+type userBatch struct {
+	ids     []string
+	results []*userResult
+	sent    bool
+}
+
+func newUserLoader(ctx context.Context) *userLoader {
+	return &userLoader{
+		ctx:     ctx,
+		results: map[string]*userResult{},
+	}
+}
+
+// load returns the user with the given ID, reading it along with the others
+// asked for at about the same time, unless it has been read already.
+func (l *userLoader) load(ctx context.Context, id string) (*pb.User, error) {
+	l.mu.Lock()
+
+	r, ok := l.results[id]
+	if !ok {
+		r = &userResult{done: make(chan struct{})}
+		l.results[id] = r
+
+		if l.batch == nil {
+			b := &userBatch{}
+			l.batch = b
+			time.AfterFunc(loaderWait, func() { l.send(b) })
+		}
+
+		b := l.batch
+		b.ids = append(b.ids, id)
+		b.results = append(b.results, r)
+
+		if len(b.ids) == loaderMaxBatch {
+			go l.send(b)
+		}
+	}
+
+	l.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return r.user, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// send reads the users of the batch, unless it has been sent already.
+func (l *userLoader) send(b *userBatch) {
+	l.mu.Lock()
+	if b.sent {
+		l.mu.Unlock()
+		return
+	}
+	b.sent = true
+	if l.batch == b {
+		l.batch = nil
+	}
+	l.mu.Unlock()
+
+	users, err := batchReadUsers(l.ctx, b.ids)
+
+	for i, r := range b.results {
+		switch u, ok := users[b.ids[i]]; {
+		case err != nil:
+			r.err = err
+		case !ok:
+			r.err = status.Errorf(codes.NotFound, "Could not find user with ID %s", b.ids[i])
+		default:
+			r.user = u
+		}
+
+		close(r.done)
+	}
+}
+
+// batchReadUsers reads the users with the given IDs from the User service,
+// keyed by ID. Unknown users are left out.
+func batchReadUsers(ctx context.Context, ids []string) (map[string]*pb.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	c, err := userServiceClient()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.BatchReadUsers(ctx, &pb.BatchReadUsersReq{Ids: ids})
+	if err != nil {
+		return nil, err
+	}
+
+	users := make(map[string]*pb.User, len(res.GetUsers()))
+	for _, u := range res.GetUsers() {
+		users[u.GetId()] = u
+	}
+
+	return users, nil
+}
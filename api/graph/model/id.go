@@ -3,11 +3,11 @@ package model
 import (
 	"errors"
 	"io"
-	"log"
 	"strconv"
 
 	graphql "github.com/99designs/gqlgen/graphql"
 	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
 )
 
 func MarshalID(id primitive.ObjectID) graphql.Marshaler {
@@ -15,7 +15,7 @@ func MarshalID(id primitive.ObjectID) graphql.Marshaler {
 		json, err := id.MarshalJSON()
 
 		if err != nil {
-			log.Fatal("Error:", err)
+			zap.L().Fatal("Unable to marshal ID", zap.Error(err))
 		}
 
 		io.WriteString(w, strconv.Quote(string(json)))
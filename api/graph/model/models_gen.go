@@ -0,0 +1,617 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package model
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// An entry of the security audit log.
+type AuditEvent struct {
+	ID primitive.ObjectID `json:"id"`
+	// Such as user.loggedIn or session.loggedOut.
+	Action string `json:"action"`
+	// Unset when nobody was authenticated, or the system acted.
+	ActorID *primitive.ObjectID `json:"actorId"`
+	// Unset when the action was not about a known user.
+	TargetID  *primitive.ObjectID `json:"targetId"`
+	IP        *string             `json:"ip"`
+	UserAgent *string             `json:"userAgent"`
+	Time      time.Time           `json:"time"`
+	Outcome   AuditOutcome        `json:"outcome"`
+	Detail    *string             `json:"detail"`
+}
+
+type AuditEventConnection struct {
+	Edges    []*AuditEventEdge `json:"edges"`
+	PageInfo *PageInfo         `json:"pageInfo"`
+}
+
+type AuditEventEdge struct {
+	Cursor string      `json:"cursor"`
+	Node   *AuditEvent `json:"node"`
+}
+
+// Every field that is set must match.
+type AuditEventFilter struct {
+	ActorID  *primitive.ObjectID `json:"actorId"`
+	TargetID *primitive.ObjectID `json:"targetId"`
+	// Matches the events whose actor or target is this user.
+	UserID *primitive.ObjectID `json:"userId"`
+	Action *string             `json:"action"`
+	Since  *time.Time          `json:"since"`
+	Until  *time.Time          `json:"until"`
+}
+
+// An archive of everything stored about the current user.
+type DataExport struct {
+	ID          primitive.ObjectID `json:"id"`
+	Status      DataExportStatus   `json:"status"`
+	RequestedAt time.Time          `json:"requestedAt"`
+	// When the export and its archive are deleted.
+	ExpiresAt time.Time `json:"expiresAt"`
+	// Set once the export is ready, to where the archive is downloaded from.
+	DownloadURL *string `json:"downloadUrl"`
+}
+
+// The views the app opens on.
+type DefaultViews struct {
+	Tasks    TaskView     `json:"tasks"`
+	Calendar CalendarView `json:"calendar"`
+}
+
+type DefaultViewsInput struct {
+	Tasks    *TaskView     `json:"tasks"`
+	Calendar *CalendarView `json:"calendar"`
+}
+
+type Impersonation struct {
+	Reason string `json:"reason"`
+	// Impersonations cannot be extended, only started again.
+	EndsAt time.Time `json:"endsAt"`
+}
+
+// The emails a user wants to receive.
+type NotificationPreferences struct {
+	SecurityAlerts bool `json:"securityAlerts"`
+	TaskReminders  bool `json:"taskReminders"`
+	ProductUpdates bool `json:"productUpdates"`
+}
+
+type NotificationPreferencesInput struct {
+	SecurityAlerts *bool `json:"securityAlerts"`
+	TaskReminders  *bool `json:"taskReminders"`
+	ProductUpdates *bool `json:"productUpdates"`
+}
+
+type PageInfo struct {
+	EndCursor   *string `json:"endCursor"`
+	HasNextPage bool    `json:"hasNextPage"`
+}
+
+// How a user wants the app to behave. Users who never set theirs have the defaults.
+type Preferences struct {
+	Theme         Theme                    `json:"theme"`
+	Notifications *NotificationPreferences `json:"notifications"`
+	DefaultViews  *DefaultViews            `json:"defaultViews"`
+	Privacy       *PrivacyPreferences      `json:"privacy"`
+	// Counts the changes of the preferences, from 0; updatePreferences is given the version the changes were made to.
+	Version int `json:"version"`
+}
+
+// Changes to preferences. Fields left out, or null, are left as they are.
+type PreferencesInput struct {
+	Theme         *Theme                        `json:"theme"`
+	Notifications *NotificationPreferencesInput `json:"notifications"`
+	DefaultViews  *DefaultViewsInput            `json:"defaultViews"`
+	Privacy       *PrivacyPreferencesInput      `json:"privacy"`
+}
+
+type PrivacyPreferences struct {
+	ProfileVisibility ProfileVisibility `json:"profileVisibility"`
+}
+
+type PrivacyPreferencesInput struct {
+	ProfileVisibility *ProfileVisibility `json:"profileVisibility"`
+}
+
+// What a user shows others about themself. Fields the user has not set are null.
+type Profile struct {
+	DisplayName *string        `json:"displayName"`
+	Bio         *string        `json:"bio"`
+	Links       []*ProfileLink `json:"links"`
+	Location    *string        `json:"location"`
+	// An IANA time zone, such as Europe/Paris.
+	Timezone *string `json:"timezone"`
+	// A BCP 47 language tag, such as en-US.
+	Locale   *string `json:"locale"`
+	Pronouns *string `json:"pronouns"`
+	// Counts the changes of the profile, from 0; updateProfile is given the version the changes were made to.
+	Version int `json:"version"`
+}
+
+// Changes to a profile. Fields left out, or null, are left as they are; empty
+// strings and lists clear them.
+type ProfileInput struct {
+	DisplayName *string `json:"displayName"`
+	Bio         *string `json:"bio"`
+	// At most 5, of http or https URLs.
+	Links    []*ProfileLinkInput `json:"links"`
+	Location *string             `json:"location"`
+	Timezone *string             `json:"timezone"`
+	Locale   *string             `json:"locale"`
+	Pronouns *string             `json:"pronouns"`
+}
+
+// A website of a user, such as a blog.
+type ProfileLink struct {
+	Label *string `json:"label"`
+	URL   string  `json:"url"`
+}
+
+type ProfileLinkInput struct {
+	Label *string `json:"label"`
+	URL   string  `json:"url"`
+}
+
+// The revocation of every session of a user.
+type SessionRevocation struct {
+	Reason    SessionRevocationReason `json:"reason"`
+	RevokedAt time.Time               `json:"revokedAt"`
+}
+
+type Suspension struct {
+	Reason string `json:"reason"`
+	// Unset when the suspension lasts until the user is reinstated.
+	Until *time.Time `json:"until"`
+}
+
+// Who the current session acts as.
+type Viewer struct {
+	// Unset when not authenticated.
+	User *User `json:"user"`
+	// Set while an admin is impersonating the user, to the admin.
+	ImpersonatedBy *User          `json:"impersonatedBy"`
+	Impersonation  *Impersonation `json:"impersonation"`
+	// Those of the user; unset when not authenticated.
+	Preferences *Preferences `json:"preferences"`
+}
+
+type AccountStatus string
+
+const (
+	AccountStatusPendingVerification AccountStatus = "PENDING_VERIFICATION"
+	AccountStatusActive              AccountStatus = "ACTIVE"
+	AccountStatusSuspended           AccountStatus = "SUSPENDED"
+	AccountStatusPendingDeletion     AccountStatus = "PENDING_DELETION"
+	AccountStatusDeleted             AccountStatus = "DELETED"
+)
+
+var AllAccountStatus = []AccountStatus{
+	AccountStatusPendingVerification,
+	AccountStatusActive,
+	AccountStatusSuspended,
+	AccountStatusPendingDeletion,
+	AccountStatusDeleted,
+}
+
+func (e AccountStatus) IsValid() bool {
+	switch e {
+	case AccountStatusPendingVerification, AccountStatusActive, AccountStatusSuspended, AccountStatusPendingDeletion, AccountStatusDeleted:
+		return true
+	}
+	return false
+}
+
+func (e AccountStatus) String() string {
+	return string(e)
+}
+
+func (e *AccountStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AccountStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AccountStatus", str)
+	}
+	return nil
+}
+
+func (e AccountStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type AuditOutcome string
+
+const (
+	AuditOutcomeSuccess AuditOutcome = "SUCCESS"
+	AuditOutcomeFailure AuditOutcome = "FAILURE"
+)
+
+var AllAuditOutcome = []AuditOutcome{
+	AuditOutcomeSuccess,
+	AuditOutcomeFailure,
+}
+
+func (e AuditOutcome) IsValid() bool {
+	switch e {
+	case AuditOutcomeSuccess, AuditOutcomeFailure:
+		return true
+	}
+	return false
+}
+
+func (e AuditOutcome) String() string {
+	return string(e)
+}
+
+func (e *AuditOutcome) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AuditOutcome(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AuditOutcome", str)
+	}
+	return nil
+}
+
+func (e AuditOutcome) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// Sizes of avatars: 64, 256 and 512 pixels square.
+type AvatarSize string
+
+const (
+	AvatarSizeSmall  AvatarSize = "SMALL"
+	AvatarSizeMedium AvatarSize = "MEDIUM"
+	AvatarSizeLarge  AvatarSize = "LARGE"
+)
+
+var AllAvatarSize = []AvatarSize{
+	AvatarSizeSmall,
+	AvatarSizeMedium,
+	AvatarSizeLarge,
+}
+
+func (e AvatarSize) IsValid() bool {
+	switch e {
+	case AvatarSizeSmall, AvatarSizeMedium, AvatarSizeLarge:
+		return true
+	}
+	return false
+}
+
+func (e AvatarSize) String() string {
+	return string(e)
+}
+
+func (e *AvatarSize) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AvatarSize(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AvatarSize", str)
+	}
+	return nil
+}
+
+func (e AvatarSize) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CalendarView string
+
+const (
+	CalendarViewDay   CalendarView = "DAY"
+	CalendarViewWeek  CalendarView = "WEEK"
+	CalendarViewMonth CalendarView = "MONTH"
+)
+
+var AllCalendarView = []CalendarView{
+	CalendarViewDay,
+	CalendarViewWeek,
+	CalendarViewMonth,
+}
+
+func (e CalendarView) IsValid() bool {
+	switch e {
+	case CalendarViewDay, CalendarViewWeek, CalendarViewMonth:
+		return true
+	}
+	return false
+}
+
+func (e CalendarView) String() string {
+	return string(e)
+}
+
+func (e *CalendarView) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CalendarView(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CalendarView", str)
+	}
+	return nil
+}
+
+func (e CalendarView) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type DataExportStatus string
+
+const (
+	DataExportStatusPending DataExportStatus = "PENDING"
+	DataExportStatusReady   DataExportStatus = "READY"
+	DataExportStatusFailed  DataExportStatus = "FAILED"
+)
+
+var AllDataExportStatus = []DataExportStatus{
+	DataExportStatusPending,
+	DataExportStatusReady,
+	DataExportStatusFailed,
+}
+
+func (e DataExportStatus) IsValid() bool {
+	switch e {
+	case DataExportStatusPending, DataExportStatusReady, DataExportStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e DataExportStatus) String() string {
+	return string(e)
+}
+
+func (e *DataExportStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DataExportStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DataExportStatus", str)
+	}
+	return nil
+}
+
+func (e DataExportStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// Who can see the profile of a user.
+type ProfileVisibility string
+
+const (
+	// Anyone, even without an account.
+	ProfileVisibilityPublic ProfileVisibility = "PUBLIC"
+	// Authenticated users.
+	ProfileVisibilityMembers ProfileVisibility = "MEMBERS"
+	// The user alone, and admins.
+	ProfileVisibilityHidden ProfileVisibility = "HIDDEN"
+)
+
+var AllProfileVisibility = []ProfileVisibility{
+	ProfileVisibilityPublic,
+	ProfileVisibilityMembers,
+	ProfileVisibilityHidden,
+}
+
+func (e ProfileVisibility) IsValid() bool {
+	switch e {
+	case ProfileVisibilityPublic, ProfileVisibilityMembers, ProfileVisibilityHidden:
+		return true
+	}
+	return false
+}
+
+func (e ProfileVisibility) String() string {
+	return string(e)
+}
+
+func (e *ProfileVisibility) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProfileVisibility(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProfileVisibility", str)
+	}
+	return nil
+}
+
+func (e ProfileVisibility) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type Role string
+
+const (
+	RoleUser  Role = "USER"
+	RoleAdmin Role = "ADMIN"
+)
+
+var AllRole = []Role{
+	RoleUser,
+	RoleAdmin,
+}
+
+func (e Role) IsValid() bool {
+	switch e {
+	case RoleUser, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+func (e Role) String() string {
+	return string(e)
+}
+
+func (e *Role) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Role(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Role", str)
+	}
+	return nil
+}
+
+func (e Role) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SessionRevocationReason string
+
+const (
+	SessionRevocationReasonAccountSuspended SessionRevocationReason = "ACCOUNT_SUSPENDED"
+	SessionRevocationReasonAccountDeleted   SessionRevocationReason = "ACCOUNT_DELETED"
+)
+
+var AllSessionRevocationReason = []SessionRevocationReason{
+	SessionRevocationReasonAccountSuspended,
+	SessionRevocationReasonAccountDeleted,
+}
+
+func (e SessionRevocationReason) IsValid() bool {
+	switch e {
+	case SessionRevocationReasonAccountSuspended, SessionRevocationReasonAccountDeleted:
+		return true
+	}
+	return false
+}
+
+func (e SessionRevocationReason) String() string {
+	return string(e)
+}
+
+func (e *SessionRevocationReason) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SessionRevocationReason(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SessionRevocationReason", str)
+	}
+	return nil
+}
+
+func (e SessionRevocationReason) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type TaskView string
+
+const (
+	TaskViewList     TaskView = "LIST"
+	TaskViewBoard    TaskView = "BOARD"
+	TaskViewCalendar TaskView = "CALENDAR"
+)
+
+var AllTaskView = []TaskView{
+	TaskViewList,
+	TaskViewBoard,
+	TaskViewCalendar,
+}
+
+func (e TaskView) IsValid() bool {
+	switch e {
+	case TaskViewList, TaskViewBoard, TaskViewCalendar:
+		return true
+	}
+	return false
+}
+
+func (e TaskView) String() string {
+	return string(e)
+}
+
+func (e *TaskView) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TaskView(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TaskView", str)
+	}
+	return nil
+}
+
+func (e TaskView) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type Theme string
+
+const (
+	// Follows the settings of the device.
+	ThemeSystem Theme = "SYSTEM"
+	ThemeLight  Theme = "LIGHT"
+	ThemeDark   Theme = "DARK"
+)
+
+var AllTheme = []Theme{
+	ThemeSystem,
+	ThemeLight,
+	ThemeDark,
+}
+
+func (e Theme) IsValid() bool {
+	switch e {
+	case ThemeSystem, ThemeLight, ThemeDark:
+		return true
+	}
+	return false
+}
+
+func (e Theme) String() string {
+	return string(e)
+}
+
+func (e *Theme) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Theme(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Theme", str)
+	}
+	return nil
+}
+
+func (e Theme) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -12,9 +14,28 @@ type NewUser struct {
 }
 
 type User struct {
-	ID       primitive.ObjectID `bson:"_id,omitempty"`
-	Email    string
-	Name     string
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	Email       string
+	Name        string
+	UserName    string
+	Password    string
+	DeleteAfter *time.Time
+	Status      AccountStatus
+	Role        Role
+	Suspension  *Suspension
+	// Avatar is the ID of the avatar of the user, if any.
+	Avatar  string
+	Profile *Profile
+	// Preferences are only exposed to the user, through the viewer.
+	Preferences *Preferences
+}
+
+// PublicUser is what anyone may see of a user.
+type PublicUser struct {
+	ID       primitive.ObjectID
 	UserName string
-	Password string
+	Name     string
+	// Avatar is the ID of the avatar of the user, if any.
+	Avatar  string
+	Profile *Profile
 }
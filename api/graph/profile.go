@@ -0,0 +1,305 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/allen-woods/the-supertask/api/auth"
+	"github.com/allen-woods/the-supertask/api/graph/model"
+	pb "github.com/allen-woods/the-supertask/services/user/proto"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// ErrVersionConflict is the code of the errors returned when a profile or
+// preferences changed since the version that changes were made to.
+const ErrVersionConflict = "VERSION_CONFLICT"
+
+var themes = map[pb.Theme]model.Theme{
+	pb.Theme_THEME_SYSTEM: model.ThemeSystem,
+	pb.Theme_THEME_LIGHT:  model.ThemeLight,
+	pb.Theme_THEME_DARK:   model.ThemeDark,
+}
+
+var taskViews = map[pb.TaskView]model.TaskView{
+	pb.TaskView_TASK_VIEW_LIST:     model.TaskViewList,
+	pb.TaskView_TASK_VIEW_BOARD:    model.TaskViewBoard,
+	pb.TaskView_TASK_VIEW_CALENDAR: model.TaskViewCalendar,
+}
+
+var calendarViews = map[pb.CalendarView]model.CalendarView{
+	pb.CalendarView_CALENDAR_VIEW_DAY:   model.CalendarViewDay,
+	pb.CalendarView_CALENDAR_VIEW_WEEK:  model.CalendarViewWeek,
+	pb.CalendarView_CALENDAR_VIEW_MONTH: model.CalendarViewMonth,
+}
+
+var visibilities = map[pb.ProfileVisibility]model.ProfileVisibility{
+	pb.ProfileVisibility_PROFILE_VISIBILITY_PUBLIC:  model.ProfileVisibilityPublic,
+	pb.ProfileVisibility_PROFILE_VISIBILITY_MEMBERS: model.ProfileVisibilityMembers,
+	pb.ProfileVisibility_PROFILE_VISIBILITY_HIDDEN:  model.ProfileVisibilityHidden,
+}
+
+// readPublicUser reads the user with the given user name, current or
+// recently changed, from the User service, and returns what the viewer may
+// see of them: nil when there is no such user, or their profile is not
+// visible to the viewer, so that hidden profiles cannot be told from missing
+// ones.
+func readPublicUser(ctx context.Context, userName string) (*model.PublicUser, error) {
+	readCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	c, err := userServiceClient()
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+
+	res, err := c.ReadUserByUserName(readCtx, &pb.ReadUserByUserNameReq{UserName: userName})
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+
+	u := res.GetUser()
+
+	// Only active accounts have a page; suspended ones are not to be
+	// told from missing ones either.
+	if u.GetStatus() != pb.Status_STATUS_ACTIVE {
+		return nil, nil
+	}
+
+	viewerID := auth.ForContext(ctx)
+
+	switch visibilities[u.GetPreferences().GetPrivacy().GetProfileVisibility()] {
+	case model.ProfileVisibilityMembers:
+		if viewerID == "" {
+			return nil, nil
+		}
+	case model.ProfileVisibilityHidden:
+		if viewerID != u.GetId() && requireRole(ctx, model.RoleAdmin) != nil {
+			return nil, nil
+		}
+	}
+
+	user, err := userFromMessage(u)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.PublicUser{
+		ID:       user.ID,
+		UserName: user.UserName,
+		Name:     user.Name,
+		Avatar:   user.Avatar,
+		Profile:  user.Profile,
+	}, nil
+}
+
+// updateProfile has the User service apply the changes of input to the
+// profile of the user, provided it is still at version.
+func updateProfile(ctx context.Context, userID string, input model.ProfileInput, version int) (*model.User, error) {
+	profile := &pb.Profile{Version: int64(version)}
+	mask := []string{}
+
+	set := func(field string, value *string, dst *string) {
+		if value != nil {
+			*dst = *value
+			mask = append(mask, field)
+		}
+	}
+
+	set("displayName", input.DisplayName, &profile.DisplayName)
+	set("bio", input.Bio, &profile.Bio)
+	set("location", input.Location, &profile.Location)
+	set("timezone", input.Timezone, &profile.Timezone)
+	set("locale", input.Locale, &profile.Locale)
+	set("pronouns", input.Pronouns, &profile.Pronouns)
+
+	if input.Links != nil {
+		for _, l := range input.Links {
+			link := &pb.ProfileLink{Url: l.URL}
+			if l.Label != nil {
+				link.Label = *l.Label
+			}
+			profile.Links = append(profile.Links, link)
+		}
+		mask = append(mask, "links")
+	}
+
+	// The User service changes every field given no mask.
+	if len(mask) == 0 {
+		return nil, errors.New("at least one field must be updated")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	c, err := userServiceClient()
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+
+	res, err := c.UpdateProfile(ctx, &pb.UpdateProfileReq{
+		Id:         userID,
+		Profile:    profile,
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: mask},
+	})
+	if err != nil {
+		return nil, versionedUpdateError(err)
+	}
+
+	return userFromMessage(res.GetUser())
+}
+
+// updatePreferences has the User service apply the changes of input to the
+// preferences of the user, provided they are still at version.
+func updatePreferences(ctx context.Context, userID string, input model.PreferencesInput, version int) (*model.Preferences, error) {
+	prefs := &pb.Preferences{
+		Notifications: &pb.NotificationPreferences{},
+		DefaultViews:  &pb.DefaultViews{},
+		Version:       int64(version),
+	}
+	mask := []string{}
+
+	if input.Theme != nil {
+		for e, theme := range themes {
+			if theme == *input.Theme {
+				prefs.Theme = e
+			}
+		}
+		mask = append(mask, "theme")
+	}
+
+	set := func(field string, value *bool, dst *bool) {
+		if value != nil {
+			*dst = *value
+			mask = append(mask, field)
+		}
+	}
+
+	if n := input.Notifications; n != nil {
+		set("notifications.securityAlerts", n.SecurityAlerts, &prefs.Notifications.SecurityAlerts)
+		set("notifications.taskReminders", n.TaskReminders, &prefs.Notifications.TaskReminders)
+		set("notifications.productUpdates", n.ProductUpdates, &prefs.Notifications.ProductUpdates)
+	}
+
+	if v := input.DefaultViews; v != nil {
+		if v.Tasks != nil {
+			for e, view := range taskViews {
+				if view == *v.Tasks {
+					prefs.DefaultViews.Tasks = e
+				}
+			}
+			mask = append(mask, "defaultViews.tasks")
+		}
+		if v.Calendar != nil {
+			for e, view := range calendarViews {
+				if view == *v.Calendar {
+					prefs.DefaultViews.Calendar = e
+				}
+			}
+			mask = append(mask, "defaultViews.calendar")
+		}
+	}
+
+	if p := input.Privacy; p != nil && p.ProfileVisibility != nil {
+		prefs.Privacy = &pb.PrivacyPreferences{}
+		for e, visibility := range visibilities {
+			if visibility == *p.ProfileVisibility {
+				prefs.Privacy.ProfileVisibility = e
+			}
+		}
+		mask = append(mask, "privacy.profileVisibility")
+	}
+
+	if len(mask) == 0 {
+		return nil, errors.New("at least one preference must be updated")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	c, err := userServiceClient()
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+
+	res, err := c.UpdatePreferences(ctx, &pb.UpdatePreferencesReq{
+		Id:          userID,
+		Preferences: prefs,
+		UpdateMask:  &fieldmaskpb.FieldMask{Paths: mask},
+	})
+	if err != nil {
+		return nil, versionedUpdateError(err)
+	}
+
+	return preferencesFromMessage(res.GetUser().GetPreferences()), nil
+}
+
+// versionedUpdateError converts an error from the User service like
+// userServiceError does, but for the updates aborted because of a newer
+// version, which get ErrVersionConflict as their code.
+func versionedUpdateError(err error) error {
+	s, _ := status.FromError(err)
+	if s.Code() != codes.Aborted {
+		return userServiceError(err)
+	}
+
+	e := gqlerror.Errorf("%s", s.Message())
+	errcode.Set(e, ErrVersionConflict)
+	return e
+}
+
+// profileFromMessage converts a profile from the User service to its GraphQL
+// model.
+func profileFromMessage(p *pb.Profile) *model.Profile {
+	optional := func(s string) *string {
+		if s == "" {
+			return nil
+		}
+		return &s
+	}
+
+	profile := &model.Profile{
+		DisplayName: optional(p.GetDisplayName()),
+		Bio:         optional(p.GetBio()),
+		Links:       []*model.ProfileLink{},
+		Location:    optional(p.GetLocation()),
+		Timezone:    optional(p.GetTimezone()),
+		Locale:      optional(p.GetLocale()),
+		Pronouns:    optional(p.GetPronouns()),
+		Version:     int(p.GetVersion()),
+	}
+
+	for _, l := range p.GetLinks() {
+		profile.Links = append(profile.Links, &model.ProfileLink{Label: optional(l.GetLabel()), URL: l.GetUrl()})
+	}
+
+	return profile
+}
+
+// preferencesFromMessage converts preferences from the User service to their
+// GraphQL model.
+func preferencesFromMessage(p *pb.Preferences) *model.Preferences {
+	return &model.Preferences{
+		Theme: themes[p.GetTheme()],
+		Notifications: &model.NotificationPreferences{
+			SecurityAlerts: p.GetNotifications().GetSecurityAlerts(),
+			TaskReminders:  p.GetNotifications().GetTaskReminders(),
+			ProductUpdates: p.GetNotifications().GetProductUpdates(),
+		},
+		DefaultViews: &model.DefaultViews{
+			Tasks:    taskViews[p.GetDefaultViews().GetTasks()],
+			Calendar: calendarViews[p.GetDefaultViews().GetCalendar()],
+		},
+		Privacy: &model.PrivacyPreferences{
+			ProfileVisibility: visibilities[p.GetPrivacy().GetProfileVisibility()],
+		},
+		Version: int(p.GetVersion()),
+	}
+}
@@ -0,0 +1,163 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/allen-woods/the-supertask/api/auth"
+	"github.com/allen-woods/the-supertask/api/avatar"
+	"github.com/allen-woods/the-supertask/api/export"
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	pb "github.com/allen-woods/the-supertask/services/user/proto"
+	"github.com/go-redis/redis"
+	uuid "github.com/satori/go.uuid"
+	"go.uber.org/zap"
+)
+
+// PurgeDeletedUsers has the User service purge every account whose deletion
+// grace period has passed, then revokes the sessions and deletes the data
+// exports, avatars and session history of the purged users, until it succeeds
+// for each. It runs once immediately and then every interval until ctx is
+// done, in one replica at a time.
+func PurgeDeletedUsers(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Every run gets a request ID of its own, to follow it into the User
+		// service.
+		runCtx := logging.WithRequestID(ctx, logging.NewRequestID())
+
+		err := purgeDeletedUsers(runCtx)
+		if err != nil && ctx.Err() == nil {
+			logging.FromContext(runCtx).Error("Unable to purge deleted users", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeLockKey is held in Redis by the replica purging deleted users, for as
+// long as a run may last.
+const purgeLockKey = "purgeDeletedUsersLock"
+
+// purgeTimeout is how long a run may last.
+const purgeTimeout = time.Minute
+
+// maxConfirmedPerCall is the most purged users ConfirmPurgedUsers takes at
+// once.
+const maxConfirmedPerCall = 100
+
+// unlockScript releases a lock, unless it has expired and been taken by
+// someone else since.
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+func purgeDeletedUsers(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, purgeTimeout)
+	defer cancel()
+
+	client := auth.RedisClient()
+
+	token := uuid.NewV4().String()
+
+	locked, err := client.SetNX(purgeLockKey, token, purgeTimeout).Result()
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return nil
+	}
+
+	defer func() {
+		err := unlockScript.Run(client, []string{purgeLockKey}, token).Err()
+		if err != nil {
+			logging.FromContext(ctx).Warn("Unable to release the purge lock", zap.Error(err))
+		}
+	}()
+
+	c, err := userServiceClient()
+	if err != nil {
+		return err
+	}
+
+	// The User service keeps returning purged users until their cleanup is
+	// confirmed, so those that failed are retried on the next run.
+	res, err := c.PurgeUsers(ctx, &pb.PurgeUsersReq{})
+	if err != nil {
+		return err
+	}
+
+	cleanedUp := []string{}
+
+	for _, id := range res.GetIds() {
+		if cleanUpPurgedUser(ctx, id) {
+			cleanedUp = append(cleanedUp, id)
+		}
+	}
+
+	confirmed := len(cleanedUp)
+
+	for len(cleanedUp) > 0 {
+		n := len(cleanedUp)
+		if n > maxConfirmedPerCall {
+			n = maxConfirmedPerCall
+		}
+
+		_, err = c.ConfirmPurgedUsers(ctx, &pb.ConfirmPurgedUsersReq{Ids: cleanedUp[:n]})
+		if err != nil {
+			return err
+		}
+
+		cleanedUp = cleanedUp[n:]
+	}
+
+	if len(res.GetIds()) > 0 {
+		logging.FromContext(ctx).Info("Cleaned up purged users", zap.Int("count", confirmed), zap.Int("failed", len(res.GetIds())-confirmed))
+	}
+
+	return nil
+}
+
+// cleanUpPurgedUser revokes the sessions and deletes the data exports, avatars
+// and session history of a purged user, and reports whether all of it went
+// through. A
+// purged user can no longer be read, so a session left behind meanwhile only
+// grants access to an empty account until it expires.
+func cleanUpPurgedUser(ctx context.Context, id string) bool {
+	ok := true
+
+	err := auth.RevokeUserSessions(ctx, id, auth.RevokedAccountDeleted)
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to revoke sessions of purged user", zap.String("userId", id), zap.Error(err))
+		ok = false
+	}
+
+	err = export.DeleteUserExports(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to delete data exports of purged user", zap.String("userId", id), zap.Error(err))
+		ok = false
+	}
+
+	err = avatar.DeleteAll(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to delete avatars of purged user", zap.String("userId", id), zap.Error(err))
+		ok = false
+	}
+
+	err = auth.DeleteSessionHistory(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to delete session history of purged user", zap.String("userId", id), zap.Error(err))
+		ok = false
+	}
+
+	return ok
+}
@@ -1,8 +1,262 @@
 package graph
 
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/allen-woods/the-supertask/api/audit"
+	"github.com/allen-woods/the-supertask/api/auth"
+	"github.com/allen-woods/the-supertask/api/graph/model"
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	pb "github.com/allen-woods/the-supertask/services/user/proto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
 // This file will not be regenerated automatically.
 //
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 // Resolver is the base type of our GraphQL resolvers.
 type Resolver struct{}
+
+var accountStatuses = map[pb.Status]model.AccountStatus{
+	pb.Status_STATUS_PENDING_VERIFICATION: model.AccountStatusPendingVerification,
+	pb.Status_STATUS_ACTIVE:               model.AccountStatusActive,
+	pb.Status_STATUS_SUSPENDED:            model.AccountStatusSuspended,
+	pb.Status_STATUS_PENDING_DELETION:     model.AccountStatusPendingDeletion,
+	pb.Status_STATUS_DELETED:              model.AccountStatusDeleted,
+}
+
+var roles = map[pb.Role]model.Role{
+	pb.Role_ROLE_USER:  model.RoleUser,
+	pb.Role_ROLE_ADMIN: model.RoleAdmin,
+}
+
+// tracerName names the spans the resolvers start themselves.
+const tracerName = "github.com/allen-woods/the-supertask/api/graph"
+
+// userServiceAddress is where the User service is dialed, unless
+// SetUserServiceAddress says otherwise.
+var userServiceAddress = "0.0.0.0:50051"
+
+// SetUserServiceAddress sets the host:port the User service is dialed at from
+// now on.
+func SetUserServiceAddress(addr string) {
+	userServiceAddress = addr
+}
+
+// userServiceCredentials secure the connections to the User service, unless
+// SetUserServiceCredentials says otherwise.
+var userServiceCredentials = insecure.NewCredentials()
+
+// SetUserServiceCredentials sets how connections to the User service are
+// secured from now on.
+func SetUserServiceCredentials(creds credentials.TransportCredentials) {
+	userServiceCredentials = creds
+}
+
+// userServiceToken authenticates the API to the User service, unless empty.
+var userServiceToken serviceToken
+
+// SetUserServiceToken sets the service token presented to the User service
+// from now on.
+func SetUserServiceToken(token string) {
+	userServiceToken = serviceToken(token)
+}
+
+// serviceToken presents a service token on every call.
+type serviceToken string
+
+// GetRequestMetadata passes the token as a bearer token.
+func (t serviceToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity is false, for the User service may be served
+// without TLS on a private network.
+func (t serviceToken) RequireTransportSecurity() bool {
+	return false
+}
+
+// userServiceConn is shared by every call to the User service, as it
+// multiplexes them and reconnects by itself.
+var (
+	userServiceMu   sync.Mutex
+	userServiceConn *grpc.ClientConn
+)
+
+// userServiceBackoff spaces the attempts to reconnect to the User service,
+// briefly enough that calls waiting for it are not held up for long once it
+// is back.
+var userServiceBackoff = backoff.Config{
+	BaseDelay:  100 * time.Millisecond,
+	Multiplier: 1.6,
+	Jitter:     0.2,
+	MaxDelay:   2 * time.Second,
+}
+
+// ConnectUserService opens the connection to the User service, with the
+// address, credentials and token set until then, unless it is open already.
+// It does not wait for the User service to be reachable: calls wait for it
+// instead, until their deadline. Calls pass the trace context and request ID
+// along, and their caller for auditing. They are authenticated by the client
+// certificate, and by the service token if any.
+func ConnectUserService() (*grpc.ClientConn, error) {
+	userServiceMu.Lock()
+	defer userServiceMu.Unlock()
+
+	if userServiceConn != nil {
+		return userServiceConn, nil
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(userServiceCredentials),
+		grpc.WithDefaultCallOptions(grpc.WaitForReady(true)),
+		grpc.WithConnectParams(grpc.ConnectParams{Backoff: userServiceBackoff, MinConnectTimeout: 5 * time.Second}),
+		grpc.WithChainUnaryInterceptor(otelgrpc.UnaryClientInterceptor(), logging.UnaryClientInterceptor, audit.UnaryClientInterceptor),
+		grpc.WithChainStreamInterceptor(otelgrpc.StreamClientInterceptor(), logging.StreamClientInterceptor, audit.StreamClientInterceptor),
+	}
+
+	if userServiceToken != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(userServiceToken))
+	}
+
+	conn, err := grpc.Dial(userServiceAddress, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	userServiceConn = conn
+
+	return conn, nil
+}
+
+// CloseUserService closes the connection to the User service, once nothing
+// uses it anymore.
+func CloseUserService() error {
+	userServiceMu.Lock()
+	defer userServiceMu.Unlock()
+
+	if userServiceConn == nil {
+		return nil
+	}
+
+	err := userServiceConn.Close()
+	userServiceConn = nil
+
+	return err
+}
+
+// userServiceClient returns a client of the User service over the shared
+// connection, which must not be closed but by CloseUserService.
+func userServiceClient() (pb.UserCRUDClient, error) {
+	conn, err := ConnectUserService()
+	if err != nil {
+		return nil, err
+	}
+
+	return pb.NewUserCRUDClient(conn), nil
+}
+
+// readUser reads the user with the given ID from the User service, through
+// the user loader of the operation if there is one.
+func readUser(ctx context.Context, id string) (*model.User, error) {
+	if l := userLoaderFor(ctx); l != nil {
+		u, err := l.load(ctx, id)
+		if err != nil {
+			return nil, userServiceError(err)
+		}
+
+		return userFromMessage(u)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	c, err := userServiceClient()
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+
+	res, err := c.ReadUser(ctx, &pb.ReadUserReq{Id: id})
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+
+	return userFromMessage(res.GetUser())
+}
+
+// userServiceError converts an error from the User service into one that is
+// safe to return to GraphQL clients.
+func userServiceError(err error) error {
+	s, _ := status.FromError(err)
+
+	switch s.Code() {
+	case codes.NotFound:
+		return errors.New("user not found")
+	case codes.Unauthenticated:
+		return errors.New("invalid credentials")
+	case codes.InvalidArgument, codes.AlreadyExists, codes.FailedPrecondition, codes.PermissionDenied:
+		return errors.New(s.Message())
+	}
+
+	return errors.New("the user service is unavailable")
+}
+
+// userFromMessage converts a User from the User service to its GraphQL model.
+func userFromMessage(u *pb.User) (*model.User, error) {
+	id, err := primitive.ObjectIDFromHex(u.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	user := &model.User{
+		ID:          id,
+		Email:       u.GetEmail(),
+		Name:        u.GetName(),
+		UserName:    u.GetUserName(),
+		Status:      accountStatuses[u.GetStatus()],
+		Role:        roles[u.GetRole()],
+		Avatar:      u.GetAvatar(),
+		Profile:     profileFromMessage(u.GetProfile()),
+		Preferences: preferencesFromMessage(u.GetPreferences()),
+	}
+
+	if u.GetDeleteAfter() != nil {
+		deleteAfter := u.GetDeleteAfter().AsTime()
+		user.DeleteAfter = &deleteAfter
+	}
+
+	if u.GetStatus() == pb.Status_STATUS_SUSPENDED {
+		user.Suspension = &model.Suspension{Reason: u.GetSuspensionReason()}
+
+		if u.GetSuspendedUntil() != nil {
+			until := u.GetSuspendedUntil().AsTime()
+			user.Suspension.Until = &until
+		}
+	}
+
+	return user, nil
+}
+
+// revokeSessionsUnlessActive logs a user out everywhere once their account has
+// left the active state, so that the change takes effect immediately.
+func revokeSessionsUnlessActive(ctx context.Context, u *pb.User) error {
+	switch u.GetStatus() {
+	case pb.Status_STATUS_SUSPENDED:
+		return auth.RevokeUserSessions(ctx, u.GetId(), auth.RevokedAccountSuspended)
+	case pb.Status_STATUS_PENDING_DELETION, pb.Status_STATUS_DELETED:
+		return auth.RevokeUserSessions(ctx, u.GetId(), auth.RevokedAccountDeleted)
+	}
+
+	return nil
+}
@@ -5,46 +5,49 @@ package graph
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/allen-woods/the-supertask/api/audit"
 	"github.com/allen-woods/the-supertask/api/auth"
+	"github.com/allen-woods/the-supertask/api/avatar"
+	"github.com/allen-woods/the-supertask/api/events"
+	"github.com/allen-woods/the-supertask/api/export"
 	"github.com/allen-woods/the-supertask/api/graph/generated"
 	"github.com/allen-woods/the-supertask/api/graph/model"
+	"github.com/allen-woods/the-supertask/pkg/logging"
 	pb "github.com/allen-woods/the-supertask/services/user/proto"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"google.golang.org/grpc"
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-const (
-	userServiceIP   = "0.0.0.0"
-	userServicePort = 50051
-)
-
-var userServiceAddress string = fmt.Sprintf("%s:%d", userServiceIP, userServicePort)
-
 func (r *mutationResolver) SignUpUser(ctx context.Context, input *model.NewUser) (*model.User, error) {
 	// Resolver not authenticated to allow sign up.
 
-	// Hash and salt the password first using authentication middleware.
+	// Hash and salt the password first using authentication middleware. It
+	// is deliberately slow, so it gets a span of its own.
+	_, span := otel.Tracer(tracerName).Start(ctx, "bcrypt")
 	securePassword, err := auth.HashAndSalt(input.Password)
+	span.End()
 	if err != nil {
-		log.Fatal("Failed to hash and salt password.")
+		logging.FromContext(ctx).Error("Failed to hash and salt password", zap.Error(err))
+		return nil, userServiceError(err)
 	}
 
-	// Dial the gRPC server dedicated to the User model.
-	conn, err := grpc.Dial(userServiceAddress, grpc.WithInsecure(), grpc.WithBlock())
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	// Call the gRPC server dedicated to the User model.
+	c, err := userServiceClient()
 	if err != nil {
-		log.Fatal("did not connect: %v", err)
+		return nil, userServiceError(err)
 	}
-	defer conn.Close()
-
-	// Create a client for talking to the server we just dialed.
-	c := pb.NewUserServiceClient(conn)
-
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
 
 	// Request to create the User, given the fields of "input" in our ctx.
 	res, err := c.CreateUser(
@@ -59,67 +62,712 @@ func (r *mutationResolver) SignUpUser(ctx context.Context, input *model.NewUser)
 		},
 	)
 	if err != nil {
-		log.Fatal("Failed to create User over gRPC.")
+		return nil, userServiceError(err)
 	}
 
-	// Parse the User from our response "res".
-	createdUser := res.GetUser()
-
-	// Sanitize our ObjectID value to make sure it's legitimate.
-	idToInsert, err := primitive.ObjectIDFromHex(createdUser.Id)
+	// Build a valid User return value with an ommitted "password" field,
+	// sanitizing our ObjectID value to make sure it's legitimate.
+	u, err := userFromMessage(res.GetUser())
 	if err != nil {
-		log.Fatalf("Corrupted ObjectID: %v", err)
+		logging.FromContext(ctx).Error("Corrupted ObjectID", zap.Error(err))
+		return nil, userServiceError(err)
 	}
 
-	// Build a valid User return value with an ommitted "password" field.
-	u := &model.User{
-		ID:       idToInsert,
-		Email:    createdUser.Email,
-		Name:     createdUser.Name,
-		UserName: createdUser.UserName,
+	// The account exists whether or not the session can be bound to it.
+	err = auth.LogIn(ctx, u.ID.Hex())
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to log in a new user", zap.Error(err))
+		return nil, errors.New("signed up, but unable to log in")
 	}
 
-	// Pass the verified ObjectID as hex into authentication middleware.
-	auth.InsertUserID(u.ID.Hex())
+	publishUserSignedUp(ctx, u.ID.Hex())
 
 	return u, nil
 }
 
 func (r *mutationResolver) LogInUser(ctx context.Context, email string, password string) (*model.User, error) {
 	// Not authenticated to allow for login.
-	// gRPC takes id as input and returns User.
-	panic(fmt.Errorf("not implemented"))
+	// gRPC takes email and password as input and returns User.
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	c, err := userServiceClient()
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+
+	// Suspended and deleted accounts are refused by the User service.
+	res, err := c.AuthenticateUser(ctx, &pb.AuthenticateUserReq{
+		Email:    email,
+		Password: password,
+	})
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+
+	u, err := userFromMessage(res.GetUser())
+	if err != nil {
+		return nil, err
+	}
+
+	err = auth.LogIn(ctx, u.ID.Hex())
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to log in", zap.Error(err))
+		return nil, errors.New("unable to log in")
+	}
+
+	return u, nil
 }
 
 func (r *mutationResolver) LogOutUser(ctx context.Context) (bool, error) {
 	// Must be authenticated.
-	// gRPC takes nothing as input and returns bool.
-	panic(fmt.Errorf("not implemented"))
+	if auth.ForContext(ctx) == "" {
+		return false, errors.New("not authenticated")
+	}
+
+	err := auth.LogOut(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to log out", zap.Error(err))
+		return false, errors.New("unable to log out")
+	}
+
+	return true, nil
 }
 
-func (r *mutationResolver) DeleteUser(ctx context.Context, id primitive.ObjectID, confirmDelete bool) (bool, error) {
-	// Must be authenticated.
+func (r *mutationResolver) DeleteUser(ctx context.Context, id primitive.ObjectID, password string, confirmDelete bool) (bool, error) {
+	// Must be authenticated, and users may only delete their own account.
 	// gRPC takes id as input and returns bool.
-	panic(fmt.Errorf("not implemented"))
+	if !confirmDelete {
+		return false, errors.New("account deletion must be confirmed")
+	}
+
+	userID := auth.ForContext(ctx)
+	if userID == "" {
+		return false, errors.New("not authenticated")
+	}
+	if userID != id.Hex() {
+		return false, errors.New("not authorized to delete this account")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	c, err := userServiceClient()
+	if err != nil {
+		return false, userServiceError(err)
+	}
+
+	// Re-authenticate, so that an unattended session cannot delete the account.
+	_, err = c.AuthenticateUser(ctx, &pb.AuthenticateUserReq{
+		Id:       id.Hex(),
+		Password: password,
+	})
+	if err != nil {
+		return false, userServiceError(err)
+	}
+
+	// The account is only marked for deletion here. It is purged by
+	// PurgeDeletedUsers once the grace period has passed; until then the user
+	// may log back in to cancel.
+	res, err := c.DeleteUser(ctx, &pb.DeleteUserReq{Id: id.Hex()})
+	if err != nil {
+		return false, userServiceError(err)
+	}
+
+	// The account is no longer active, so every session of it ends now.
+	err = auth.RevokeUserSessions(ctx, id.Hex(), auth.RevokedAccountDeleted)
+	if err != nil {
+		return false, err
+	}
+
+	return res.GetSuccess(), nil
+}
+
+func (r *mutationResolver) CancelAccountDeletion(ctx context.Context) (bool, error) {
+	// Must be authenticated.
+	// gRPC takes id (via cookie) as input and returns User.
+	userID := auth.ForContext(ctx)
+	if userID == "" {
+		return false, errors.New("not authenticated")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	c, err := userServiceClient()
+	if err != nil {
+		return false, userServiceError(err)
+	}
+
+	_, err = c.CancelUserDeletion(ctx, &pb.CancelUserDeletionReq{Id: userID})
+	if err != nil {
+		return false, userServiceError(err)
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) RequestMyDataExport(ctx context.Context) (*model.DataExport, error) {
+	// Must be authenticated.
+	// gRPC takes id (via cookie) as input and streams the stored documents,
+	// once the export is built in the background.
+	userID := auth.ForContext(ctx)
+	if userID == "" {
+		return nil, errors.New("not authenticated")
+	}
+
+	e, err := export.Create(userID)
+	if err == export.ErrPending {
+		return nil, err
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to create data export", zap.Error(err))
+		return nil, errors.New("unable to request a data export")
+	}
+
+	go buildDataExport(ctx, e)
+
+	return dataExportFromExport(e)
+}
+
+func (r *mutationResolver) SuspendUser(ctx context.Context, id primitive.ObjectID, reason string, until *time.Time) (*model.User, error) {
+	// Must be authenticated as an admin, see @hasRole.
+	// gRPC takes id, reason and until as input and returns User.
+	req := &pb.SuspendUserReq{
+		Id:     id.Hex(),
+		Reason: reason,
+	}
+
+	if until != nil {
+		req.Until = timestamppb.New(*until)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	c, err := userServiceClient()
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+
+	res, err := c.SuspendUser(ctx, req)
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+
+	err = revokeSessionsUnlessActive(ctx, res.GetUser())
+	if err != nil {
+		return nil, err
+	}
+
+	return userFromMessage(res.GetUser())
+}
+
+func (r *mutationResolver) ReinstateUser(ctx context.Context, id primitive.ObjectID) (*model.User, error) {
+	// Must be authenticated as an admin, see @hasRole.
+	// gRPC takes id as input and returns User.
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	c, err := userServiceClient()
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+
+	res, err := c.ReinstateUser(ctx, &pb.ReinstateUserReq{Id: id.Hex()})
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+
+	return userFromMessage(res.GetUser())
+}
+
+func (r *mutationResolver) StartImpersonation(ctx context.Context, userID primitive.ObjectID, reason string) (*model.Impersonation, error) {
+	// Must be authenticated as an admin, see @hasRole.
+	// gRPC takes userId as input and returns User, to check that it exists.
+	if reason == "" {
+		return nil, errors.New("a reason for the impersonation is required")
+	}
+
+	// Admins cannot be impersonated, so that their role is never lent out.
+	u, err := readUser(ctx, userID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if u.Role == model.RoleAdmin {
+		audit.Record(ctx, audit.ActionImpersonationDenied, userID.Hex(), pb.AuditOutcome_AUDIT_OUTCOME_FAILURE, "Cannot impersonate an admin")
+		return nil, errors.New("cannot impersonate an admin")
+	}
+
+	i, err := auth.StartImpersonation(ctx, userID.Hex(), reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Impersonation{Reason: i.Reason, EndsAt: i.EndsAt}, nil
+}
+
+func (r *mutationResolver) StopImpersonation(ctx context.Context) (bool, error) {
+	// Must be impersonating a user.
+	if auth.ImpersonationForContext(ctx) == nil {
+		return false, errors.New("not impersonating a user")
+	}
+
+	err := auth.StopImpersonation(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to stop impersonation", zap.Error(err))
+		return false, errors.New("unable to stop the impersonation")
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) UploadAvatar(ctx context.Context, file graphql.Upload) (*model.User, error) {
+	// Must be authenticated.
+	// The images are stored before the user is pointed at them, and those of
+	// the previous avatar removed after, so that the avatar never breaks.
+	userID := auth.ForContext(ctx)
+	if userID == "" {
+		return nil, errors.New("not authenticated")
+	}
+
+	current, err := readUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := avatar.Save(ctx, userID, file.File)
+	switch err {
+	case nil:
+	case avatar.ErrTooLarge:
+		return nil, fmt.Errorf("the avatar file must be at most %d KiB", avatar.MaxSize()/1024)
+	case avatar.ErrUnsupportedType, avatar.ErrTooManyPixels, avatar.ErrInvalidImage:
+		return nil, err
+	default:
+		logging.FromContext(ctx).Error("Unable to save an avatar", zap.Error(err))
+		return nil, errors.New("unable to save the avatar")
+	}
+
+	user, err := setAvatar(ctx, userID, id)
+	if err != nil {
+		deleteAvatar(ctx, userID, id)
+		return nil, err
+	}
+
+	deleteAvatar(ctx, userID, current.Avatar)
+
+	return user, nil
+}
+
+func (r *mutationResolver) RemoveAvatar(ctx context.Context) (*model.User, error) {
+	// Must be authenticated.
+	userID := auth.ForContext(ctx)
+	if userID == "" {
+		return nil, errors.New("not authenticated")
+	}
+
+	current, err := readUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := setAvatar(ctx, userID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	deleteAvatar(ctx, userID, current.Avatar)
+
+	return user, nil
+}
+
+func (r *mutationResolver) UpdateProfile(ctx context.Context, input model.ProfileInput, version int) (*model.User, error) {
+	// Must be authenticated.
+	userID := auth.ForContext(ctx)
+	if userID == "" {
+		return nil, errors.New("not authenticated")
+	}
+
+	return updateProfile(ctx, userID, input, version)
+}
+
+func (r *mutationResolver) UpdatePreferences(ctx context.Context, input model.PreferencesInput, version int) (*model.Preferences, error) {
+	// Must be authenticated.
+	userID := auth.ForContext(ctx)
+	if userID == "" {
+		return nil, errors.New("not authenticated")
+	}
+
+	return updatePreferences(ctx, userID, input, version)
+}
+
+func (r *publicUserResolver) AvatarURL(ctx context.Context, obj *model.PublicUser, size *model.AvatarSize) (*string, error) {
+	if obj.Avatar == "" {
+		return nil, nil
+	}
+
+	px := avatar.SizeMedium
+	if size != nil {
+		px = avatarSizes[*size]
+	}
+
+	url := avatar.URL(obj.ID.Hex(), obj.Avatar, px)
+	return &url, nil
+}
+
+func (r *queryResolver) Viewer(ctx context.Context) (*model.Viewer, error) {
+	// Not authenticated to allow for anonymous viewers.
+	// gRPC takes id (via cookie) as input and returns User.
+	viewer := &model.Viewer{}
+
+	userID := auth.ForContext(ctx)
+	if userID == "" {
+		return viewer, nil
+	}
+
+	user, err := readUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	viewer.User = user
+	viewer.Preferences = user.Preferences
+
+	if i := auth.ImpersonationForContext(ctx); i != nil {
+		impersonator, err := readUser(ctx, i.ImpersonatorID)
+		if err != nil {
+			return nil, err
+		}
+
+		viewer.ImpersonatedBy = impersonator
+		viewer.Impersonation = &model.Impersonation{Reason: i.Reason, EndsAt: i.EndsAt}
+	}
+
+	return viewer, nil
 }
 
 func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
 	// Must be authenticated.
 	// gRPC takes id (via cookie) as input and returns User.
-	panic(fmt.Errorf("not implemented"))
+	userID := auth.ForContext(ctx)
+	if userID == "" {
+		return nil, errors.New("not authenticated")
+	}
+
+	return readUser(ctx, userID)
 }
 
-func (r *queryResolver) Users(ctx context.Context) ([]*model.User, error) {
+func (r *queryResolver) Users(ctx context.Context, first *int) ([]*model.User, error) {
+	// Must be authenticated as an admin, see @hasRole.
+	// gRPC takes nothing as input and streams every User, read up to first.
+	limit := 20
+	if first != nil {
+		limit = *first
+	}
+	if limit < 0 {
+		return nil, errors.New("first must not be negative")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	c, err := userServiceClient()
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+
+	// The stream is cancelled along with ctx when it is left before its end.
+	stream, err := c.ListUsers(ctx, &pb.ListUsersReq{})
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+
+	users := []*model.User{}
+
+	for len(users) < limit {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, userServiceError(err)
+		}
+
+		u, err := userFromMessage(res.GetUser())
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, u)
+	}
+
+	return users, nil
+}
+
+func (r *queryResolver) MyDataExports(ctx context.Context) ([]*model.DataExport, error) {
 	// Must be authenticated.
-	// gRPC takes nothing as input and returns []*User.
-	panic(fmt.Errorf("not implemented"))
+	userID := auth.ForContext(ctx)
+	if userID == "" {
+		return nil, errors.New("not authenticated")
+	}
+
+	exports, err := export.List(userID)
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to list data exports", zap.Error(err))
+		return nil, errors.New("unable to list data exports")
+	}
+
+	dataExports := []*model.DataExport{}
+
+	for _, e := range exports {
+		dataExport, err := dataExportFromExport(e)
+		if err != nil {
+			return nil, err
+		}
+
+		dataExports = append(dataExports, dataExport)
+	}
+
+	return dataExports, nil
+}
+
+func (r *queryResolver) AuditEvents(ctx context.Context, filter *model.AuditEventFilter, after *string, first *int) (*model.AuditEventConnection, error) {
+	// Must be authenticated as an admin, see @hasRole.
+	// gRPC takes filter, after and first as input and returns []*AuditEvent.
+	req := &pb.ListAuditEventsReq{}
+
+	if filter != nil {
+		if filter.ActorID != nil {
+			req.ActorId = filter.ActorID.Hex()
+		}
+		if filter.TargetID != nil {
+			req.TargetId = filter.TargetID.Hex()
+		}
+		if filter.UserID != nil {
+			req.UserId = filter.UserID.Hex()
+		}
+		if filter.Action != nil {
+			req.Action = *filter.Action
+		}
+		if filter.Since != nil {
+			req.Since = timestamppb.New(*filter.Since)
+		}
+		if filter.Until != nil {
+			req.Until = timestamppb.New(*filter.Until)
+		}
+	}
+
+	if after != nil {
+		req.After = *after
+	}
+	if first != nil {
+		req.First = int32(*first)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	c, err := userServiceClient()
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+
+	res, err := c.ListAuditEvents(ctx, req)
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+
+	connection := &model.AuditEventConnection{
+		Edges:    []*model.AuditEventEdge{},
+		PageInfo: &model.PageInfo{HasNextPage: res.GetHasNextPage()},
+	}
+
+	for _, e := range res.GetEvents() {
+		event, err := auditEventFromMessage(e)
+		if err != nil {
+			return nil, err
+		}
+
+		connection.Edges = append(connection.Edges, &model.AuditEventEdge{Cursor: e.GetId(), Node: event})
+	}
+
+	if n := len(res.GetEvents()); n > 0 {
+		endCursor := res.GetEvents()[n-1].GetId()
+		connection.PageInfo.EndCursor = &endCursor
+	}
+
+	return connection, nil
+}
+
+func (r *queryResolver) MySecurityActivity(ctx context.Context, first *int) ([]*model.AuditEvent, error) {
+	// Must be authenticated.
+	// gRPC takes id (via cookie) and first as input and returns []*AuditEvent.
+	userID := auth.ForContext(ctx)
+	if userID == "" {
+		return nil, errors.New("not authenticated")
+	}
+
+	req := &pb.ListAuditEventsReq{UserId: userID}
+	if first != nil {
+		req.First = int32(*first)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	c, err := userServiceClient()
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+
+	res, err := c.ListAuditEvents(ctx, req)
+	if err != nil {
+		return nil, userServiceError(err)
+	}
+
+	events := []*model.AuditEvent{}
+
+	for _, e := range res.GetEvents() {
+		event, err := auditEventFromMessage(e)
+		if err != nil {
+			return nil, err
+		}
+
+		// Staff acting on the account are not identified to its owner.
+		if e.GetActorId() != "" && e.GetActorId() != userID {
+			event.ActorID = nil
+			event.IP = nil
+			event.UserAgent = nil
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (r *queryResolver) UserByUserName(ctx context.Context, userName string) (*model.PublicUser, error) {
+	// Not authenticated, as profiles may be public.
+	// gRPC takes userName as input and returns User, whose privacy is applied here.
+	return readPublicUser(ctx, userName)
+}
+
+func (r *subscriptionResolver) MySessionRevoked(ctx context.Context) (<-chan *model.SessionRevocation, error) {
+	// Must be authenticated.
+	// Fed by Redis pub/sub, whichever replica revoked the sessions.
+	userID := auth.ForContext(ctx)
+	if userID == "" {
+		return nil, errors.New("not authenticated")
+	}
+
+	messages, err := events.Subscribe(ctx, events.SessionsRevokedTopic(userID))
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to subscribe to revocations", zap.Error(err))
+		return nil, errors.New("unable to subscribe")
+	}
+
+	revocations := make(chan *model.SessionRevocation)
+
+	go func() {
+		defer close(revocations)
+
+		for m := range messages {
+			var e events.SessionsRevoked
+			err := json.Unmarshal(m, &e)
+			if err != nil {
+				logging.FromContext(ctx).Error("Unable to decode a revocation", zap.Error(err))
+				continue
+			}
+
+			revocation := &model.SessionRevocation{
+				Reason:    model.SessionRevocationReason(e.Reason),
+				RevokedAt: e.RevokedAt,
+			}
+
+			select {
+			case revocations <- revocation:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return revocations, nil
+}
+
+func (r *subscriptionResolver) UserSignedUp(ctx context.Context) (<-chan *model.User, error) {
+	// Must be an admin, as checked by @hasRole when subscribing and again for
+	// every sign up, so that a demotion ends the subscription.
+	messages, err := events.Subscribe(ctx, events.TopicUserSignedUp)
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to subscribe to sign ups", zap.Error(err))
+		return nil, errors.New("unable to subscribe")
+	}
+
+	users := make(chan *model.User)
+
+	go func() {
+		defer close(users)
+
+		for m := range messages {
+			var e events.UserSignedUp
+			err := json.Unmarshal(m, &e)
+			if err != nil {
+				logging.FromContext(ctx).Error("Unable to decode a sign up", zap.Error(err))
+				continue
+			}
+
+			if requireRole(ctx, model.RoleAdmin) != nil {
+				return
+			}
+
+			u, err := readUser(ctx, e.UserID)
+			if err != nil {
+				logging.FromContext(ctx).Warn("Unable to read a user who signed up", zap.String("userId", e.UserID), zap.Error(err))
+				continue
+			}
+
+			select {
+			case users <- u:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return users, nil
+}
+
+func (r *userResolver) AvatarURL(ctx context.Context, obj *model.User, size *model.AvatarSize) (*string, error) {
+	if obj.Avatar == "" {
+		return nil, nil
+	}
+
+	px := avatar.SizeMedium
+	if size != nil {
+		px = avatarSizes[*size]
+	}
+
+	url := avatar.URL(obj.ID.Hex(), obj.Avatar, px)
+	return &url, nil
 }
 
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
+// PublicUser returns generated.PublicUserResolver implementation.
+func (r *Resolver) PublicUser() generated.PublicUserResolver { return &publicUserResolver{r} }
+
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
+// Subscription returns generated.SubscriptionResolver implementation.
+func (r *Resolver) Subscription() generated.SubscriptionResolver { return &subscriptionResolver{r} }
+
+// User returns generated.UserResolver implementation.
+func (r *Resolver) User() generated.UserResolver { return &userResolver{r} }
+
 type mutationResolver struct{ *Resolver }
+type publicUserResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
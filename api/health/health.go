@@ -0,0 +1,139 @@
+// Package health serves the liveness and readiness of the API. Each endpoint
+// runs a set of checks, concurrently and within a timeout, and reports the
+// outcome of every one of them as JSON:
+//
+//	{
+//	  "status": "fail",
+//	  "checks": {
+//	    "redis": {"status": "ok", "duration": "1.2ms"},
+//	    "userService": {"status": "fail", "error": "...", "duration": "2s"}
+//	  }
+//	}
+//
+// The status code is 200 when every check passes, and 503 otherwise.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// DefaultTimeout bounds the checks of an endpoint, unless told otherwise.
+const DefaultTimeout = 2 * time.Second
+
+// Statuses of a check, and of an endpoint as a whole.
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Check is a dependency, or a part of the API itself, that must work for the
+// API to be healthy.
+type Check struct {
+	Name string
+	// Run returns why the check fails, or nil. It should give up once ctx is
+	// done.
+	Run func(ctx context.Context) error
+}
+
+// Result is the outcome of a check.
+type Result struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report is the outcome of every check of an endpoint.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// errTimeout is the error of a check that did not complete in time.
+var errTimeout = errors.New("timed out")
+
+// Run runs checks concurrently, giving up on those that do not complete
+// within timeout.
+func Run(ctx context.Context, timeout time.Duration, checks ...Check) Report {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type outcome struct {
+		name   string
+		result Result
+	}
+
+	// The channel is buffered so that checks that do not stop in time can
+	// still complete once nothing waits for them.
+	outcomes := make(chan outcome, len(checks))
+
+	for _, c := range checks {
+		go func(c Check) {
+			start := time.Now()
+			err := c.Run(ctx)
+			outcomes <- outcome{name: c.Name, result: result(err, time.Since(start))}
+		}(c)
+	}
+
+	report := Report{Status: StatusOK, Checks: map[string]Result{}}
+
+	for _, c := range checks {
+		report.Checks[c.Name] = result(errTimeout, timeout)
+	}
+
+	for range checks {
+		select {
+		case o := <-outcomes:
+			report.Checks[o.name] = o.result
+		case <-ctx.Done():
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	for _, r := range report.Checks {
+		if r.Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+
+	return report
+}
+
+func result(err error, d time.Duration) Result {
+	r := Result{Status: StatusOK, Duration: d.String()}
+
+	if err != nil {
+		r.Status = StatusFail
+		r.Error = err.Error()
+	}
+
+	return r
+}
+
+// Handler serves the report of checks, run on every request.
+func Handler(timeout time.Duration, checks ...Check) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		report := Run(r.Context(), timeout, checks...)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+
+		if report.Status != StatusOK {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		json.NewEncoder(w).Encode(report)
+	})
+}
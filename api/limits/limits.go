@@ -0,0 +1,194 @@
+// Package limits rejects GraphQL operations that select fields too deeply, or
+// that would cost too much to resolve, before any resolver runs. The cost of
+// a field is read from its @cost directive in the schema.
+package limits
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Error codes of the rejected operations, in the extensions of their errors.
+const (
+	ErrDepthLimit      = "DEPTH_LIMIT_EXCEEDED"
+	ErrComplexityLimit = "COMPLEXITY_LIMIT_EXCEEDED"
+)
+
+// costDirective names the directive setting the cost of a field.
+const costDirective = "cost"
+
+// Limits caps how deeply an operation selects fields, and how complex it is.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// GraphQL is the gqlgen extension enforcing the limits of the user making the
+// request. Admins get limits of their own.
+type GraphQL struct {
+	User  Limits
+	Admin Limits
+	// IsAdmin reports whether the request is made by an admin. It is only
+	// called for operations over the limits of users.
+	IsAdmin func(ctx context.Context) bool
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = GraphQL{}
+
+// Stats are the depth and complexity of an operation, as computed by the
+// extension.
+type Stats struct {
+	Depth      int
+	Complexity int
+}
+
+// ExtensionName names the extension in stats and logs.
+func (GraphQL) ExtensionName() string {
+	return "Limits"
+}
+
+// Validate accepts any schema.
+func (GraphQL) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// MutateOperationContext rejects the operation when it is over the limits,
+// telling the client its depth or complexity and the limit.
+func (g GraphQL) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	if rc.Operation == nil {
+		return nil
+	}
+
+	complexity, depth := measure(rc.Operation.SelectionSet, rc.Variables)
+	rc.Stats.SetExtension(g.ExtensionName(), &Stats{Depth: depth, Complexity: complexity})
+
+	limits := g.User
+	if !within(limits, depth, complexity) && g.IsAdmin != nil && g.IsAdmin(ctx) {
+		limits = g.Admin
+	}
+
+	if depth > limits.MaxDepth {
+		err := gqlerror.Errorf("operation has a depth of %d, over the limit of %d", depth, limits.MaxDepth)
+		errcode.Set(err, ErrDepthLimit)
+		err.Extensions["depth"] = depth
+		err.Extensions["limit"] = limits.MaxDepth
+		return err
+	}
+
+	if complexity > limits.MaxComplexity {
+		err := gqlerror.Errorf("operation has a complexity of %d, over the limit of %d", complexity, limits.MaxComplexity)
+		errcode.Set(err, ErrComplexityLimit)
+		err.Extensions["complexity"] = complexity
+		err.Extensions["limit"] = limits.MaxComplexity
+		return err
+	}
+
+	return nil
+}
+
+// within reports whether an operation of the given depth and complexity is
+// within the limits.
+func within(limits Limits, depth int, complexity int) bool {
+	return depth <= limits.MaxDepth && complexity <= limits.MaxComplexity
+}
+
+// measure returns the complexity and depth of the selections. Introspection
+// is left out, as it only reads the schema.
+func measure(set ast.SelectionSet, vars map[string]interface{}) (complexity int, depth int) {
+	for _, sel := range set {
+		var c, d int
+
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(sel.Name, "__") {
+				continue
+			}
+
+			c, d = measure(sel.SelectionSet, vars)
+			c, d = fieldCost(sel, vars, c), d+1
+		case *ast.InlineFragment:
+			c, d = measure(sel.SelectionSet, vars)
+		case *ast.FragmentSpread:
+			if sel.Definition != nil {
+				c, d = measure(sel.Definition.SelectionSet, vars)
+			}
+		}
+
+		complexity += c
+		if d > depth {
+			depth = d
+		}
+	}
+
+	return complexity, depth
+}
+
+// fieldCost is the complexity of the field, given that of its selections.
+func fieldCost(f *ast.Field, vars map[string]interface{}, children int) int {
+	if f.Definition == nil {
+		return 1 + children
+	}
+
+	cost := f.Definition.Directives.ForName(costDirective)
+	if cost == nil {
+		return 1 + children
+	}
+
+	own := 1
+	if n, ok := toInt(directiveArgument(cost, "complexity")); ok {
+		own = n
+	}
+
+	total := own + children
+
+	if name, ok := directiveArgument(cost, "multiplier").(string); ok {
+		if n, ok := toInt(f.ArgumentMap(vars)[name]); ok && n > 1 {
+			total *= n
+		}
+	}
+
+	return total
+}
+
+// directiveArgument returns the value of the named argument of a directive in
+// the schema, or nil.
+func directiveArgument(d *ast.Directive, name string) interface{} {
+	arg := d.Arguments.ForName(name)
+	if arg == nil || arg.Value == nil {
+		return nil
+	}
+
+	v, err := arg.Value.Value(nil)
+	if err != nil {
+		return nil
+	}
+
+	return v
+}
+
+// toInt converts an Int argument, as parsed from the query or decoded from
+// the variables.
+func toInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	case json.Number:
+		i, err := n.Int64()
+		return int(i), err == nil
+	}
+
+	return 0, false
+}
@@ -0,0 +1,115 @@
+// Package metrics exports the metrics of the API to Prometheus: the duration
+// of every GraphQL operation and resolver, through the GraphQL extension, and
+// those the other packages register, such as the session lookups of auth.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Outcomes of an operation or resolver.
+const (
+	outcomeOK    = "ok"
+	outcomeError = "error"
+)
+
+var (
+	operationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "api",
+		Subsystem: "graphql",
+		Name:      "operation_duration_seconds",
+		Help:      "Duration of GraphQL operations, from parsing to the response.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "type", "outcome"})
+
+	resolverDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "api",
+		Subsystem: "graphql",
+		Name:      "resolver_duration_seconds",
+		Help:      "Duration of GraphQL resolvers, by object and field.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"object", "field", "outcome"})
+)
+
+// Handler serves the metrics for Prometheus to scrape.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// GraphQL is the gqlgen extension timing operations and resolvers. Operations
+// are labelled with their name, "anonymous" when they have none.
+type GraphQL struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = GraphQL{}
+
+// ExtensionName names the extension in stats and logs.
+func (GraphQL) ExtensionName() string {
+	return "Metrics"
+}
+
+// Validate accepts any schema.
+func (GraphQL) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptResponse times the operation of each response.
+func (GraphQL) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	res := next(ctx)
+
+	if !graphql.HasOperationContext(ctx) {
+		return res
+	}
+
+	oc := graphql.GetOperationContext(ctx)
+
+	name := oc.OperationName
+	if name == "" {
+		name = "anonymous"
+	}
+
+	opType := "unknown"
+	if oc.Operation != nil {
+		opType = string(oc.Operation.Operation)
+	}
+
+	outcome := outcomeOK
+	if res == nil || len(res.Errors) > 0 {
+		outcome = outcomeError
+	}
+
+	operationDuration.WithLabelValues(name, opType, outcome).Observe(time.Since(oc.Stats.OperationStart).Seconds())
+
+	return res
+}
+
+// InterceptField times the fields backed by a method, which are those doing
+// actual work, as opposed to reading a struct field.
+func (GraphQL) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsMethod {
+		return next(ctx)
+	}
+
+	start := time.Now()
+	res, err := next(ctx)
+
+	outcome := outcomeOK
+	if err != nil {
+		outcome = outcomeError
+	}
+
+	resolverDuration.WithLabelValues(fc.Object, fc.Field.Name, outcome).Observe(time.Since(start).Seconds())
+
+	return res, err
+}
@@ -0,0 +1,82 @@
+package persisted
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	"github.com/mitchellh/mapstructure"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.uber.org/zap"
+)
+
+// ErrNotAllowed is the code of the errors of the operations that are not
+// registered, in their extensions.
+const ErrNotAllowed = "PERSISTED_QUERY_NOT_ALLOWED"
+
+// Allowlist is the gqlgen extension that only runs registered operations. An
+// operation is given by the hash of its query, as an automatic persisted
+// query, or in full, in which case it is hashed. Either way, the body that
+// runs is the registered one.
+type Allowlist struct {
+	Store Store
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationParameterMutator
+} = Allowlist{}
+
+// ExtensionName names the extension in stats and logs.
+func (Allowlist) ExtensionName() string {
+	return "Allowlist"
+}
+
+// Validate accepts any schema.
+func (Allowlist) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// MutateOperationParameters replaces the query with the registered body of
+// the operation, or rejects the operation if it is not registered.
+func (a Allowlist) MutateOperationParameters(ctx context.Context, rawParams *graphql.RawParams) *gqlerror.Error {
+	var extension struct {
+		Sha256 string `mapstructure:"sha256Hash"`
+	}
+
+	if rawParams.Extensions["persistedQuery"] != nil {
+		err := mapstructure.Decode(rawParams.Extensions["persistedQuery"], &extension)
+		if err != nil {
+			return gqlerror.Errorf("invalid APQ extension data")
+		}
+	}
+
+	id := extension.Sha256
+	if rawParams.Query != "" {
+		if id != "" && Hash(rawParams.Query) != id {
+			return gqlerror.Errorf("provided APQ hash does not match query")
+		}
+		id = Hash(rawParams.Query)
+	}
+
+	if id == "" {
+		return gqlerror.Errorf("no operation provided")
+	}
+
+	body, ok, err := a.Store.Get(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to look up an allowed operation", zap.Error(err))
+		return gqlerror.Errorf("unable to look up the operation")
+	}
+	if !ok {
+		logging.FromContext(ctx).Warn("Refused an operation that is not allowed", zap.String("hash", id))
+		err := gqlerror.Errorf("operation is not allowed")
+		errcode.Set(err, ErrNotAllowed)
+		return err
+	}
+
+	rawParams.Query = body
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package persisted
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/go-redis/redis"
+)
+
+// ManifestFormat is the format of the manifests generated by the front end
+// build, that of @apollo/generate-persisted-query-manifest.
+const ManifestFormat = "apollo-persisted-query-manifest"
+
+// allowlistKey is the Redis hash of the registered operations, by ID.
+const allowlistKey = "persistedQueries"
+
+// Operation is an operation of the front end, identified by the hash of its
+// body.
+type Operation struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Body string `json:"body"`
+}
+
+// Manifest lists the operations of a build of the front end.
+type Manifest struct {
+	Format     string      `json:"format"`
+	Version    int         `json:"version"`
+	Operations []Operation `json:"operations"`
+}
+
+// ReadManifest reads a manifest, checking that the ID of each operation is
+// the hash of its body.
+func ReadManifest(r io.Reader) (*Manifest, error) {
+	var m Manifest
+
+	err := json.NewDecoder(r).Decode(&m)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the manifest: %w", err)
+	}
+
+	if m.Format != ManifestFormat || m.Version != 1 {
+		return nil, fmt.Errorf("the manifest is in format %q version %d, not %q version 1", m.Format, m.Version, ManifestFormat)
+	}
+
+	for _, op := range m.Operations {
+		if op.Body == "" {
+			return nil, fmt.Errorf("operation %s (%s) has no body", op.ID, op.Name)
+		}
+		if Hash(op.Body) != op.ID {
+			return nil, fmt.Errorf("operation %s (%s) is not identified by the hash of its body", op.ID, op.Name)
+		}
+	}
+
+	return &m, nil
+}
+
+// Store holds the registered operations in Redis, where every replica of the
+// API reads them from.
+type Store struct {
+	// Redis returns the client the operations are stored with.
+	Redis func() *redis.Client
+}
+
+// Get returns the body of the registered operation with the given ID, if any.
+func (s Store) Get(ctx context.Context, id string) (string, bool, error) {
+	data, err := s.Redis().WithContext(ctx).HGet(allowlistKey, id).Bytes()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	var op Operation
+	err = json.Unmarshal(data, &op)
+	if err != nil {
+		return "", false, err
+	}
+
+	return op.Body, true, nil
+}
+
+// List returns every registered operation, ordered as Diff orders them.
+func (s Store) List(ctx context.Context) ([]Operation, error) {
+	entries, err := s.Redis().WithContext(ctx).HGetAll(allowlistKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	ops := make([]Operation, 0, len(entries))
+	for id, data := range entries {
+		var op Operation
+		err := json.Unmarshal([]byte(data), &op)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode operation %s: %w", id, err)
+		}
+		ops = append(ops, op)
+	}
+
+	sortOperations(ops)
+
+	return ops, nil
+}
+
+// Register adds the operations of the manifest to those registered. With
+// prune, the operations left out of the manifest are removed at the same
+// time, so that the allowlist is exactly the manifest.
+func (s Store) Register(ctx context.Context, m *Manifest, prune bool) error {
+	var stale []string
+
+	if prune {
+		registered, err := s.List(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, removed := Diff(registered, m.Operations)
+		for _, op := range removed {
+			stale = append(stale, op.ID)
+		}
+	}
+
+	fields := make(map[string]interface{}, len(m.Operations))
+	for _, op := range m.Operations {
+		data, err := json.Marshal(op)
+		if err != nil {
+			return err
+		}
+		fields[op.ID] = data
+	}
+
+	_, err := s.Redis().WithContext(ctx).TxPipelined(func(pipe redis.Pipeliner) error {
+		if len(fields) > 0 {
+			pipe.HMSet(allowlistKey, fields)
+		}
+		if len(stale) > 0 {
+			pipe.HDel(allowlistKey, stale...)
+		}
+		return nil
+	})
+
+	return err
+}
+
+// Diff returns the operations of the manifest that are not registered, and
+// the registered operations that are not in the manifest, by name then ID.
+func Diff(registered []Operation, manifest []Operation) (added []Operation, removed []Operation) {
+	inManifest := make(map[string]bool, len(manifest))
+	for _, op := range manifest {
+		inManifest[op.ID] = true
+	}
+
+	isRegistered := make(map[string]bool, len(registered))
+	for _, op := range registered {
+		isRegistered[op.ID] = true
+		if !inManifest[op.ID] {
+			removed = append(removed, op)
+		}
+	}
+
+	for _, op := range manifest {
+		if !isRegistered[op.ID] {
+			added = append(added, op)
+			// A manifest listing an operation twice adds it once.
+			isRegistered[op.ID] = true
+		}
+	}
+
+	sortOperations(added)
+	sortOperations(removed)
+
+	return added, removed
+}
+
+func sortOperations(ops []Operation) {
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Name != ops[j].Name {
+			return ops[i].Name < ops[j].Name
+		}
+		return ops[i].ID < ops[j].ID
+	})
+}
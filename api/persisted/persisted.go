@@ -0,0 +1,79 @@
+// Package persisted lets clients send GraphQL operations by the hash of their
+// query. Automatic persisted queries are cached in Redis, so that a hash
+// registered on one replica of the API is known to all of them. In allowlist
+// mode, only the operations registered from the manifest of the front end
+// build are run, whatever query the client sends.
+package persisted
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	"github.com/go-redis/redis"
+	"go.uber.org/zap"
+)
+
+// Modes of persisted queries.
+const (
+	// ModeOff runs the queries sent in full, and only them.
+	ModeOff = "off"
+	// ModeAPQ runs any query, and caches it by its hash for the clients
+	// that send it as an automatic persisted query.
+	ModeAPQ = "apq"
+	// ModeAllowlist only runs the operations registered from a manifest.
+	ModeAllowlist = "allowlist"
+)
+
+// DefaultCacheTTL is how long an automatic persisted query is cached since it
+// was last sent in full.
+const DefaultCacheTTL = 24 * time.Hour
+
+// Hash is the hash that identifies a query, as computed by clients: the
+// SHA-256 of its text, hex encoded.
+func Hash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+func cacheKey(hash string) string {
+	return "apq:" + hash
+}
+
+// Cache is the graphql.Cache of automatic persisted queries, in Redis.
+type Cache struct {
+	// Redis returns the client the queries are cached with.
+	Redis func() *redis.Client
+	// TTL is how long a query is cached; DefaultCacheTTL if zero.
+	TTL time.Duration
+}
+
+// Get returns the query with the given hash, if cached. Failing to reach
+// Redis is taken as a miss, upon which the client sends the query in full.
+func (c Cache) Get(ctx context.Context, hash string) (interface{}, bool) {
+	query, err := c.Redis().WithContext(ctx).Get(cacheKey(hash)).Result()
+	if err == redis.Nil {
+		return nil, false
+	}
+	if err != nil {
+		logging.FromContext(ctx).Warn("Unable to look up a persisted query", zap.Error(err))
+		return nil, false
+	}
+
+	return query, true
+}
+
+// Add caches the query under its hash, which the caller has checked.
+func (c Cache) Add(ctx context.Context, hash string, query interface{}) {
+	ttl := c.TTL
+	if ttl == 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	err := c.Redis().WithContext(ctx).Set(cacheKey(hash), query, ttl).Err()
+	if err != nil {
+		logging.FromContext(ctx).Warn("Unable to cache a persisted query", zap.Error(err))
+	}
+}
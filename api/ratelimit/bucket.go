@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// takeScript takes the cost from a token bucket, stored as a hash of its
+// tokens and when they were counted, after refilling it for the time since.
+// The bucket expires once it would be full anyway. It returns whether the
+// cost was taken, and the tokens left.
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+local now = tonumber(ARGV[4])
+
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "at")
+local tokens = tonumber(bucket[1]) or burst
+local at = tonumber(bucket[2]) or now
+
+tokens = math.min(burst, tokens + math.max(0, now - at) * rate)
+
+local taken = 0
+if tokens >= cost then
+	tokens = tokens - cost
+	taken = 1
+end
+
+redis.call("HMSET", KEYS[1], "tokens", tostring(tokens), "at", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil((burst - tokens) / rate) + 1000)
+
+return {taken, tostring(tokens)}
+`)
+
+// outcome is the state of a bucket once an operation has been charged to it.
+type outcome struct {
+	taken     bool
+	remaining int
+	// reset is how long until the bucket is full again.
+	reset time.Duration
+	// retryAfter is how long until the operation could be afforded, if it
+	// was not.
+	retryAfter time.Duration
+}
+
+// take charges cost to the bucket under key, which is refilled at rate.
+func take(ctx context.Context, client *redis.Client, key string, rate Rate, cost int, now time.Time) (outcome, error) {
+	perMs := float64(rate.PerMinute) / float64(time.Minute/time.Millisecond)
+
+	res, err := takeScript.Run(client.WithContext(ctx), []string{key},
+		perMs, rate.Burst, cost, now.UnixNano()/int64(time.Millisecond)).Result()
+	if err != nil {
+		return outcome{}, err
+	}
+
+	values, _ := res.([]interface{})
+	if len(values) != 2 {
+		return outcome{}, errUnexpectedReply
+	}
+
+	taken, _ := values[0].(int64)
+	tokens, err := parseFloat(values[1])
+	if err != nil {
+		return outcome{}, err
+	}
+
+	o := outcome{
+		taken:     taken == 1,
+		remaining: int(tokens),
+		reset:     msDuration((float64(rate.Burst) - tokens) / perMs),
+	}
+	if !o.taken {
+		o.retryAfter = msDuration((float64(cost) - tokens) / perMs)
+	}
+
+	return o, nil
+}
+
+// msDuration converts a number of milliseconds into a duration.
+func msDuration(ms float64) time.Duration {
+	if ms < 0 {
+		return 0
+	}
+	return time.Duration(ms * float64(time.Millisecond))
+}
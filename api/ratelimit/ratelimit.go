@@ -0,0 +1,349 @@
+// Package ratelimit limits how much each client of the API may ask of it. Every
+// client has a token bucket per type of operation, in Redis so that it is
+// shared by the replicas, and each operation takes as many tokens as its
+// complexity, as measured by the limits package. Clients are told of their
+// bucket in X-RateLimit-* headers.
+//
+// A client is the user of the session, or the IP address of anonymous
+// requests. Clients presenting a known access token, as a bearer token, have
+// a bucket and limits of their own instead. Access tokens do not
+// authenticate; they only select limits, for the servers calling the API on
+// behalf of many users.
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/allen-woods/the-supertask/api/auth"
+	"github.com/allen-woods/the-supertask/api/limits"
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	"github.com/go-redis/redis"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.uber.org/zap"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ErrRateLimited is the code of the errors of the operations over the rate
+// limit, in their extensions.
+const ErrRateLimited = "RATE_LIMITED"
+
+// Headers describing the bucket an operation was charged to. Reset is the
+// number of seconds until the bucket is full again.
+const (
+	HeaderLimit     = "X-RateLimit-Limit"
+	HeaderRemaining = "X-RateLimit-Remaining"
+	HeaderReset     = "X-RateLimit-Reset"
+	HeaderCost      = "X-RateLimit-Cost"
+)
+
+// Kinds of clients, as labelled in metrics.
+const (
+	clientAnonymous   = "anonymous"
+	clientUser        = "user"
+	clientAdmin       = "admin"
+	clientAccessToken = "accessToken"
+)
+
+var errUnexpectedReply = errors.New("unexpected reply from the rate limit script")
+
+var rejections = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "api",
+	Subsystem: "ratelimit",
+	Name:      "rejections_total",
+	Help:      "GraphQL operations rejected for being over the rate limit, by type and kind of client.",
+}, []string{"type", "client"})
+
+// Rate is how many tokens a bucket holds at most, and how many it regains
+// every minute.
+type Rate struct {
+	PerMinute int `yaml:"perMinute"`
+	Burst     int `yaml:"burst"`
+}
+
+// Limits are the rates of the buckets of a client, per type of operation.
+type Limits struct {
+	Query        Rate `yaml:"query"`
+	Mutation     Rate `yaml:"mutation"`
+	Subscription Rate `yaml:"subscription"`
+}
+
+// For returns the rate of the bucket of the given type of operation.
+func (l Limits) For(op ast.Operation) Rate {
+	switch op {
+	case ast.Mutation:
+		return l.Mutation
+	case ast.Subscription:
+		return l.Subscription
+	}
+	return l.Query
+}
+
+// AccessToken is a token that clients present to get limits of their own.
+// Only the SHA-256 of the token is kept, hex encoded.
+type AccessToken struct {
+	Name   string `yaml:"name"`
+	SHA256 string `yaml:"sha256"`
+	Limits `yaml:",inline"`
+}
+
+// ReadAccessTokens reads a YAML list of access tokens. The rates a token
+// leaves out are those given.
+func ReadAccessTokens(r io.Reader, defaults Limits) ([]AccessToken, error) {
+	var tokens []AccessToken
+
+	err := yaml.NewDecoder(r).Decode(&tokens)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for i := range tokens {
+		t := &tokens[i]
+
+		if t.Name == "" || names[t.Name] {
+			return nil, fmt.Errorf("access token %d must have a unique name", i)
+		}
+		names[t.Name] = true
+
+		if b, err := hex.DecodeString(t.SHA256); err != nil || len(b) != sha256.Size {
+			return nil, fmt.Errorf("access token %s: sha256 must be a hex encoded SHA-256", t.Name)
+		}
+		t.SHA256 = strings.ToLower(t.SHA256)
+
+		for _, rate := range []struct {
+			name string
+			rate *Rate
+			def  Rate
+		}{
+			{"query", &t.Query, defaults.Query},
+			{"mutation", &t.Mutation, defaults.Mutation},
+			{"subscription", &t.Subscription, defaults.Subscription},
+		} {
+			if *rate.rate == (Rate{}) {
+				*rate.rate = rate.def
+			}
+			if rate.rate.PerMinute <= 0 || rate.rate.Burst <= 0 {
+				return nil, fmt.Errorf("access token %s: the %s rate must be positive", t.Name, rate.name)
+			}
+		}
+	}
+
+	return tokens, nil
+}
+
+// ReadAccessTokensFile reads the access tokens in the file at path, if any.
+func ReadAccessTokensFile(path string, defaults Limits) ([]AccessToken, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadAccessTokens(f, defaults)
+}
+
+var requestCtxKey = &contextKey{"rateLimitRequest"}
+
+type contextKey struct {
+	name string
+}
+
+// request holds what the extension needs of the HTTP request an operation
+// came in.
+type request struct {
+	ip string
+	// tokenHash is the SHA-256 of the bearer token, if any.
+	tokenHash string
+
+	// mu guards header, as the operations of a WebSocket connection run
+	// concurrently.
+	mu     sync.Mutex
+	header http.Header
+}
+
+// Middleware gives the operations of the request their client, and a way to
+// set the X-RateLimit-* headers of the response.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+
+		req := &request{ip: ip, header: w.Header()}
+
+		if token := bearerToken(r); token != "" {
+			sum := sha256.Sum256([]byte(token))
+			req.tokenHash = hex.EncodeToString(sum[:])
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestCtxKey, req)))
+	})
+}
+
+// bearerToken returns the bearer token of the Authorization header, if any.
+func bearerToken(r *http.Request) string {
+	const prefix = "bearer "
+
+	h := r.Header.Get("Authorization")
+	if len(h) < len(prefix) || !strings.EqualFold(h[:len(prefix)], prefix) {
+		return ""
+	}
+
+	return strings.TrimSpace(h[len(prefix):])
+}
+
+// GraphQL is the gqlgen extension charging operations to the buckets of their
+// client. It must be used after limits.GraphQL, which measures them.
+type GraphQL struct {
+	// Redis returns the client the buckets are kept with.
+	Redis func() *redis.Client
+
+	Anonymous Limits
+	User      Limits
+	Admin     Limits
+
+	AccessTokens []AccessToken
+
+	// IsAdmin reports whether the request is made by an admin. It is called
+	// for every operation of a signed in user.
+	IsAdmin func(ctx context.Context) bool
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = GraphQL{}
+
+// ExtensionName names the extension in stats and logs.
+func (GraphQL) ExtensionName() string {
+	return "RateLimit"
+}
+
+// Validate checks that the buckets can be kept.
+func (g GraphQL) Validate(schema graphql.ExecutableSchema) error {
+	if g.Redis == nil {
+		return errors.New("RateLimit.Redis can not be nil")
+	}
+	return nil
+}
+
+// MutateOperationContext charges the operation to the bucket of its client,
+// and rejects it if the bucket cannot afford it. Failing to reach Redis lets
+// the operation through.
+func (g GraphQL) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	if rc.Operation == nil {
+		return nil
+	}
+
+	req, _ := ctx.Value(requestCtxKey).(*request)
+	if req == nil {
+		return nil
+	}
+
+	kind, client, clientLimits := g.client(ctx, req)
+	rate := clientLimits.For(rc.Operation.Operation)
+
+	// An operation costing more than a whole bucket takes all of it, rather
+	// than never being afforded.
+	cost := 1
+	if stats, ok := rc.Stats.GetExtension(limits.GraphQL{}.ExtensionName()).(*limits.Stats); ok && stats.Complexity > cost {
+		cost = stats.Complexity
+	}
+	if cost > rate.Burst {
+		cost = rate.Burst
+	}
+
+	opType := string(rc.Operation.Operation)
+	key := "rateLimit:" + opType + ":" + client
+
+	o, err := take(ctx, g.Redis(), key, rate, cost, time.Now())
+	if err != nil {
+		logging.FromContext(ctx).Warn("Unable to rate limit an operation", zap.Error(err))
+		return nil
+	}
+
+	req.mu.Lock()
+	req.header.Set(HeaderLimit, strconv.Itoa(rate.Burst))
+	req.header.Set(HeaderRemaining, strconv.Itoa(o.remaining))
+	req.header.Set(HeaderReset, strconv.Itoa(seconds(o.reset)))
+	req.header.Set(HeaderCost, strconv.Itoa(cost))
+	if !o.taken {
+		req.header.Set("Retry-After", strconv.Itoa(seconds(o.retryAfter)))
+	}
+	req.mu.Unlock()
+
+	if !o.taken {
+		rejections.WithLabelValues(opType, kind).Inc()
+
+		err := gqlerror.Errorf("rate limit exceeded, retry in %d seconds", seconds(o.retryAfter))
+		errcode.Set(err, ErrRateLimited)
+		err.Extensions["cost"] = cost
+		err.Extensions["limit"] = rate.Burst
+		err.Extensions["remaining"] = o.remaining
+		err.Extensions["retryAfter"] = seconds(o.retryAfter)
+		return err
+	}
+
+	return nil
+}
+
+// client returns the kind of the client of the request, the key of its
+// buckets and its limits.
+func (g GraphQL) client(ctx context.Context, req *request) (kind string, key string, l Limits) {
+	if req.tokenHash != "" {
+		for _, t := range g.AccessTokens {
+			if t.SHA256 == req.tokenHash {
+				return clientAccessToken, "token:" + t.Name, t.Limits
+			}
+		}
+	}
+
+	if userID := auth.ForContext(ctx); userID != "" {
+		if g.IsAdmin != nil && g.IsAdmin(ctx) {
+			return clientAdmin, "user:" + userID, g.Admin
+		}
+		return clientUser, "user:" + userID, g.User
+	}
+
+	return clientAnonymous, "ip:" + req.ip, g.Anonymous
+}
+
+// seconds rounds d up to whole seconds, for headers.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// parseFloat reads a number returned by the script as a string.
+func parseFloat(v interface{}) (float64, error) {
+	s, ok := v.(string)
+	if !ok {
+		return 0, errUnexpectedReply
+	}
+	return strconv.ParseFloat(s, 64)
+}
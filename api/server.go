@@ -1,29 +1,260 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
-	"github.com/allen-woods/gqlgen-api/graph"
-	"github.com/allen-woods/gqlgen-api/graph/generated"
+	"github.com/allen-woods/the-supertask/api/accesslog"
+	"github.com/allen-woods/the-supertask/api/audit"
+	"github.com/allen-woods/the-supertask/api/auth"
+	"github.com/allen-woods/the-supertask/api/avatar"
+	"github.com/allen-woods/the-supertask/api/blob"
+	"github.com/allen-woods/the-supertask/api/config"
+	"github.com/allen-woods/the-supertask/api/events"
+	"github.com/allen-woods/the-supertask/api/export"
+	"github.com/allen-woods/the-supertask/api/graph"
+	"github.com/allen-woods/the-supertask/api/graph/generated"
+	"github.com/allen-woods/the-supertask/api/health"
+	"github.com/allen-woods/the-supertask/api/limits"
+	"github.com/allen-woods/the-supertask/api/metrics"
+	"github.com/allen-woods/the-supertask/api/persisted"
+	"github.com/allen-woods/the-supertask/api/ratelimit"
+	"github.com/allen-woods/the-supertask/api/tracer"
+	"github.com/allen-woods/the-supertask/pkg/lifecycle"
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	"github.com/allen-woods/the-supertask/pkg/mtls"
+	"github.com/allen-woods/the-supertask/pkg/tracing"
+	"github.com/gorilla/websocket"
+	"github.com/rs/cors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/credentials"
 )
 
-const defaultPort = "8080"
-
 func main() {
-	port := os.Getenv("GRAPHQL_API_PORT")
-	if port == "" {
-		port = defaultPort
+	cfg, err := config.Load(os.Args[1:])
+	if err == flag.ErrHelp {
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if cfg.PrintOnly() {
+		err := cfg.Print(os.Stdout)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	logger, err := logging.New(cfg.Logging.Format, cfg.Logging.Level)
+	if err != nil {
+		log.Fatalf("Unable to set up logging: %v", err)
+	}
+	defer logger.Sync()
+	defer logging.Install(logger)()
+
+	auth.SetRedisOptions(cfg.RedisAddress(), cfg.Redis.Password.Value())
+	events.SetRedisClient(auth.RedisClient)
+	auth.SetCookieOptions(cfg.CookieOptions())
+	auth.SetImpersonationDuration(cfg.Impersonation.Duration)
+	export.SetRetention(cfg.DataExport.Retention)
+	blobs, err := cfg.BlobStore()
+	if err != nil {
+		logger.Fatal("Unable to set up blob storage", zap.Error(err))
+	}
+	avatar.SetStore(blobs)
+	export.SetStore(blobs)
+	avatar.SetPublicURL(cfg.Blobs.PublicURL)
+	avatar.SetMaxSize(int64(cfg.Avatars.MaxSize))
+	graph.SetUserServiceAddress(cfg.UserService.Address)
+	graph.SetUserServiceToken(cfg.UserService.Token.Value())
+
+	// The User service is called over mutual TLS, unless explicitly told
+	// otherwise for development.
+	var certs *mtls.Certificates
+	if cfg.UserService.Insecure {
+		logger.Warn("Calling the User service without TLS, which is only fit for development")
+	} else {
+		certs, err = mtls.Load(cfg.UserServiceTLSFiles())
+		if err != nil {
+			logger.Fatal("Unable to load the TLS certificates", zap.Error(err))
+		}
+		graph.SetUserServiceCredentials(credentials.NewTLS(certs.ClientConfig(cfg.UserServiceName())))
+	}
+
+	// Every call to the User service goes through this one connection.
+	_, err = graph.ConnectUserService()
+	if err != nil {
+		logger.Fatal("Unable to connect to the User service", zap.Error(err))
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.TracingOptions())
+	if err != nil {
+		logger.Fatal("Unable to set up tracing", zap.Error(err))
+	}
+
+	err = auth.Roll(cfg.Cookie.KeyDir)
+	if err != nil {
+		logger.Fatal("Unable to roll cookie keys", zap.Error(err))
 	}
 
-	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{Resolvers: &graph.Resolver{}}))
+	audit.SetRecorder(graph.RecordAuditEvent)
 
+	runner := lifecycle.New()
+	runner.DrainTimeout = cfg.Shutdown.DrainTimeout
+	runner.NotReadyDelay = cfg.Shutdown.NotReadyDelay
+	runner.CloseTimeout = cfg.Shutdown.CloseTimeout
+
+	if certs != nil {
+		runner.Go("certificate reloading", func() error {
+			certs.Watch(runner.Context(), mtls.DefaultReloadInterval)
+			return nil
+		})
+	}
+
+	runner.Go("account purge", func() error {
+		graph.PurgeDeletedUsers(runner.Context(), cfg.Accounts.PurgeInterval)
+		return nil
+	})
+	runner.Go("data export expiry", func() error {
+		export.DeleteExpired(runner.Context())
+		return nil
+	})
+
+	schemaCfg := generated.Config{Resolvers: &graph.Resolver{}}
+	schemaCfg.Directives.HasRole = graph.HasRole
+	schemaCfg.Directives.NoImpersonation = graph.NoImpersonation
+
+	// As handler.NewDefaultServer, but with subscriptions authenticated when
+	// their WebSocket connection is initialized, and only accepted from the
+	// allowed origins.
+	srv := handler.New(generated.NewExecutableSchema(schemaCfg))
+	srv.AddTransport(transport.Websocket{
+		Upgrader: websocket.Upgrader{
+			CheckOrigin: auth.WebsocketOriginChecker(cfg.HTTP.AllowedOrigins),
+		},
+		InitFunc:              auth.WebsocketInit,
+		KeepAlivePingInterval: 10 * time.Second,
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	// Multipart requests carry avatars, with room for the operation itself.
+	srv.AddTransport(transport.MultipartForm{
+		MaxUploadSize: avatar.MaxSize() + 1<<20,
+		MaxMemory:     avatar.MaxSize() + 1<<20,
+	})
+	srv.SetQueryCache(lru.New(1000))
+	if cfg.GraphQL.Introspection {
+		srv.Use(extension.Introspection{})
+	}
+	// Persisted queries are shared by the replicas through Redis. In allowlist
+	// mode, only the operations registered from the front end are run.
+	switch cfg.GraphQL.PersistedQueries {
+	case persisted.ModeAPQ:
+		srv.Use(extension.AutomaticPersistedQuery{Cache: persisted.Cache{Redis: auth.RedisClient, TTL: cfg.GraphQL.PersistedQueryTTL}})
+	case persisted.ModeAllowlist:
+		srv.Use(persisted.Allowlist{Store: persisted.Store{Redis: auth.RedisClient}})
+	}
+	srv.AroundOperations(auth.CSRFOperationMiddleware)
+	srv.AroundOperations(graph.UserLoaderOperationMiddleware)
+	userLimits, adminLimits := cfg.GraphQLLimits()
+	srv.Use(limits.GraphQL{User: userLimits, Admin: adminLimits, IsAdmin: graph.IsAdmin})
+	// Rate limiting charges operations by the complexity measured above.
+	if cfg.RateLimit.Enabled {
+		accessTokens, err := cfg.RateLimitAccessTokens()
+		if err != nil {
+			logger.Fatal("Unable to read the rate limit access tokens", zap.Error(err))
+		}
+		srv.Use(ratelimit.GraphQL{
+			Redis:        auth.RedisClient,
+			Anonymous:    cfg.RateLimit.Anonymous,
+			User:         cfg.RateLimit.User,
+			Admin:        cfg.RateLimit.Admin,
+			AccessTokens: accessTokens,
+			IsAdmin:      graph.IsAdmin,
+		})
+	}
+	srv.Use(metrics.GraphQL{})
+	srv.Use(tracer.GraphQL{})
+	srv.Use(accesslog.GraphQL{})
+
+	c := cors.New(cors.Options{
+		AllowedOrigins:   cfg.HTTP.AllowedOrigins,
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodDelete},
+		AllowedHeaders:   []string{"Content-Type", auth.CSRFHeader, logging.RequestIDHeader, graph.PasswordHeader},
+		ExposedHeaders:   []string{logging.RequestIDHeader, ratelimit.HeaderLimit, ratelimit.HeaderRemaining, ratelimit.HeaderReset, ratelimit.HeaderCost, "Retry-After"},
+		AllowCredentials: true,
+		Debug:            cfg.Env == "development",
+	})
+
+	// Liveness only covers the API itself, so that an outage of a dependency
+	// does not get it restarted; readiness covers everything a request needs.
+	keyring := health.Check{Name: "keyring", Run: func(ctx context.Context) error {
+		return auth.CheckKeyring()
+	}}
+	ready := health.Check{Name: "lifecycle", Run: func(ctx context.Context) error {
+		if !runner.Ready() {
+			return errors.New("not ready, starting or shutting down")
+		}
+		return nil
+	}}
+
+	http.Handle("/healthz", health.Handler(health.DefaultTimeout, keyring))
+	http.Handle("/readyz", health.Handler(health.DefaultTimeout,
+		ready,
+		keyring,
+		health.Check{Name: "redis", Run: auth.PingRedis},
+		health.Check{Name: "userService", Run: graph.CheckUserService},
+	))
+	http.Handle("/metrics", metrics.Handler())
 	http.Handle("/", playground.Handler("GraphQL playground", "/query"))
-	http.Handle("/query", srv)
+	http.Handle("/query", c.Handler(auth.CSRFMiddleware(cfg.HTTP.AllowedOrigins)(auth.Middleware()(ratelimit.Middleware(srv)))))
+	// The REST gateway to the User service follows the rules of the GraphQL
+	// schema, and its requests are checked against CSRF in the same way.
+	http.Handle(graph.GatewayPathPrefix, c.Handler(auth.CSRFMiddleware(cfg.HTTP.AllowedOrigins)(auth.Middleware()(auth.CSRFRequestMiddleware(graph.GatewayHandler())))))
+	http.Handle(export.PathPrefix, auth.Middleware()(export.Handler()))
+	// Local storage is served by the API itself; buckets serve themselves.
+	if local, ok := blobs.(*blob.Local); ok {
+		http.Handle(blob.PathPrefix, local.Handler())
+	}
 
-	log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
+	// The HTTP server is drained before the User service connection and the
+	// Redis client its requests use are closed. Every request is given a
+	// request ID, for its log lines.
+	runner.ServeHTTP("HTTP server", &http.Server{
+		Addr:     fmt.Sprintf(":%d", cfg.HTTP.Port),
+		Handler:  logging.Middleware(http.DefaultServeMux),
+		ErrorLog: zap.NewStdLog(logger.Named("http")),
+	})
+	runner.OnShutdown("User service connection", func(ctx context.Context) error {
+		return graph.CloseUserService()
+	})
+	runner.OnShutdown("Redis client", func(ctx context.Context) error {
+		return auth.CloseRedis()
+	})
+	// Spans are flushed last, once every request has ended.
+	runner.OnShutdown("tracer provider", shutdownTracing)
+
+	runner.SetReady()
+
+	logger.Info("Connect to the GraphQL playground",
+		zap.String("url", fmt.Sprintf("http://localhost:%d/", cfg.HTTP.Port)))
+
+	err = runner.Run()
+	if err != nil {
+		logger.Fatal("Unable to shut down cleanly", zap.Error(err))
+	}
 }
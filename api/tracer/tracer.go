@@ -0,0 +1,103 @@
+// Package tracer traces GraphQL operations with OpenTelemetry: every operation
+// gets a span, with a child span for every resolver it runs. The spans of the
+// User service calls and Redis lookups made by the resolvers nest under them.
+package tracer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName names the spans of the extension.
+const tracerName = "github.com/allen-woods/the-supertask/api/tracer"
+
+// Attributes of the spans.
+const (
+	operationNameKey = attribute.Key("graphql.operation.name")
+	operationTypeKey = attribute.Key("graphql.operation.type")
+	fieldPathKey     = attribute.Key("graphql.field.path")
+)
+
+// GraphQL is the gqlgen extension tracing operations and resolvers.
+type GraphQL struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = GraphQL{}
+
+// ExtensionName names the extension in stats and logs.
+func (GraphQL) ExtensionName() string {
+	return "Tracer"
+}
+
+// Validate accepts any schema.
+func (GraphQL) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptResponse spans the operation of each response. The resolvers of an
+// operation run within its response, and so within the span.
+func (GraphQL) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+
+	oc := graphql.GetOperationContext(ctx)
+
+	opType := "operation"
+	if oc.Operation != nil {
+		opType = string(oc.Operation.Operation)
+	}
+
+	name := opType
+	if oc.OperationName != "" {
+		name = fmt.Sprintf("%s %s", opType, oc.OperationName)
+	}
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, name,
+		trace.WithTimestamp(oc.Stats.OperationStart),
+		trace.WithAttributes(
+			operationNameKey.String(oc.OperationName),
+			operationTypeKey.String(opType),
+		),
+	)
+	defer span.End()
+
+	res := next(ctx)
+
+	if res != nil && len(res.Errors) > 0 {
+		span.SetStatus(codes.Error, res.Errors.Error())
+	}
+
+	return res
+}
+
+// InterceptField spans the fields backed by a method, which are those doing
+// actual work, as opposed to reading a struct field.
+func (GraphQL) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsMethod {
+		return next(ctx)
+	}
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, fc.Object+"."+fc.Field.Name,
+		trace.WithAttributes(fieldPathKey.String(fc.Path().String())),
+	)
+	defer span.End()
+
+	res, err := next(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return res, err
+}
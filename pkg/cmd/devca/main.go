@@ -0,0 +1,249 @@
+// Command devca is a certificate authority for local development. It creates
+// the CA, then issues the certificates the services present to each other
+// over mutual TLS, naming each service by its DNS names:
+//
+//	devca init -dir certs
+//	devca issue -dir certs -name user -dns localhost
+//	devca issue -dir certs -name api
+//
+// Issued certificates are written to <dir>/<name>.pem and <dir>/<name>-key.pem,
+// along with the CA in <dir>/ca.pem. They are fit for both serving and
+// calling. Never use them outside of development.
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Lifetimes of the certificates.
+const (
+	caLifetime   = 10 * 365 * 24 * time.Hour
+	certLifetime = 365 * 24 * time.Hour
+)
+
+const usage = `Usage:
+  devca init -dir <dir>
+  devca issue -dir <dir> -name <name> [-dns <names>] [-ip <addresses>]
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "init":
+		err = initCA(os.Args[2:])
+	case "issue":
+		err = issue(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// initCA creates the key and certificate of the CA.
+func initCA(args []string) error {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	dir := fs.String("dir", "certs", "directory to write the CA to")
+	force := fs.Bool("force", false, "replace an existing CA, invalidating every certificate it issued")
+	fs.Parse(args)
+
+	certPath := filepath.Join(*dir, "ca.pem")
+
+	if _, err := os.Stat(certPath); err == nil && !*force {
+		return fmt.Errorf("A CA already exists in %s; pass -force to replace it", *dir)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	template, err := newTemplate("The Supertask development CA", caLifetime)
+	if err != nil {
+		return err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(*dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	err = writeKeyPair(certPath, filepath.Join(*dir, "ca-key.pem"), der, key)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote the CA to %s\n", certPath)
+	return nil
+}
+
+// issue creates a certificate for a service, signed by the CA.
+func issue(args []string) error {
+	fs := flag.NewFlagSet("issue", flag.ExitOnError)
+	dir := fs.String("dir", "certs", "directory holding the CA, and to write the certificate to")
+	name := fs.String("name", "", "name of the service, its first DNS name")
+	dns := fs.String("dns", "", "comma separated DNS names besides the name")
+	ips := fs.String("ip", "", "comma separated IP addresses")
+	fs.Parse(args)
+
+	if *name == "" {
+		return errors.New("A -name is required")
+	}
+
+	caCert, caKey, err := readCA(*dir)
+	if err != nil {
+		return err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	template, err := newTemplate(*name, certLifetime)
+	if err != nil {
+		return err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	// Peers are authorized by the first DNS name.
+	template.DNSNames = append([]string{*name}, split(*dns)...)
+
+	for _, s := range split(*ips) {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return fmt.Errorf("Invalid IP address %q", s)
+		}
+		template.IPAddresses = append(template.IPAddresses, ip)
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return err
+	}
+
+	certPath := filepath.Join(*dir, *name+".pem")
+
+	err = writeKeyPair(certPath, filepath.Join(*dir, *name+"-key.pem"), der, key)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote the certificate of %s to %s, valid for %s\n", *name, certPath, strings.Join(template.DNSNames, ", "))
+	return nil
+}
+
+// newTemplate returns a certificate template for commonName, valid from now on
+// for lifetime.
+func newTemplate(commonName string, lifetime time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		// Some slack for clocks running behind.
+		NotBefore: now.Add(-time.Hour),
+		NotAfter:  now.Add(lifetime),
+	}, nil
+}
+
+// readCA reads the certificate and key of the CA from dir.
+func readCA(dir string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	certPEM, err := ioutil.ReadFile(filepath.Join(dir, "ca.pem"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("Unable to read the CA, run devca init first: %v", err)
+	}
+	keyPEM, err := ioutil.ReadFile(filepath.Join(dir, "ca-key.pem"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("Unable to read the CA key: %v", err)
+	}
+
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, nil, errors.New("No certificate found in ca.pem")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block, _ = pem.Decode(keyPEM)
+	if block == nil {
+		return nil, nil, errors.New("No key found in ca-key.pem")
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cert, key, nil
+}
+
+// writeKeyPair writes the certificate der and its key as PEM files. The key
+// file is only readable by its owner.
+func writeKeyPair(certPath string, keyPath string, der []byte, key *ecdsa.PrivateKey) error {
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	// The key is written first, so that a reloading service never sees the
+	// new certificate with the old key for long.
+	err = ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)
+}
+
+// split splits a comma separated list, dropping empty items.
+func split(s string) []string {
+	items := []string{}
+
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,15 @@
+module github.com/allen-woods/the-supertask/pkg
+
+go 1.13
+
+require (
+	github.com/satori/go.uuid v1.2.0
+	go.opentelemetry.io/otel v0.20.0
+	go.opentelemetry.io/otel/exporters/otlp v0.20.0
+	go.opentelemetry.io/otel/exporters/stdout v0.20.0
+	go.opentelemetry.io/otel/sdk v0.20.0
+	go.uber.org/zap v1.16.0
+	google.golang.org/grpc v1.37.0
+	google.golang.org/protobuf v1.26.0
+	gopkg.in/yaml.v2 v2.2.8
+)
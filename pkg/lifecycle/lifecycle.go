@@ -0,0 +1,264 @@
+// Package lifecycle runs a server process from start to shutdown. A Runner
+// starts the servers and background tasks of the process, waits for SIGINT or
+// SIGTERM, which Docker sends on stop, and then shuts everything down in order:
+// it reports not ready, drains the servers within a timeout, and finally
+// closes the clients the servers used, each within a timeout of its own.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultDrainTimeout is how long servers are given to finish what they are
+// doing before they are stopped, unless the Runner says otherwise.
+const DefaultDrainTimeout = 15 * time.Second
+
+// DefaultCloseTimeout is how long each other step of the shutdown is given,
+// unless the Runner says otherwise.
+const DefaultCloseTimeout = 5 * time.Second
+
+// Runner runs the servers and tasks of a process until it is told to stop.
+type Runner struct {
+	// DrainTimeout bounds the draining of the servers, from the moment the
+	// process reports not ready to the last server being stopped.
+	DrainTimeout time.Duration
+	// CloseTimeout bounds each step of the shutdown that is not a server,
+	// such as closing a client, and then waiting for background tasks, so
+	// that a slow step does not leave the next ones no time.
+	CloseTimeout time.Duration
+	// NotReadyDelay is how long the process keeps serving after reporting
+	// not ready, so that load balancers stop sending it traffic before it
+	// drains.
+	NotReadyDelay time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	ready    int32
+	notReady chan struct{}
+
+	mu     sync.Mutex
+	hooks  []hook
+	failed chan error
+	tasks  sync.WaitGroup
+}
+
+// hook is a step of the shutdown. Servers drain within the drain timeout,
+// other steps within a close timeout of their own.
+type hook struct {
+	name   string
+	server bool
+	stop   func(ctx context.Context) error
+}
+
+// New returns a Runner with the default drain and close timeouts.
+func New() *Runner {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Runner{
+		DrainTimeout: DefaultDrainTimeout,
+		CloseTimeout: DefaultCloseTimeout,
+		ctx:          ctx,
+		cancel:       cancel,
+		notReady:     make(chan struct{}),
+		failed:       make(chan error, 1),
+	}
+}
+
+// Context is done once the shutdown starts. Background tasks should stop when
+// it is.
+func (r *Runner) Context() context.Context {
+	return r.ctx
+}
+
+// Ready is whether the process is ready for traffic: it has been marked ready
+// and is not shutting down.
+func (r *Runner) Ready() bool {
+	return atomic.LoadInt32(&r.ready) == 1
+}
+
+// NotReady is closed once the shutdown starts, when the process reports not
+// ready for good; before NotReadyDelay, and so before Context is done.
+// Health services should report that the process is not serving from then on.
+func (r *Runner) NotReady() <-chan struct{} {
+	return r.notReady
+}
+
+// SetReady marks the process ready for traffic, once everything has started.
+func (r *Runner) SetReady() {
+	atomic.StoreInt32(&r.ready, 1)
+}
+
+// Go runs task in the background. A task returning an error, such as a server
+// that cannot serve, shuts the process down; a task returning nil simply ends.
+// Tasks are waited for at the end of the shutdown.
+func (r *Runner) Go(name string, task func() error) {
+	r.tasks.Add(1)
+
+	go func() {
+		defer r.tasks.Done()
+
+		err := task()
+		if err == nil {
+			return
+		}
+
+		select {
+		case r.failed <- fmt.Errorf("%s: %w", name, err):
+		default:
+		}
+	}()
+}
+
+// OnShutdown adds a step to the shutdown. Steps run one after the other, in
+// the order they were added, so servers should be added before the clients
+// they use. ctx ends after the close timeout, however long the steps before
+// took.
+func (r *Runner) OnShutdown(name string, stop func(ctx context.Context) error) {
+	r.addHook(hook{name: name, stop: stop})
+}
+
+func (r *Runner) addHook(h hook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.hooks = append(r.hooks, h)
+}
+
+// Run waits for SIGINT or SIGTERM, or for a task to fail, then shuts the
+// process down. It returns the error of the failed task, or of the first step
+// of the shutdown that failed.
+func (r *Runner) Run() error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	var cause error
+
+	select {
+	case sig := <-signals:
+		zap.L().Info("Received signal, shutting down", zap.Stringer("signal", sig))
+	case cause = <-r.failed:
+		zap.L().Error("Shutting down after a task failed", zap.Error(cause))
+	}
+
+	return r.shutdown(cause)
+}
+
+func (r *Runner) shutdown(cause error) error {
+	atomic.StoreInt32(&r.ready, 0)
+	close(r.notReady)
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), r.DrainTimeout)
+	defer cancel()
+
+	if r.NotReadyDelay > 0 {
+		select {
+		case <-time.After(r.NotReadyDelay):
+		case <-drainCtx.Done():
+		}
+	}
+
+	r.cancel()
+
+	r.mu.Lock()
+	hooks := r.hooks
+	r.mu.Unlock()
+
+	err := cause
+
+	for _, h := range hooks {
+		stopErr := r.stop(drainCtx, h)
+		if stopErr != nil {
+			zap.L().Error("Unable to stop", zap.String("step", h.name), zap.Error(stopErr))
+			if err == nil {
+				err = fmt.Errorf("%s: %w", h.name, stopErr)
+			}
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		r.tasks.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(r.CloseTimeout):
+		zap.L().Warn("Gave up waiting for background tasks to end")
+	}
+
+	return err
+}
+
+// stop runs the step h, within what is left of drainCtx for servers, and
+// within a close timeout of its own otherwise.
+func (r *Runner) stop(drainCtx context.Context, h hook) error {
+	if h.server {
+		return h.stop(drainCtx)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.CloseTimeout)
+	defer cancel()
+
+	return h.stop(ctx)
+}
+
+// ServeHTTP serves srv until the shutdown, which waits for its requests to
+// complete and closes it if they do not in time.
+func (r *Runner) ServeHTTP(name string, srv *http.Server) {
+	r.Go(name, func() error {
+		err := srv.ListenAndServe()
+		if err == http.ErrServerClosed {
+			return nil
+		}
+		return err
+	})
+
+	r.addHook(hook{name: name, server: true, stop: func(ctx context.Context) error {
+		err := srv.Shutdown(ctx)
+		if err == context.DeadlineExceeded {
+			return srv.Close()
+		}
+		return err
+	}})
+}
+
+// GRPCServer is the part of a *grpc.Server the Runner stops.
+type GRPCServer interface {
+	GracefulStop()
+	Stop()
+}
+
+// StopGRPC adds a step to the shutdown that waits for the RPCs of srv to
+// complete, and cancels those that do not in time.
+func (r *Runner) StopGRPC(name string, srv GRPCServer) {
+	r.addHook(hook{name: name, server: true, stop: func(ctx context.Context) error {
+		stopped := make(chan struct{})
+
+		go func() {
+			srv.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+			return nil
+		case <-ctx.Done():
+			srv.Stop()
+			return errors.New("RPCs still running after the drain timeout were cancelled")
+		}
+	}})
+}
@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// requestIDMetadataKey is the gRPC metadata key carrying request IDs.
+const requestIDMetadataKey = "x-request-id"
+
+// outgoing passes the request ID of ctx on to the server called.
+func outgoing(ctx context.Context) context.Context {
+	if id := RequestID(ctx); id != "" {
+		return metadata.AppendToOutgoingContext(ctx, requestIDMetadataKey, id)
+	}
+
+	return ctx
+}
+
+// UnaryClientInterceptor passes the request ID of every call on.
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(outgoing(ctx), method, req, reply, cc, opts...)
+}
+
+// StreamClientInterceptor passes the request ID of every stream on.
+func StreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(outgoing(ctx), desc, cc, method, opts...)
+}
+
+// incoming carries the request ID passed by the caller, or a new one, in
+// ctx, along with a logger adding it and the method to every line.
+func incoming(ctx context.Context, method string) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	id := ""
+	if values := md.Get(requestIDMetadataKey); len(values) > 0 {
+		id = values[0]
+	}
+	if !validRequestID(id) {
+		id = NewRequestID()
+	}
+
+	ctx = WithRequestID(ctx, id)
+	return WithLogger(ctx, FromContext(ctx).With(zap.String("method", method)))
+}
+
+// UnaryServerInterceptor carries the request ID of every call in its context.
+// At the debug level, requests are logged with their secrets redacted.
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx = incoming(ctx, info.FullMethod)
+
+	if logger := FromContext(ctx); logger.Core().Enabled(zapcore.DebugLevel) {
+		logger.Debug("Received request", zap.Any("request", Redact(req)))
+	}
+
+	return handler(ctx, req)
+}
+
+// StreamServerInterceptor carries the request ID of every stream in its
+// context.
+func StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &serverStream{ServerStream: ss, ctx: incoming(ss.Context(), info.FullMethod)})
+}
+
+// serverStream is a grpc.ServerStream with another context.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,108 @@
+// Package logging builds the structured logger of the servers, and carries it
+// in request contexts along with the ID of the request, so that every line
+// logged while serving a request can be correlated, across services.
+//
+// Fields whose key names a secret, such as "password" or "token", are
+// redacted by the logger itself, including within structured values such as
+// requests; see Redact.
+package logging
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Formats of the log lines.
+const (
+	// FormatJSON writes a JSON object per line, for production.
+	FormatJSON = "json"
+	// FormatConsole writes colored, human-readable lines, for development.
+	FormatConsole = "console"
+)
+
+// ValidFormat is whether format is one of the Format constants.
+func ValidFormat(format string) bool {
+	return format == FormatJSON || format == FormatConsole
+}
+
+// ValidLevel is whether level is a level name, such as "debug" or "info".
+func ValidLevel(level string) bool {
+	var l zapcore.Level
+	return l.UnmarshalText([]byte(level)) == nil
+}
+
+// New returns a logger writing lines of the given format to standard error,
+// from level up.
+func New(format string, level string) (*zap.Logger, error) {
+	var cfg zap.Config
+
+	switch format {
+	case FormatJSON:
+		cfg = zap.NewProductionConfig()
+	case FormatConsole:
+		cfg = zap.NewDevelopmentConfig()
+		cfg.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+
+	err := cfg.Level.UnmarshalText([]byte(level))
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.Build(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return redactingCore{core}
+	}))
+}
+
+// Install makes logger the one returned by FromContext for contexts without
+// one, and the destination of the standard log package, for the libraries
+// still using it. It returns a function undoing it.
+func Install(logger *zap.Logger) func() {
+	undoGlobals := zap.ReplaceGlobals(logger)
+	undoStdLog := zap.RedirectStdLog(logger)
+
+	return func() {
+		undoStdLog()
+		undoGlobals()
+	}
+}
+
+type ctxKey int
+
+const (
+	loggerKey ctxKey = iota
+	requestIDKey
+)
+
+// FromContext returns the logger of the request ctx belongs to, which adds
+// its request ID to every line, or the installed logger outside of requests.
+func FromContext(ctx context.Context) *zap.Logger {
+	if logger, ok := ctx.Value(loggerKey).(*zap.Logger); ok {
+		return logger
+	}
+
+	return zap.L()
+}
+
+// WithLogger returns a copy of ctx carrying logger.
+func WithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// WithRequestID returns a copy of ctx carrying the ID of the request it
+// belongs to, and a logger adding it to every line.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey, id)
+	return WithLogger(ctx, FromContext(ctx).With(zap.String("requestId", id)))
+}
+
+// RequestID returns the ID of the request ctx belongs to, if any.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
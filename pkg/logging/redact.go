@@ -0,0 +1,131 @@
+package logging
+
+import (
+	"encoding/json"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// redacted replaces the values that are never logged.
+const redacted = "[redacted]"
+
+// sensitiveKeys are the substrings, in lower case, of the keys whose values
+// are never logged.
+var sensitiveKeys = []string{"password", "secret", "token", "authorization", "cookie", "apikey", "api_key"}
+
+// sensitive is whether the value of key must not be logged.
+func sensitive(key string) bool {
+	key = strings.ToLower(key)
+
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Redact returns a copy of v, such as a request, fit for logging: it is
+// turned into its JSON form, with the values of sensitive keys replaced at any
+// depth. Protobuf messages are turned into their protojson form.
+func Redact(v interface{}) interface{} {
+	var b []byte
+	var err error
+
+	if m, ok := v.(proto.Message); ok {
+		b, err = protojson.Marshal(m)
+	} else {
+		b, err = json.Marshal(v)
+	}
+	if err != nil {
+		return "[unloggable: " + err.Error() + "]"
+	}
+
+	var doc interface{}
+
+	err = json.Unmarshal(b, &doc)
+	if err != nil {
+		return "[unloggable: " + err.Error() + "]"
+	}
+
+	return redactValue(doc)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if sensitive(key) {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactValue(value)
+		}
+	}
+
+	return v
+}
+
+// redactFields redacts the fields named after a secret, and the structured
+// values of the others.
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+
+	for i, f := range fields {
+		var replacement zapcore.Field
+
+		switch {
+		case sensitive(f.Key):
+			replacement = zap.String(f.Key, redacted)
+		case f.Type == zapcore.ReflectType:
+			replacement = zap.Any(f.Key, Redact(f.Interface))
+		default:
+			if out != nil {
+				out = append(out, f)
+			}
+			continue
+		}
+
+		// Fields are only copied once one of them needs replacing.
+		if out == nil {
+			out = append(make([]zapcore.Field, 0, len(fields)), fields[:i]...)
+		}
+		out = append(out, replacement)
+	}
+
+	if out == nil {
+		return fields
+	}
+
+	return out
+}
+
+// redactingCore redacts the fields of every line before writing it.
+type redactingCore struct {
+	zapcore.Core
+}
+
+func (c redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return redactingCore{c.Core.With(redactFields(fields))}
+}
+
+func (c redactingCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(e.Level) {
+		return ce.AddCore(e, c)
+	}
+
+	return ce
+}
+
+func (c redactingCore) Write(e zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(e, redactFields(fields))
+}
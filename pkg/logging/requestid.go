@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"net/http"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// RequestIDHeader is the HTTP header a request ID is accepted from, and
+// returned in.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the request IDs accepted from callers.
+const maxRequestIDLength = 128
+
+// NewRequestID returns a random request ID.
+func NewRequestID() string {
+	return uuid.NewV4().String()
+}
+
+// validRequestID is whether id, given by a caller, can be used as a request
+// ID: it must be short and made of characters safe to log and to pass on in
+// headers.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// Middleware gives every request an ID, the one of its X-Request-ID header
+// when valid or a new one, returns it in the X-Request-ID header of the
+// response, and carries it in the context of the request.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = NewRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+
+		next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), id)))
+	})
+}
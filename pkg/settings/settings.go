@@ -0,0 +1,413 @@
+// Package settings fills the configuration structs of the services. Their
+// settings are read, by increasing precedence, from their defaults, a YAML
+// file, environment variables and command line flags, as named by the tags
+// of their fields: "yaml" for the key in the file, nested structs being
+// prefixed with theirs, "env", "flag" and "usage".
+package settings
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Secret is a configuration value that is never printed. It is given either
+// literally or as a reference: "file:<path>" reads the value from a file, as
+// mounted by Docker secrets, and "env:<NAME>" from another environment
+// variable.
+type Secret struct {
+	value string
+	ref   string
+}
+
+// NewSecret returns a secret given literally, such as a default.
+func NewSecret(value string) Secret {
+	return Secret{value: value}
+}
+
+// Value returns the secret itself.
+func (s Secret) Value() string {
+	return s.value
+}
+
+// String returns the reference the secret was read from, or a placeholder.
+func (s Secret) String() string {
+	switch {
+	case s.ref != "":
+		return s.ref
+	case s.value != "":
+		return "[redacted]"
+	}
+
+	return ""
+}
+
+// ParseSecret returns the secret given by raw, literally or as a reference,
+// such as in a command line flag.
+func ParseSecret(raw string) (Secret, error) {
+	switch {
+	case strings.HasPrefix(raw, "file:"):
+		b, err := ioutil.ReadFile(strings.TrimPrefix(raw, "file:"))
+		if err != nil {
+			return Secret{}, err
+		}
+		return Secret{value: strings.TrimRight(string(b), "\r\n"), ref: raw}, nil
+	case strings.HasPrefix(raw, "env:"):
+		name := strings.TrimPrefix(raw, "env:")
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return Secret{}, fmt.Errorf("%s is not set", name)
+		}
+		return Secret{value: value, ref: raw}, nil
+	}
+
+	return Secret{value: raw}, nil
+}
+
+var (
+	secretType   = reflect.TypeOf(Secret{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// setting is a leaf of a configuration struct, along with where it is read
+// from.
+type setting struct {
+	path  string
+	env   string
+	flag  string
+	usage string
+	value reflect.Value
+}
+
+// describe names the setting in errors, with every way of setting it.
+func (s *setting) describe() string {
+	names := []string{s.path}
+	if s.env != "" {
+		names = append(names, s.env)
+	}
+	if s.flag != "" {
+		names = append(names, "--"+s.flag)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// settings lists the leaves of the struct v points to, in declaration order.
+// Nested structs are prefixed with their key.
+func settings(v reflect.Value, prefix string) []*setting {
+	list := []*setting{}
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+
+		key := field.Tag.Get("yaml")
+		if key == "" {
+			continue
+		}
+
+		path := prefix + key
+
+		if field.Type.Kind() == reflect.Struct && field.Type != secretType {
+			list = append(list, settings(v.Field(i), path+".")...)
+			continue
+		}
+
+		list = append(list, &setting{
+			path:  path,
+			env:   field.Tag.Get("env"),
+			flag:  field.Tag.Get("flag"),
+			usage: field.Tag.Get("usage"),
+			value: v.Field(i),
+		})
+	}
+
+	return list
+}
+
+// set parses raw into the setting.
+func (s *setting) set(raw string) error {
+	switch {
+	case s.value.Type() == secretType:
+		secret, err := ParseSecret(raw)
+		if err != nil {
+			return err
+		}
+		s.value.Set(reflect.ValueOf(secret))
+	case s.value.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		s.value.SetInt(int64(d))
+	case s.value.Kind() == reflect.String:
+		s.value.SetString(raw)
+	case s.value.Kind() == reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		s.value.SetInt(int64(n))
+	case s.value.Kind() == reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		s.value.SetFloat(f)
+	case s.value.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		s.value.SetBool(b)
+	case s.value.Kind() == reflect.Slice:
+		list := []string{}
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		s.value.Set(reflect.ValueOf(list))
+	default:
+		return fmt.Errorf("unsupported type %s", s.value.Type())
+	}
+
+	return nil
+}
+
+// printable is the value of the setting as printed, with secrets redacted.
+func (s *setting) printable() interface{} {
+	switch v := s.value.Interface().(type) {
+	case Secret:
+		return v.String()
+	case time.Duration:
+		return v.String()
+	}
+
+	return s.value.Interface()
+}
+
+// flatten turns the nested maps of a YAML document into values keyed by
+// dotted paths. Lists become comma separated.
+func flatten(m map[interface{}]interface{}, prefix string, out map[string]string) {
+	for k, v := range m {
+		path := prefix + fmt.Sprint(k)
+
+		switch v := v.(type) {
+		case map[interface{}]interface{}:
+			flatten(v, path+".", out)
+		case []interface{}:
+			items := []string{}
+			for _, item := range v {
+				items = append(items, fmt.Sprint(item))
+			}
+			out[path] = strings.Join(items, ",")
+		case nil:
+			out[path] = ""
+		default:
+			out[path] = fmt.Sprint(v)
+		}
+	}
+}
+
+// readFile reads a YAML configuration file into values keyed by dotted paths.
+func readFile(path string) (map[string]string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := map[interface{}]interface{}{}
+
+	err = yaml.Unmarshal(b, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{}
+	flatten(doc, "", values)
+
+	return values, nil
+}
+
+// Loader fills a configuration struct from, by increasing precedence, its
+// defaults, a YAML file, environment variables and command line flags. It is
+// then used to validate the settings, reporting every problem at once.
+type Loader struct {
+	// Name is the name of the program, in the usage of its flags.
+	Name string
+	// FileEnv is the environment variable naming the configuration file,
+	// unless given by --config.
+	FileEnv string
+
+	settings []*setting
+	// set holds the paths of the settings read from any source, as opposed
+	// to left at their default.
+	set map[string]bool
+	// print is whether --print-config was given.
+	print bool
+	// problems are the settings that could not be read, reported along
+	// with those that are invalid.
+	problems []string
+}
+
+// Load fills cfg, a pointer to a struct, from args and the environment.
+func (l *Loader) Load(cfg interface{}, args []string) error {
+	l.settings = settings(reflect.ValueOf(cfg).Elem(), "")
+	l.set = map[string]bool{}
+
+	fs := flag.NewFlagSet(l.Name, flag.ContinueOnError)
+
+	configPath := fs.String("config", os.Getenv(l.FileEnv), "YAML configuration file (env "+l.FileEnv+")")
+	fs.BoolVar(&l.print, "print-config", false, "print the configuration, with secrets redacted, and exit")
+
+	flags := map[string]*string{}
+	for _, s := range l.settings {
+		if s.flag != "" {
+			usage := s.usage
+			if s.env != "" {
+				usage += " (env " + s.env + ")"
+			}
+			flags[s.flag] = fs.String(s.flag, "", usage)
+		}
+	}
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	given := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+
+	file := map[string]string{}
+	if *configPath != "" {
+		file, err = readFile(*configPath)
+		if err != nil {
+			return fmt.Errorf("could not read %s: %v", *configPath, err)
+		}
+	}
+
+	known := map[string]bool{}
+	for _, s := range l.settings {
+		known[s.path] = true
+	}
+	for path := range file {
+		if !known[path] {
+			l.problems = append(l.problems, fmt.Sprintf("%s: unknown setting in %s", path, *configPath))
+		}
+	}
+
+	for _, s := range l.settings {
+		var raw, source string
+		var ok bool
+
+		switch {
+		case s.flag != "" && given[s.flag]:
+			raw, source, ok = *flags[s.flag], "--"+s.flag, true
+		case s.env != "" && os.Getenv(s.env) != "":
+			raw, source, ok = os.Getenv(s.env), s.env, true
+		default:
+			raw, ok = file[s.path]
+			source = *configPath
+		}
+
+		if !ok {
+			continue
+		}
+
+		err := s.set(raw)
+		if err != nil {
+			l.problems = append(l.problems, fmt.Sprintf("%s: %v (from %s)", s.path, err, source))
+			continue
+		}
+
+		l.set[s.path] = true
+	}
+
+	return nil
+}
+
+// IsSet reports whether the setting at path was read from any source, as
+// opposed to left at its default.
+func (l *Loader) IsSet(path string) bool {
+	return l.set[path]
+}
+
+// PrintOnly is whether --print-config was given.
+func (l *Loader) PrintOnly() bool {
+	return l.print
+}
+
+// Check validates a setting: it records a problem when ok is false.
+func (l *Loader) Check(path string, ok bool, format string, args ...interface{}) {
+	if ok {
+		return
+	}
+
+	for _, s := range l.settings {
+		if s.path == path {
+			path = s.describe()
+		}
+	}
+
+	l.problems = append(l.problems, fmt.Sprintf("%s: %s", path, fmt.Sprintf(format, args...)))
+}
+
+// Err reports every problem found at once, so that they can all be fixed
+// before starting again.
+func (l *Loader) Err() error {
+	if len(l.problems) == 0 {
+		return nil
+	}
+
+	sort.Strings(l.problems)
+
+	return errors.New("invalid configuration:\n  " + strings.Join(l.problems, "\n  "))
+}
+
+// Print writes the settings of cfg, a pointer to a struct, to w as YAML, with
+// secrets redacted.
+func Print(w io.Writer, cfg interface{}) error {
+	doc := yaml.MapSlice{}
+
+	for _, s := range settings(reflect.ValueOf(cfg).Elem(), "") {
+		keys := strings.Split(s.path, ".")
+		doc = insert(doc, keys, s.printable())
+	}
+
+	b, err := yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
+	return err
+}
+
+// insert sets the value at keys in doc, keeping the order keys were added in.
+func insert(doc yaml.MapSlice, keys []string, value interface{}) yaml.MapSlice {
+	if len(keys) == 1 {
+		return append(doc, yaml.MapItem{Key: keys[0], Value: value})
+	}
+
+	for i, item := range doc {
+		if item.Key == keys[0] {
+			doc[i].Value = insert(item.Value.(yaml.MapSlice), keys[1:], value)
+			return doc
+		}
+	}
+
+	return append(doc, yaml.MapItem{Key: keys[0], Value: insert(yaml.MapSlice{}, keys[1:], value)})
+}
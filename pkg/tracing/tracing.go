@@ -0,0 +1,136 @@
+// Package tracing sets up OpenTelemetry tracing for a server process: where
+// its spans are exported to, how many traces it samples, and the propagation
+// of trace context to and from the processes it calls.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/exporters/stdout"
+	"go.opentelemetry.io/otel/propagation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.uber.org/zap"
+)
+
+// Exporters spans can be sent to.
+const (
+	// ExporterNone drops spans, while still propagating the trace context of
+	// callers.
+	ExporterNone = "none"
+	// ExporterOTLP sends spans to an OpenTelemetry collector over gRPC.
+	ExporterOTLP = "otlp"
+	// ExporterStdout writes spans as JSON, to standard output or a file, for
+	// local development.
+	ExporterStdout = "stdout"
+)
+
+// Options says how a process exports its spans.
+type Options struct {
+	// ServiceName names the process in traces.
+	ServiceName string
+	// Exporter is one of the Exporter constants.
+	Exporter string
+	// Endpoint is the host:port of the collector, for ExporterOTLP.
+	Endpoint string
+	// Insecure sends spans to the collector in plain text.
+	Insecure bool
+	// File is where ExporterStdout writes, instead of standard output.
+	File string
+	// SampleRatio is the share of the traces started by the process that are
+	// sampled. Traces continued from a caller follow its decision.
+	SampleRatio float64
+}
+
+// errorHandler logs the errors of exporting spans. This version of the SDK
+// also reports nil errors, which are dropped.
+type errorHandler struct{}
+
+func (errorHandler) Handle(err error) {
+	if err != nil {
+		zap.L().Warn("Tracing error", zap.Error(err))
+	}
+}
+
+// ValidExporter is whether name is one of the Exporter constants.
+func ValidExporter(name string) bool {
+	switch name {
+	case ExporterNone, ExporterOTLP, ExporterStdout:
+		return true
+	}
+
+	return false
+}
+
+// Setup installs the tracer provider used by otel.Tracer, and the W3C trace
+// context propagators. It returns a function flushing the spans not yet
+// exported, to be called on shutdown.
+func Setup(ctx context.Context, opts Options) (func(ctx context.Context) error, error) {
+	otel.SetErrorHandler(errorHandler{})
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var closer io.Closer
+
+	switch opts.Exporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		driverOpts := []otlpgrpc.Option{otlpgrpc.WithEndpoint(opts.Endpoint)}
+		if opts.Insecure {
+			driverOpts = append(driverOpts, otlpgrpc.WithInsecure())
+		}
+
+		exp, err := otlp.NewExporter(ctx, otlpgrpc.NewDriver(driverOpts...))
+		if err != nil {
+			return nil, fmt.Errorf("unable to create the OTLP exporter: %w", err)
+		}
+		exporter = exp
+	case ExporterStdout:
+		var w io.Writer = os.Stdout
+		if opts.File != "" {
+			f, err := os.OpenFile(opts.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+			if err != nil {
+				return nil, fmt.Errorf("unable to open %s: %w", opts.File, err)
+			}
+			w, closer = f, f
+		}
+
+		exp, err := stdout.NewExporter(stdout.WithWriter(w), stdout.WithoutMetricExport())
+		if err != nil {
+			return nil, fmt.Errorf("unable to create the stdout exporter: %w", err)
+		}
+		exporter = exp
+	default:
+		return nil, fmt.Errorf("unknown exporter %q", opts.Exporter)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(sdkresource.NewWithAttributes(semconv.ServiceNameKey.String(opts.ServiceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			closeErr := closer.Close()
+			if err == nil {
+				err = closeErr
+			}
+		}
+		return err
+	}, nil
+}
@@ -0,0 +1,77 @@
+// Command healthcheck asks the User service whether it is serving, through the
+// grpc.health.v1 service, and exits with status 1 if it is not. It is the
+// healthcheck of the User service container:
+//
+//	healthcheck -address localhost:50051
+//
+// It dials over mutual TLS with the files of the service itself, from the same
+// environment variables, unless USER_SERVICE_INSECURE or -insecure is set.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/allen-woods/the-supertask/pkg/mtls"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func main() {
+	insecureDefault, _ := strconv.ParseBool(os.Getenv("USER_SERVICE_INSECURE"))
+
+	address := flag.String("address", "localhost:50051", "host:port of the User service")
+	service := flag.String("service", "", "service to check; empty checks the whole server")
+	timeout := flag.Duration("timeout", 3*time.Second, "how long to wait for an answer")
+	insecure := flag.Bool("insecure", insecureDefault, "dial without TLS")
+	cert := flag.String("cert", os.Getenv("USER_SERVICE_TLS_CERT"), "PEM client certificate")
+	key := flag.String("key", os.Getenv("USER_SERVICE_TLS_KEY"), "PEM private key of the client certificate")
+	ca := flag.String("ca", os.Getenv("USER_SERVICE_TLS_CLIENT_CA"), "PEM certificates of the authorities signing the server certificate")
+	serverName := flag.String("server-name", "", "name expected in the server certificate; defaults to the host of -address")
+	flag.Parse()
+
+	creds := grpc.WithInsecure()
+
+	if !*insecure {
+		certs, err := mtls.Load(mtls.Files{Cert: *cert, Key: *key, CA: *ca})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Unable to load the TLS certificates: %v\n", err)
+			os.Exit(1)
+		}
+
+		name := *serverName
+		if name == "" {
+			name, _, _ = net.SplitHostPort(*address)
+		}
+
+		creds = grpc.WithTransportCredentials(credentials.NewTLS(certs.ClientConfig(name)))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, *address, creds, grpc.WithBlock())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to connect to %s: %v\n", *address, err)
+		os.Exit(1)
+	}
+	defer conn.Close()
+
+	res, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: *service})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to check health: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(res.GetStatus())
+
+	if res.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		os.Exit(1)
+	}
+}
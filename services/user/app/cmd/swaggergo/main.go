@@ -0,0 +1,61 @@
+// Command swaggergo turns the OpenAPI spec generated from the proto files
+// into a Go constant, so that the gateway of the API serves the spec that
+// matches its routes. It is run by go generate in the proto directory:
+//
+//	swaggergo -in user.swagger.json -out user.swagger.go -package userpb
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	in := flag.String("in", "", "OpenAPI spec, as JSON")
+	out := flag.String("out", "", "Go file to write")
+	pkg := flag.String("package", "", "package of the Go file")
+	name := flag.String("const", "OpenAPIv2", "name of the constant")
+	flag.Parse()
+
+	if *in == "" || *out == "" || *pkg == "" {
+		flag.Usage()
+		log.Fatal("-in, -out and -package are required")
+	}
+
+	spec, err := ioutil.ReadFile(*in)
+	if err != nil {
+		log.Fatalf("Could not read the spec: %v", err)
+	}
+
+	// Raw strings cannot hold backquotes, which JSON allows in strings.
+	literal := "`" + string(spec) + "`"
+	if bytes.ContainsRune(spec, '`') {
+		literal = strconv.Quote(string(spec))
+	}
+
+	src := fmt.Sprintf(`// Code generated by swaggergo from %s. DO NOT EDIT.
+
+package %s
+
+// %s is the OpenAPI v2 spec of the HTTP routes of UserCRUD, as
+// served by the REST gateway of the API.
+const %s = %s
+`, filepath.Base(*in), *pkg, *name, *name, strings.TrimRight(literal, "\n"))
+
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		log.Fatalf("Could not format the Go file: %v", err)
+	}
+
+	err = ioutil.WriteFile(*out, formatted, 0644)
+	if err != nil {
+		log.Fatalf("Could not write the Go file: %v", err)
+	}
+}
@@ -0,0 +1,275 @@
+// Package config holds the settings of the User service. They are read, by
+// increasing precedence, from their defaults, a YAML file, environment
+// variables and command line flags, the same way as those of the API; see
+// config.example.yaml for every setting.
+package config
+
+import (
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/allen-woods/the-supertask/pkg/lifecycle"
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	"github.com/allen-woods/the-supertask/pkg/mtls"
+	"github.com/allen-woods/the-supertask/pkg/settings"
+	"github.com/allen-woods/the-supertask/pkg/tracing"
+)
+
+// FileEnv is the environment variable naming the configuration file, unless
+// given by --config.
+const FileEnv = "USER_SERVICE_CONFIG"
+
+// Secret is a setting that is never printed, given literally or as a "file:"
+// or "env:" reference.
+type Secret = settings.Secret
+
+// Config holds every setting of the User service.
+type Config struct {
+	GRPC     GRPCConfig     `yaml:"grpc"`
+	TLS      TLSConfig      `yaml:"tls"`
+	Auth     AuthConfig     `yaml:"auth"`
+	Metrics  MetricsConfig  `yaml:"metrics"`
+	Mongo    MongoConfig    `yaml:"mongo"`
+	Accounts AccountsConfig `yaml:"accounts"`
+	Audit    AuditConfig    `yaml:"audit"`
+	Debug    DebugConfig    `yaml:"debug"`
+	Logging  LoggingConfig  `yaml:"logging"`
+	Tracing  TracingConfig  `yaml:"tracing"`
+	Shutdown ShutdownConfig `yaml:"shutdown"`
+
+	loader *settings.Loader
+}
+
+// GRPCConfig is where the service is served.
+type GRPCConfig struct {
+	// Host is the interface to listen on; empty listens on all of them.
+	Host string `yaml:"host" env:"USER_SERVICE_HOST" flag:"host" usage:"interface to serve gRPC on"`
+	Port int    `yaml:"port" env:"USER_SERVICE_PORT" flag:"port" usage:"port to serve gRPC on"`
+	// DefaultTimeout caps the deadline of unary methods without a timeout
+	// of their own in the proto files; 0 leaves it uncapped.
+	DefaultTimeout time.Duration `yaml:"defaultTimeout" env:"USER_SERVICE_DEFAULT_TIMEOUT" usage:"longest deadline of unary methods without a timeout of their own, 0 for none"`
+}
+
+// TLSConfig secures gRPC with mutual TLS. The files are reloaded when they
+// change.
+type TLSConfig struct {
+	Cert     string `yaml:"cert" env:"USER_SERVICE_TLS_CERT" usage:"PEM certificate the service presents"`
+	Key      string `yaml:"key" env:"USER_SERVICE_TLS_KEY" usage:"PEM private key of the certificate"`
+	ClientCA string `yaml:"clientCA" env:"USER_SERVICE_TLS_CLIENT_CA" usage:"PEM certificates of the authorities signing client certificates"`
+	// ServerCA is only used by the debug subcommand, which calls the
+	// service with its own certificate and must trust the one it serves.
+	ServerCA string `yaml:"serverCA" env:"USER_SERVICE_TLS_SERVER_CA" usage:"PEM certificates of the authorities signing the certificate of the service, for the debug subcommand"`
+	// Insecure serves gRPC without TLS, for local development only.
+	Insecure bool `yaml:"insecure" env:"USER_SERVICE_INSECURE" flag:"insecure" usage:"serve gRPC without TLS, for development only"`
+}
+
+// AuthConfig says how calling services are authenticated.
+type AuthConfig struct {
+	// APIToken is the service token the API presents; empty refuses every
+	// token, leaving only methods open to any caller.
+	APIToken Secret `yaml:"apiToken" env:"USER_SERVICE_TOKEN" usage:"service token of the API, or a file: or env: reference to it"`
+	// APIName is the DNS name in the client certificate of the API.
+	APIName string `yaml:"apiName" env:"USER_SERVICE_API_NAME" usage:"DNS name of the API in its client certificate"`
+}
+
+// MetricsConfig is where the Prometheus metrics are served, apart from gRPC.
+type MetricsConfig struct {
+	// Port 0 disables the metrics server.
+	Port int `yaml:"port" env:"USER_SERVICE_METRICS_PORT" flag:"metrics-port" usage:"port to serve Prometheus metrics on, 0 to disable"`
+}
+
+// MongoConfig is the MongoDB holding the accounts and audit log.
+type MongoConfig struct {
+	// URI may hold credentials, so it is a secret.
+	URI      Secret `yaml:"uri" env:"MONGO_URI" usage:"MongoDB connection string, or a file: or env: reference to it"`
+	Database string `yaml:"database" env:"MONGO_DATABASE" flag:"mongo-database" usage:"MongoDB database"`
+}
+
+// AccountsConfig drives the lifecycle of user accounts.
+type AccountsConfig struct {
+	DeletionGracePeriod    time.Duration `yaml:"deletionGracePeriod" env:"USER_DELETION_GRACE_PERIOD" usage:"how long an account stays pending deletion before it is purged"`
+	UserNameRedirectPeriod time.Duration `yaml:"userNameRedirectPeriod" env:"USER_NAME_REDIRECT_PERIOD" usage:"how long a former user name still finds the account, and is kept from others"`
+}
+
+// AuditConfig drives the security audit log.
+type AuditConfig struct {
+	Retention time.Duration `yaml:"retention" env:"AUDIT_EVENT_RETENTION" usage:"how long audit events are kept"`
+}
+
+// DebugConfig exposes the internals of the service to debugging tools, such
+// as grpcurl.
+type DebugConfig struct {
+	// Reflection lets clients list the services and their messages.
+	Reflection bool `yaml:"reflection" env:"USER_SERVICE_REFLECTION" flag:"reflection" usage:"register gRPC server reflection"`
+	// Channelz reports the state of the connections of the server.
+	Channelz bool `yaml:"channelz" env:"USER_SERVICE_CHANNELZ" flag:"channelz" usage:"register the gRPC channelz service"`
+}
+
+// LoggingConfig drives the structured logs of the service.
+type LoggingConfig struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL" flag:"log-level" usage:"lowest level logged: debug, info, warn or error"`
+	Format string `yaml:"format" env:"LOG_FORMAT" usage:"json, or console for human-readable lines"`
+}
+
+// TracingConfig says where the OpenTelemetry spans of the service are exported.
+type TracingConfig struct {
+	Exporter string `yaml:"exporter" env:"TRACING_EXPORTER" flag:"tracing-exporter" usage:"where spans are exported: none, otlp or stdout"`
+	Endpoint string `yaml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT" usage:"host:port of the OpenTelemetry collector, for the otlp exporter"`
+	Insecure bool   `yaml:"insecure" env:"TRACING_INSECURE" usage:"send spans to the collector without TLS"`
+	// File is where the stdout exporter writes, for local development; empty
+	// writes to standard output.
+	File        string  `yaml:"file" env:"TRACING_FILE" usage:"file the stdout exporter writes to"`
+	SampleRatio float64 `yaml:"sampleRatio" env:"TRACING_SAMPLE_RATIO" usage:"share of new traces that are sampled, from 0 to 1"`
+}
+
+// ShutdownConfig drives the graceful shutdown of the service.
+type ShutdownConfig struct {
+	DrainTimeout  time.Duration `yaml:"drainTimeout" env:"SHUTDOWN_DRAIN_TIMEOUT" usage:"how long in-flight RPCs are given to complete on shutdown"`
+	NotReadyDelay time.Duration `yaml:"notReadyDelay" env:"SHUTDOWN_NOT_READY_DELAY" usage:"how long the service keeps serving after reporting not ready on shutdown"`
+	CloseTimeout  time.Duration `yaml:"closeTimeout" env:"SHUTDOWN_CLOSE_TIMEOUT" usage:"how long each client is given to close on shutdown, after draining"`
+}
+
+// Default returns the settings used when nothing else is given.
+func Default() *Config {
+	return &Config{
+		GRPC: GRPCConfig{
+			Port:           50051,
+			DefaultTimeout: 30 * time.Second,
+		},
+		Auth: AuthConfig{
+			APIName: "api",
+		},
+		Metrics: MetricsConfig{
+			Port: 9090,
+		},
+		Mongo: MongoConfig{
+			URI:      settings.NewSecret("mongodb://0.0.0.0:27017"),
+			Database: "theSupertask",
+		},
+		Accounts: AccountsConfig{
+			DeletionGracePeriod:    30 * 24 * time.Hour,
+			UserNameRedirectPeriod: 90 * 24 * time.Hour,
+		},
+		Audit: AuditConfig{
+			Retention: 365 * 24 * time.Hour,
+		},
+		Logging: LoggingConfig{
+			Level:  "info",
+			Format: logging.FormatJSON,
+		},
+		Tracing: TracingConfig{
+			Exporter:    tracing.ExporterNone,
+			Endpoint:    "localhost:4317",
+			SampleRatio: 1,
+		},
+		Shutdown: ShutdownConfig{
+			DrainTimeout: lifecycle.DefaultDrainTimeout,
+			CloseTimeout: lifecycle.DefaultCloseTimeout,
+		},
+	}
+}
+
+// Load reads the settings from args, the environment and the configuration
+// file, and validates them.
+func Load(args []string) (*Config, error) {
+	cfg := Default()
+	cfg.loader = &settings.Loader{Name: "user-service", FileEnv: FileEnv}
+
+	err := cfg.loader.Load(cfg, args)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cfg.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) validate() error {
+	check := c.loader.Check
+
+	check("grpc.port", c.GRPC.Port > 0 && c.GRPC.Port < 65536, "must be between 1 and 65535, not %d", c.GRPC.Port)
+	check("grpc.defaultTimeout", c.GRPC.DefaultTimeout >= 0, "must not be negative")
+	if !c.TLS.Insecure {
+		check("tls.cert", c.TLS.Cert != "", "must be set, unless tls.insecure is set for development")
+		check("tls.key", c.TLS.Key != "", "must be set, unless tls.insecure is set for development")
+		check("tls.clientCA", c.TLS.ClientCA != "", "must be set, unless tls.insecure is set for development")
+	}
+
+	check("metrics.port", c.Metrics.Port >= 0 && c.Metrics.Port < 65536, "must be between 0 and 65535, not %d", c.Metrics.Port)
+	check("metrics.port", c.Metrics.Port != c.GRPC.Port, "must differ from grpc.port")
+
+	uri := c.Mongo.URI.Value()
+	check("mongo.uri", strings.HasPrefix(uri, "mongodb://") || strings.HasPrefix(uri, "mongodb+srv://"), "must start with mongodb:// or mongodb+srv://")
+	check("mongo.database", c.Mongo.Database != "", "must be set")
+
+	check("accounts.deletionGracePeriod", c.Accounts.DeletionGracePeriod > 0, "must be positive")
+	check("accounts.userNameRedirectPeriod", c.Accounts.UserNameRedirectPeriod >= 0, "must not be negative")
+
+	// The retention is applied by a TTL index, which counts in seconds.
+	check("audit.retention", c.Audit.Retention >= time.Second, "must be at least 1s")
+
+	check("logging.level", logging.ValidLevel(c.Logging.Level), "must be debug, info, warn or error, not %q", c.Logging.Level)
+	check("logging.format", logging.ValidFormat(c.Logging.Format), "must be json or console, not %q", c.Logging.Format)
+
+	check("tracing.exporter", tracing.ValidExporter(c.Tracing.Exporter), "must be none, otlp or stdout, not %q", c.Tracing.Exporter)
+	if c.Tracing.Exporter == tracing.ExporterOTLP {
+		_, _, err := net.SplitHostPort(c.Tracing.Endpoint)
+		check("tracing.endpoint", err == nil, "must be host:port, not %q", c.Tracing.Endpoint)
+	}
+	check("tracing.sampleRatio", c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "must be between 0 and 1, not %g", c.Tracing.SampleRatio)
+
+	check("shutdown.drainTimeout", c.Shutdown.DrainTimeout > 0, "must be positive")
+	check("shutdown.notReadyDelay", c.Shutdown.NotReadyDelay >= 0 && c.Shutdown.NotReadyDelay < c.Shutdown.DrainTimeout, "must be less than shutdown.drainTimeout")
+	check("shutdown.closeTimeout", c.Shutdown.CloseTimeout > 0, "must be positive")
+
+	return c.loader.Err()
+}
+
+// TLSFiles are the files gRPC is secured with, unless insecure.
+func (c *Config) TLSFiles() mtls.Files {
+	return mtls.Files{
+		Cert: c.TLS.Cert,
+		Key:  c.TLS.Key,
+		CA:   c.TLS.ClientCA,
+	}
+}
+
+// TracingOptions says how the service exports its spans.
+func (c *Config) TracingOptions() tracing.Options {
+	return tracing.Options{
+		ServiceName: "user-service",
+		Exporter:    c.Tracing.Exporter,
+		Endpoint:    c.Tracing.Endpoint,
+		Insecure:    c.Tracing.Insecure,
+		File:        c.Tracing.File,
+		SampleRatio: c.Tracing.SampleRatio,
+	}
+}
+
+// PrintOnly is whether --print-config was given, in which case the service
+// should print its configuration and exit rather than start.
+func (c *Config) PrintOnly() bool {
+	return c.loader != nil && c.loader.PrintOnly()
+}
+
+// Print writes the settings to w as YAML, with secrets redacted.
+func (c *Config) Print(w io.Writer) error {
+	return settings.Print(w, c)
+}
+
+// MetricsAddress is the host:port the metrics are served on, if enabled.
+func (c *Config) MetricsAddress() string {
+	return net.JoinHostPort(c.GRPC.Host, strconv.Itoa(c.Metrics.Port))
+}
+
+// ListenAddress is the host:port the service listens on.
+func (c *Config) ListenAddress() string {
+	return net.JoinHostPort(c.GRPC.Host, strconv.Itoa(c.GRPC.Port))
+}
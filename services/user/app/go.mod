@@ -3,6 +3,22 @@ module github.com/allen-woods/the-supertask/services/user
 go 1.13
 
 require (
-	go.mongodb.org/mongo-driver v1.4.1 // indirect
-	google.golang.org/grpc v1.32.0 // indirect
+	github.com/allen-woods/the-supertask/pkg v0.0.0-00010101000000-000000000000
+	github.com/golang/protobuf v1.5.2
+	github.com/grpc-ecosystem/go-grpc-prometheus v1.2.0
+	github.com/grpc-ecosystem/grpc-gateway v1.16.0
+	github.com/prometheus/client_golang v1.7.1
+	go.mongodb.org/mongo-driver v1.4.1
+	go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc v0.20.0
+	go.opentelemetry.io/otel v0.20.0
+	go.opentelemetry.io/otel/trace v0.20.0
+	go.uber.org/zap v1.16.0
+	golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9
+	golang.org/x/text v0.3.3
+	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013
+	google.golang.org/grpc v1.37.0
+	google.golang.org/protobuf v1.26.0
+	gopkg.in/yaml.v2 v2.2.8
 )
+
+replace github.com/allen-woods/the-supertask/pkg => ../../../pkg
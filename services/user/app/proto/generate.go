@@ -0,0 +1,5 @@
+package userpb
+
+// The protoc plugins write user.swagger.json along with the Go code of the
+// proto files; the gateway of the API serves it from user.swagger.go.
+//go:generate go run ../cmd/swaggergo -in user.swagger.json -out user.swagger.go -package userpb
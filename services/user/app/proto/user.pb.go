@@ -8,8 +8,11 @@ package userpb
 
 import (
 	proto "github.com/golang/protobuf/proto"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -25,23 +28,392 @@ const (
 // of the legacy proto package is being used.
 const _ = proto.ProtoPackageIsVersion4
 
-// Create a message for new users.
+// The lifecycle of an account. New accounts start out active for as long as
+// email addresses are not verified.
+type Status int32
+
+const (
+	Status_STATUS_UNSPECIFIED          Status = 0
+	Status_STATUS_PENDING_VERIFICATION Status = 1
+	Status_STATUS_ACTIVE               Status = 2
+	Status_STATUS_SUSPENDED            Status = 3
+	Status_STATUS_PENDING_DELETION     Status = 4
+	Status_STATUS_DELETED              Status = 5
+)
+
+// Enum value maps for Status.
+var (
+	Status_name = map[int32]string{
+		0: "STATUS_UNSPECIFIED",
+		1: "STATUS_PENDING_VERIFICATION",
+		2: "STATUS_ACTIVE",
+		3: "STATUS_SUSPENDED",
+		4: "STATUS_PENDING_DELETION",
+		5: "STATUS_DELETED",
+	}
+	Status_value = map[string]int32{
+		"STATUS_UNSPECIFIED":          0,
+		"STATUS_PENDING_VERIFICATION": 1,
+		"STATUS_ACTIVE":               2,
+		"STATUS_SUSPENDED":            3,
+		"STATUS_PENDING_DELETION":     4,
+		"STATUS_DELETED":              5,
+	}
+)
+
+func (x Status) Enum() *Status {
+	p := new(Status)
+	*p = x
+	return p
+}
+
+func (x Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_proto_user_proto_enumTypes[0].Descriptor()
+}
+
+func (Status) Type() protoreflect.EnumType {
+	return &file_user_proto_user_proto_enumTypes[0]
+}
+
+func (x Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Status.Descriptor instead.
+func (Status) EnumDescriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{0}
+}
+
+// The role of an account. Roles are only ever granted in the database.
+type Role int32
+
+const (
+	Role_ROLE_UNSPECIFIED Role = 0
+	Role_ROLE_USER        Role = 1
+	Role_ROLE_ADMIN       Role = 2
+)
+
+// Enum value maps for Role.
+var (
+	Role_name = map[int32]string{
+		0: "ROLE_UNSPECIFIED",
+		1: "ROLE_USER",
+		2: "ROLE_ADMIN",
+	}
+	Role_value = map[string]int32{
+		"ROLE_UNSPECIFIED": 0,
+		"ROLE_USER":        1,
+		"ROLE_ADMIN":       2,
+	}
+)
+
+func (x Role) Enum() *Role {
+	p := new(Role)
+	*p = x
+	return p
+}
+
+func (x Role) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Role) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_proto_user_proto_enumTypes[1].Descriptor()
+}
+
+func (Role) Type() protoreflect.EnumType {
+	return &file_user_proto_user_proto_enumTypes[1]
+}
+
+func (x Role) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Role.Descriptor instead.
+func (Role) EnumDescriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{1}
+}
+
+// Whether an audited action went through.
+type AuditOutcome int32
+
+const (
+	AuditOutcome_AUDIT_OUTCOME_UNSPECIFIED AuditOutcome = 0
+	AuditOutcome_AUDIT_OUTCOME_SUCCESS     AuditOutcome = 1
+	AuditOutcome_AUDIT_OUTCOME_FAILURE     AuditOutcome = 2
+)
+
+// Enum value maps for AuditOutcome.
+var (
+	AuditOutcome_name = map[int32]string{
+		0: "AUDIT_OUTCOME_UNSPECIFIED",
+		1: "AUDIT_OUTCOME_SUCCESS",
+		2: "AUDIT_OUTCOME_FAILURE",
+	}
+	AuditOutcome_value = map[string]int32{
+		"AUDIT_OUTCOME_UNSPECIFIED": 0,
+		"AUDIT_OUTCOME_SUCCESS":     1,
+		"AUDIT_OUTCOME_FAILURE":     2,
+	}
+)
+
+func (x AuditOutcome) Enum() *AuditOutcome {
+	p := new(AuditOutcome)
+	*p = x
+	return p
+}
+
+func (x AuditOutcome) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AuditOutcome) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_proto_user_proto_enumTypes[2].Descriptor()
+}
+
+func (AuditOutcome) Type() protoreflect.EnumType {
+	return &file_user_proto_user_proto_enumTypes[2]
+}
+
+func (x AuditOutcome) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AuditOutcome.Descriptor instead.
+func (AuditOutcome) EnumDescriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{2}
+}
+
+// The theme of the app. The system theme follows the settings of the device.
+type Theme int32
+
+const (
+	Theme_THEME_UNSPECIFIED Theme = 0
+	Theme_THEME_SYSTEM      Theme = 1
+	Theme_THEME_LIGHT       Theme = 2
+	Theme_THEME_DARK        Theme = 3
+)
+
+// Enum value maps for Theme.
+var (
+	Theme_name = map[int32]string{
+		0: "THEME_UNSPECIFIED",
+		1: "THEME_SYSTEM",
+		2: "THEME_LIGHT",
+		3: "THEME_DARK",
+	}
+	Theme_value = map[string]int32{
+		"THEME_UNSPECIFIED": 0,
+		"THEME_SYSTEM":      1,
+		"THEME_LIGHT":       2,
+		"THEME_DARK":        3,
+	}
+)
+
+func (x Theme) Enum() *Theme {
+	p := new(Theme)
+	*p = x
+	return p
+}
+
+func (x Theme) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Theme) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_proto_user_proto_enumTypes[3].Descriptor()
+}
+
+func (Theme) Type() protoreflect.EnumType {
+	return &file_user_proto_user_proto_enumTypes[3]
+}
+
+func (x Theme) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Theme.Descriptor instead.
+func (Theme) EnumDescriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{3}
+}
+
+// The view tasks are shown in.
+type TaskView int32
+
+const (
+	TaskView_TASK_VIEW_UNSPECIFIED TaskView = 0
+	TaskView_TASK_VIEW_LIST        TaskView = 1
+	TaskView_TASK_VIEW_BOARD       TaskView = 2
+	TaskView_TASK_VIEW_CALENDAR    TaskView = 3
+)
+
+// Enum value maps for TaskView.
+var (
+	TaskView_name = map[int32]string{
+		0: "TASK_VIEW_UNSPECIFIED",
+		1: "TASK_VIEW_LIST",
+		2: "TASK_VIEW_BOARD",
+		3: "TASK_VIEW_CALENDAR",
+	}
+	TaskView_value = map[string]int32{
+		"TASK_VIEW_UNSPECIFIED": 0,
+		"TASK_VIEW_LIST":        1,
+		"TASK_VIEW_BOARD":       2,
+		"TASK_VIEW_CALENDAR":    3,
+	}
+)
+
+func (x TaskView) Enum() *TaskView {
+	p := new(TaskView)
+	*p = x
+	return p
+}
+
+func (x TaskView) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TaskView) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_proto_user_proto_enumTypes[4].Descriptor()
+}
+
+func (TaskView) Type() protoreflect.EnumType {
+	return &file_user_proto_user_proto_enumTypes[4]
+}
+
+func (x TaskView) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TaskView.Descriptor instead.
+func (TaskView) EnumDescriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{4}
+}
+
+// The span of the calendar.
+type CalendarView int32
+
+const (
+	CalendarView_CALENDAR_VIEW_UNSPECIFIED CalendarView = 0
+	CalendarView_CALENDAR_VIEW_DAY         CalendarView = 1
+	CalendarView_CALENDAR_VIEW_WEEK        CalendarView = 2
+	CalendarView_CALENDAR_VIEW_MONTH       CalendarView = 3
+)
+
+// Enum value maps for CalendarView.
+var (
+	CalendarView_name = map[int32]string{
+		0: "CALENDAR_VIEW_UNSPECIFIED",
+		1: "CALENDAR_VIEW_DAY",
+		2: "CALENDAR_VIEW_WEEK",
+		3: "CALENDAR_VIEW_MONTH",
+	}
+	CalendarView_value = map[string]int32{
+		"CALENDAR_VIEW_UNSPECIFIED": 0,
+		"CALENDAR_VIEW_DAY":         1,
+		"CALENDAR_VIEW_WEEK":        2,
+		"CALENDAR_VIEW_MONTH":       3,
+	}
+)
+
+func (x CalendarView) Enum() *CalendarView {
+	p := new(CalendarView)
+	*p = x
+	return p
+}
+
+func (x CalendarView) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CalendarView) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_proto_user_proto_enumTypes[5].Descriptor()
+}
+
+func (CalendarView) Type() protoreflect.EnumType {
+	return &file_user_proto_user_proto_enumTypes[5]
+}
+
+func (x CalendarView) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CalendarView.Descriptor instead.
+func (CalendarView) EnumDescriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{5}
+}
+
+// Who may see the profile of a user, besides the user and admins.
+type ProfileVisibility int32
+
+const (
+	ProfileVisibility_PROFILE_VISIBILITY_UNSPECIFIED ProfileVisibility = 0
+	ProfileVisibility_PROFILE_VISIBILITY_PUBLIC      ProfileVisibility = 1
+	ProfileVisibility_PROFILE_VISIBILITY_MEMBERS     ProfileVisibility = 2
+	ProfileVisibility_PROFILE_VISIBILITY_HIDDEN      ProfileVisibility = 3
+)
+
+// Enum value maps for ProfileVisibility.
+var (
+	ProfileVisibility_name = map[int32]string{
+		0: "PROFILE_VISIBILITY_UNSPECIFIED",
+		1: "PROFILE_VISIBILITY_PUBLIC",
+		2: "PROFILE_VISIBILITY_MEMBERS",
+		3: "PROFILE_VISIBILITY_HIDDEN",
+	}
+	ProfileVisibility_value = map[string]int32{
+		"PROFILE_VISIBILITY_UNSPECIFIED": 0,
+		"PROFILE_VISIBILITY_PUBLIC":      1,
+		"PROFILE_VISIBILITY_MEMBERS":     2,
+		"PROFILE_VISIBILITY_HIDDEN":      3,
+	}
+)
+
+func (x ProfileVisibility) Enum() *ProfileVisibility {
+	p := new(ProfileVisibility)
+	*p = x
+	return p
+}
+
+func (x ProfileVisibility) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProfileVisibility) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_proto_user_proto_enumTypes[6].Descriptor()
+}
+
+func (ProfileVisibility) Type() protoreflect.EnumType {
+	return &file_user_proto_user_proto_enumTypes[6]
+}
+
+func (x ProfileVisibility) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProfileVisibility.Descriptor instead.
+func (ProfileVisibility) EnumDescriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{6}
+}
+
+// Create a message for a website of a user.
 // IMPORTANT:
-// - No "id" field because the user is new.
-// - Has hashed and salted "password" field.
-type NewUser struct {
+// - "url" is an absolute http or https URL.
+type ProfileLink struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Email    string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
-	Name     string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	UserName string `protobuf:"bytes,3,opt,name=userName,proto3" json:"userName,omitempty"`
-	Password string `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
+	Label string `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Url   string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
 }
 
-func (x *NewUser) Reset() {
-	*x = NewUser{}
+func (x *ProfileLink) Reset() {
+	*x = ProfileLink{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_user_proto_user_proto_msgTypes[0]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -49,13 +421,13 @@ func (x *NewUser) Reset() {
 	}
 }
 
-func (x *NewUser) String() string {
+func (x *ProfileLink) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NewUser) ProtoMessage() {}
+func (*ProfileLink) ProtoMessage() {}
 
-func (x *NewUser) ProtoReflect() protoreflect.Message {
+func (x *ProfileLink) ProtoReflect() protoreflect.Message {
 	mi := &file_user_proto_user_proto_msgTypes[0]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -67,56 +439,48 @@ func (x *NewUser) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NewUser.ProtoReflect.Descriptor instead.
-func (*NewUser) Descriptor() ([]byte, []int) {
+// Deprecated: Use ProfileLink.ProtoReflect.Descriptor instead.
+func (*ProfileLink) Descriptor() ([]byte, []int) {
 	return file_user_proto_user_proto_rawDescGZIP(), []int{0}
 }
 
-func (x *NewUser) GetEmail() string {
-	if x != nil {
-		return x.Email
-	}
-	return ""
-}
-
-func (x *NewUser) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
-}
-
-func (x *NewUser) GetUserName() string {
+func (x *ProfileLink) GetLabel() string {
 	if x != nil {
-		return x.UserName
+		return x.Label
 	}
 	return ""
 }
 
-func (x *NewUser) GetPassword() string {
+func (x *ProfileLink) GetUrl() string {
 	if x != nil {
-		return x.Password
+		return x.Url
 	}
 	return ""
 }
 
-// Create a message for a registered user.
+// Create a message for what a user shows others about themself.
 // IMPORTANT:
-// - Has "id" field because the user is registered.
-// - No "password" field because we should never return it.
-type User struct {
+// - Every field is optional.
+// - "timezone" is an IANA time zone, such as "Europe/Paris".
+// - "locale" is a BCP 47 language tag, such as "en-US".
+// - "version" counts the times the profile was changed, from 0 for none.
+type Profile struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Email    string `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
-	Name     string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
-	UserName string `protobuf:"bytes,4,opt,name=userName,proto3" json:"userName,omitempty"`
+	DisplayName string         `protobuf:"bytes,1,opt,name=displayName,proto3" json:"displayName,omitempty"`
+	Bio         string         `protobuf:"bytes,2,opt,name=bio,proto3" json:"bio,omitempty"`
+	Links       []*ProfileLink `protobuf:"bytes,3,rep,name=links,proto3" json:"links,omitempty"`
+	Location    string         `protobuf:"bytes,4,opt,name=location,proto3" json:"location,omitempty"`
+	Timezone    string         `protobuf:"bytes,5,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Locale      string         `protobuf:"bytes,6,opt,name=locale,proto3" json:"locale,omitempty"`
+	Pronouns    string         `protobuf:"bytes,7,opt,name=pronouns,proto3" json:"pronouns,omitempty"`
+	Version     int64          `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"`
 }
 
-func (x *User) Reset() {
-	*x = User{}
+func (x *Profile) Reset() {
+	*x = Profile{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_user_proto_user_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -124,13 +488,13 @@ func (x *User) Reset() {
 	}
 }
 
-func (x *User) String() string {
+func (x *Profile) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*User) ProtoMessage() {}
+func (*Profile) ProtoMessage() {}
 
-func (x *User) ProtoReflect() protoreflect.Message {
+func (x *Profile) ProtoReflect() protoreflect.Message {
 	mi := &file_user_proto_user_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -142,57 +506,80 @@ func (x *User) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use User.ProtoReflect.Descriptor instead.
-func (*User) Descriptor() ([]byte, []int) {
+// Deprecated: Use Profile.ProtoReflect.Descriptor instead.
+func (*Profile) Descriptor() ([]byte, []int) {
 	return file_user_proto_user_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *User) GetId() string {
+func (x *Profile) GetDisplayName() string {
 	if x != nil {
-		return x.Id
+		return x.DisplayName
 	}
 	return ""
 }
 
-func (x *User) GetEmail() string {
+func (x *Profile) GetBio() string {
 	if x != nil {
-		return x.Email
+		return x.Bio
 	}
 	return ""
 }
 
-func (x *User) GetName() string {
+func (x *Profile) GetLinks() []*ProfileLink {
 	if x != nil {
-		return x.Name
+		return x.Links
+	}
+	return nil
+}
+
+func (x *Profile) GetLocation() string {
+	if x != nil {
+		return x.Location
 	}
 	return ""
 }
 
-func (x *User) GetUserName() string {
+func (x *Profile) GetTimezone() string {
 	if x != nil {
-		return x.UserName
+		return x.Timezone
 	}
 	return ""
 }
 
-// Create a message for updating a registered user.
-// IMPORTANT:
-// - Has "id" field because the user is registered.
-// - Has "password" field because this user is "me".
-type EditUser struct {
+func (x *Profile) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *Profile) GetPronouns() string {
+	if x != nil {
+		return x.Pronouns
+	}
+	return ""
+}
+
+func (x *Profile) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// The emails a user wants to receive.
+type NotificationPreferences struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Email    string `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
-	Name     string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
-	UserName string `protobuf:"bytes,4,opt,name=userName,proto3" json:"userName,omitempty"`
-	Password string `protobuf:"bytes,5,opt,name=password,proto3" json:"password,omitempty"`
+	SecurityAlerts bool `protobuf:"varint,1,opt,name=securityAlerts,proto3" json:"securityAlerts,omitempty"`
+	TaskReminders  bool `protobuf:"varint,2,opt,name=taskReminders,proto3" json:"taskReminders,omitempty"`
+	ProductUpdates bool `protobuf:"varint,3,opt,name=productUpdates,proto3" json:"productUpdates,omitempty"`
 }
 
-func (x *EditUser) Reset() {
-	*x = EditUser{}
+func (x *NotificationPreferences) Reset() {
+	*x = NotificationPreferences{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_user_proto_user_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -200,13 +587,13 @@ func (x *EditUser) Reset() {
 	}
 }
 
-func (x *EditUser) String() string {
+func (x *NotificationPreferences) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EditUser) ProtoMessage() {}
+func (*NotificationPreferences) ProtoMessage() {}
 
-func (x *EditUser) ProtoReflect() protoreflect.Message {
+func (x *NotificationPreferences) ProtoReflect() protoreflect.Message {
 	mi := &file_user_proto_user_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -218,57 +605,44 @@ func (x *EditUser) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EditUser.ProtoReflect.Descriptor instead.
-func (*EditUser) Descriptor() ([]byte, []int) {
+// Deprecated: Use NotificationPreferences.ProtoReflect.Descriptor instead.
+func (*NotificationPreferences) Descriptor() ([]byte, []int) {
 	return file_user_proto_user_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *EditUser) GetId() string {
+func (x *NotificationPreferences) GetSecurityAlerts() bool {
 	if x != nil {
-		return x.Id
+		return x.SecurityAlerts
 	}
-	return ""
+	return false
 }
 
-func (x *EditUser) GetEmail() string {
+func (x *NotificationPreferences) GetTaskReminders() bool {
 	if x != nil {
-		return x.Email
+		return x.TaskReminders
 	}
-	return ""
+	return false
 }
 
-func (x *EditUser) GetName() string {
+func (x *NotificationPreferences) GetProductUpdates() bool {
 	if x != nil {
-		return x.Name
+		return x.ProductUpdates
 	}
-	return ""
-}
-
-func (x *EditUser) GetUserName() string {
-	if x != nil {
-		return x.UserName
-	}
-	return ""
-}
-
-func (x *EditUser) GetPassword() string {
-	if x != nil {
-		return x.Password
-	}
-	return ""
+	return false
 }
 
-// No "id".
-type CreateUserReq struct {
+// The views the app opens on.
+type DefaultViews struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	User *NewUser `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Tasks    TaskView     `protobuf:"varint,1,opt,name=tasks,proto3,enum=user.TaskView" json:"tasks,omitempty"`
+	Calendar CalendarView `protobuf:"varint,2,opt,name=calendar,proto3,enum=user.CalendarView" json:"calendar,omitempty"`
 }
 
-func (x *CreateUserReq) Reset() {
-	*x = CreateUserReq{}
+func (x *DefaultViews) Reset() {
+	*x = DefaultViews{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_user_proto_user_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -276,13 +650,13 @@ func (x *CreateUserReq) Reset() {
 	}
 }
 
-func (x *CreateUserReq) String() string {
+func (x *DefaultViews) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateUserReq) ProtoMessage() {}
+func (*DefaultViews) ProtoMessage() {}
 
-func (x *CreateUserReq) ProtoReflect() protoreflect.Message {
+func (x *DefaultViews) ProtoReflect() protoreflect.Message {
 	mi := &file_user_proto_user_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -294,29 +668,36 @@ func (x *CreateUserReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateUserReq.ProtoReflect.Descriptor instead.
-func (*CreateUserReq) Descriptor() ([]byte, []int) {
+// Deprecated: Use DefaultViews.ProtoReflect.Descriptor instead.
+func (*DefaultViews) Descriptor() ([]byte, []int) {
 	return file_user_proto_user_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *CreateUserReq) GetUser() *NewUser {
+func (x *DefaultViews) GetTasks() TaskView {
 	if x != nil {
-		return x.User
+		return x.Tasks
 	}
-	return nil
+	return TaskView_TASK_VIEW_UNSPECIFIED
 }
 
-// No "password".
-type CreateUserRes struct {
+func (x *DefaultViews) GetCalendar() CalendarView {
+	if x != nil {
+		return x.Calendar
+	}
+	return CalendarView_CALENDAR_VIEW_UNSPECIFIED
+}
+
+// What a user shows others.
+type PrivacyPreferences struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	User *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	ProfileVisibility ProfileVisibility `protobuf:"varint,1,opt,name=profileVisibility,proto3,enum=user.ProfileVisibility" json:"profileVisibility,omitempty"`
 }
 
-func (x *CreateUserRes) Reset() {
-	*x = CreateUserRes{}
+func (x *PrivacyPreferences) Reset() {
+	*x = PrivacyPreferences{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_user_proto_user_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -324,13 +705,13 @@ func (x *CreateUserRes) Reset() {
 	}
 }
 
-func (x *CreateUserRes) String() string {
+func (x *PrivacyPreferences) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateUserRes) ProtoMessage() {}
+func (*PrivacyPreferences) ProtoMessage() {}
 
-func (x *CreateUserRes) ProtoReflect() protoreflect.Message {
+func (x *PrivacyPreferences) ProtoReflect() protoreflect.Message {
 	mi := &file_user_proto_user_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -342,29 +723,36 @@ func (x *CreateUserRes) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateUserRes.ProtoReflect.Descriptor instead.
-func (*CreateUserRes) Descriptor() ([]byte, []int) {
+// Deprecated: Use PrivacyPreferences.ProtoReflect.Descriptor instead.
+func (*PrivacyPreferences) Descriptor() ([]byte, []int) {
 	return file_user_proto_user_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *CreateUserRes) GetUser() *User {
+func (x *PrivacyPreferences) GetProfileVisibility() ProfileVisibility {
 	if x != nil {
-		return x.User
+		return x.ProfileVisibility
 	}
-	return nil
+	return ProfileVisibility_PROFILE_VISIBILITY_UNSPECIFIED
 }
 
-// No "password".
-type ReadUserReq struct {
+// Create a message for how a user wants the app to behave.
+// IMPORTANT:
+// - Users who never set theirs get the defaults, at version 0.
+// - "version" counts the times the preferences were changed.
+type Preferences struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Theme         Theme                    `protobuf:"varint,1,opt,name=theme,proto3,enum=user.Theme" json:"theme,omitempty"`
+	Notifications *NotificationPreferences `protobuf:"bytes,2,opt,name=notifications,proto3" json:"notifications,omitempty"`
+	DefaultViews  *DefaultViews            `protobuf:"bytes,3,opt,name=defaultViews,proto3" json:"defaultViews,omitempty"`
+	Version       int64                    `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	Privacy       *PrivacyPreferences      `protobuf:"bytes,5,opt,name=privacy,proto3" json:"privacy,omitempty"`
 }
 
-func (x *ReadUserReq) Reset() {
-	*x = ReadUserReq{}
+func (x *Preferences) Reset() {
+	*x = Preferences{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_user_proto_user_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -372,13 +760,13 @@ func (x *ReadUserReq) Reset() {
 	}
 }
 
-func (x *ReadUserReq) String() string {
+func (x *Preferences) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReadUserReq) ProtoMessage() {}
+func (*Preferences) ProtoMessage() {}
 
-func (x *ReadUserReq) ProtoReflect() protoreflect.Message {
+func (x *Preferences) ProtoReflect() protoreflect.Message {
 	mi := &file_user_proto_user_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -390,92 +778,84 @@ func (x *ReadUserReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReadUserReq.ProtoReflect.Descriptor instead.
-func (*ReadUserReq) Descriptor() ([]byte, []int) {
+// Deprecated: Use Preferences.ProtoReflect.Descriptor instead.
+func (*Preferences) Descriptor() ([]byte, []int) {
 	return file_user_proto_user_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *ReadUserReq) GetId() string {
+func (x *Preferences) GetTheme() Theme {
 	if x != nil {
-		return x.Id
+		return x.Theme
 	}
-	return ""
-}
-
-// No "password".
-type ReadUserRes struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	User *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	return Theme_THEME_UNSPECIFIED
 }
 
-func (x *ReadUserRes) Reset() {
-	*x = ReadUserRes{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_user_proto_user_proto_msgTypes[6]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *Preferences) GetNotifications() *NotificationPreferences {
+	if x != nil {
+		return x.Notifications
 	}
+	return nil
 }
 
-func (x *ReadUserRes) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*ReadUserRes) ProtoMessage() {}
-
-func (x *ReadUserRes) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_user_proto_msgTypes[6]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *Preferences) GetDefaultViews() *DefaultViews {
+	if x != nil {
+		return x.DefaultViews
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use ReadUserRes.ProtoReflect.Descriptor instead.
-func (*ReadUserRes) Descriptor() ([]byte, []int) {
-	return file_user_proto_user_proto_rawDescGZIP(), []int{6}
+func (x *Preferences) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
 }
 
-func (x *ReadUserRes) GetUser() *User {
+func (x *Preferences) GetPrivacy() *PrivacyPreferences {
 	if x != nil {
-		return x.User
+		return x.Privacy
 	}
 	return nil
 }
 
-// Has both "id" and "password".
-type UpdateUserReq struct {
+// Create a message for an entry of the security audit log.
+// IMPORTANT:
+// - Entries are append-only, they are never updated.
+// - No "actorId" when nobody was authenticated, or the system acted.
+// - No "targetId" when the action was not about a known user.
+type AuditEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	User *EditUser `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Action    string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	ActorId   string                 `protobuf:"bytes,3,opt,name=actorId,proto3" json:"actorId,omitempty"`
+	TargetId  string                 `protobuf:"bytes,4,opt,name=targetId,proto3" json:"targetId,omitempty"`
+	Ip        string                 `protobuf:"bytes,5,opt,name=ip,proto3" json:"ip,omitempty"`
+	UserAgent string                 `protobuf:"bytes,6,opt,name=userAgent,proto3" json:"userAgent,omitempty"`
+	Time      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=time,proto3" json:"time,omitempty"`
+	Outcome   AuditOutcome           `protobuf:"varint,8,opt,name=outcome,proto3,enum=user.AuditOutcome" json:"outcome,omitempty"`
+	Detail    string                 `protobuf:"bytes,9,opt,name=detail,proto3" json:"detail,omitempty"`
 }
 
-func (x *UpdateUserReq) Reset() {
-	*x = UpdateUserReq{}
+func (x *AuditEvent) Reset() {
+	*x = AuditEvent{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_user_proto_user_proto_msgTypes[7]
+		mi := &file_user_proto_user_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *UpdateUserReq) String() string {
+func (x *AuditEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateUserReq) ProtoMessage() {}
+func (*AuditEvent) ProtoMessage() {}
 
-func (x *UpdateUserReq) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_user_proto_msgTypes[7]
+func (x *AuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -486,92 +866,106 @@ func (x *UpdateUserReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateUserReq.ProtoReflect.Descriptor instead.
-func (*UpdateUserReq) Descriptor() ([]byte, []int) {
-	return file_user_proto_user_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *UpdateUserReq) GetUser() *EditUser {
+func (x *AuditEvent) GetId() string {
 	if x != nil {
-		return x.User
+		return x.Id
 	}
-	return nil
+	return ""
 }
 
-// No "password".
-type UpdateUserRes struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *AuditEvent) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
 
-	User *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+func (x *AuditEvent) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
 }
 
-func (x *UpdateUserRes) Reset() {
-	*x = UpdateUserRes{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_user_proto_user_proto_msgTypes[8]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *AuditEvent) GetTargetId() string {
+	if x != nil {
+		return x.TargetId
 	}
+	return ""
 }
 
-func (x *UpdateUserRes) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *AuditEvent) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
 }
 
-func (*UpdateUserRes) ProtoMessage() {}
+func (x *AuditEvent) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
 
-func (x *UpdateUserRes) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_user_proto_msgTypes[8]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *AuditEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use UpdateUserRes.ProtoReflect.Descriptor instead.
-func (*UpdateUserRes) Descriptor() ([]byte, []int) {
-	return file_user_proto_user_proto_rawDescGZIP(), []int{8}
+func (x *AuditEvent) GetOutcome() AuditOutcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return AuditOutcome_AUDIT_OUTCOME_UNSPECIFIED
 }
 
-func (x *UpdateUserRes) GetUser() *User {
+func (x *AuditEvent) GetDetail() string {
 	if x != nil {
-		return x.User
+		return x.Detail
 	}
-	return nil
+	return ""
 }
 
-// No "password".
-type DeleteUserReq struct {
+// Create a message for new users.
+// IMPORTANT:
+// - No "id" field because the user is new.
+// - Has hashed and salted "password" field.
+type NewUser struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Email    string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Name     string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	UserName string `protobuf:"bytes,3,opt,name=userName,proto3" json:"userName,omitempty"`
+	Password string `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
 }
 
-func (x *DeleteUserReq) Reset() {
-	*x = DeleteUserReq{}
+func (x *NewUser) Reset() {
+	*x = NewUser{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_user_proto_user_proto_msgTypes[9]
+		mi := &file_user_proto_user_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *DeleteUserReq) String() string {
+func (x *NewUser) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteUserReq) ProtoMessage() {}
+func (*NewUser) ProtoMessage() {}
 
-func (x *DeleteUserReq) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_user_proto_msgTypes[9]
+func (x *NewUser) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -582,43 +976,83 @@ func (x *DeleteUserReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteUserReq.ProtoReflect.Descriptor instead.
-func (*DeleteUserReq) Descriptor() ([]byte, []int) {
-	return file_user_proto_user_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use NewUser.ProtoReflect.Descriptor instead.
+func (*NewUser) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *DeleteUserReq) GetId() string {
+func (x *NewUser) GetEmail() string {
 	if x != nil {
-		return x.Id
+		return x.Email
 	}
 	return ""
 }
 
-type DeleteUserRes struct {
+func (x *NewUser) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NewUser) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *NewUser) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+// Create a message for a registered user.
+// IMPORTANT:
+// - Has "id" field because the user is registered.
+// - No "password" field because we should never return it.
+// - Has "deleteAfter" only while the account is pending deletion.
+// - Has "suspensionReason" and maybe "suspendedUntil" only while suspended.
+type User struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Success bool `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Email            string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Name             string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	UserName         string                 `protobuf:"bytes,4,opt,name=userName,proto3" json:"userName,omitempty"`
+	DeleteAfter      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=deleteAfter,proto3" json:"deleteAfter,omitempty"`
+	Status           Status                 `protobuf:"varint,6,opt,name=status,proto3,enum=user.Status" json:"status,omitempty"`
+	Role             Role                   `protobuf:"varint,7,opt,name=role,proto3,enum=user.Role" json:"role,omitempty"`
+	SuspensionReason string                 `protobuf:"bytes,8,opt,name=suspensionReason,proto3" json:"suspensionReason,omitempty"`
+	SuspendedUntil   *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=suspendedUntil,proto3" json:"suspendedUntil,omitempty"`
+	// The ID of the current avatar, whose images the API stores; empty when the
+	// user has none.
+	Avatar      string       `protobuf:"bytes,10,opt,name=avatar,proto3" json:"avatar,omitempty"`
+	Profile     *Profile     `protobuf:"bytes,11,opt,name=profile,proto3" json:"profile,omitempty"`
+	Preferences *Preferences `protobuf:"bytes,12,opt,name=preferences,proto3" json:"preferences,omitempty"`
 }
 
-func (x *DeleteUserRes) Reset() {
-	*x = DeleteUserRes{}
+func (x *User) Reset() {
+	*x = User{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_user_proto_user_proto_msgTypes[10]
+		mi := &file_user_proto_user_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *DeleteUserRes) String() string {
+func (x *User) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteUserRes) ProtoMessage() {}
+func (*User) ProtoMessage() {}
 
-func (x *DeleteUserRes) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_user_proto_msgTypes[10]
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -629,29 +1063,1975 @@ func (x *DeleteUserRes) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteUserRes.ProtoReflect.Descriptor instead.
-func (*DeleteUserRes) Descriptor() ([]byte, []int) {
-	return file_user_proto_user_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *User) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *User) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *User) GetDeleteAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeleteAfter
+	}
+	return nil
+}
+
+func (x *User) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+func (x *User) GetRole() Role {
+	if x != nil {
+		return x.Role
+	}
+	return Role_ROLE_UNSPECIFIED
+}
+
+func (x *User) GetSuspensionReason() string {
+	if x != nil {
+		return x.SuspensionReason
+	}
+	return ""
+}
+
+func (x *User) GetSuspendedUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SuspendedUntil
+	}
+	return nil
+}
+
+func (x *User) GetAvatar() string {
+	if x != nil {
+		return x.Avatar
+	}
+	return ""
+}
+
+func (x *User) GetProfile() *Profile {
+	if x != nil {
+		return x.Profile
+	}
+	return nil
+}
+
+func (x *User) GetPreferences() *Preferences {
+	if x != nil {
+		return x.Preferences
+	}
+	return nil
+}
+
+// Create a message for updating a registered user.
+// IMPORTANT:
+// - Has "id" field because the user is registered.
+// - Has "password" field because this user is "me".
+type EditUser struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Email    string `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Name     string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	UserName string `protobuf:"bytes,4,opt,name=userName,proto3" json:"userName,omitempty"`
+	Password string `protobuf:"bytes,5,opt,name=password,proto3" json:"password,omitempty"`
+	// Only changed when named by the update mask.
+	Avatar string `protobuf:"bytes,6,opt,name=avatar,proto3" json:"avatar,omitempty"`
+}
+
+func (x *EditUser) Reset() {
+	*x = EditUser{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EditUser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditUser) ProtoMessage() {}
+
+func (x *EditUser) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditUser.ProtoReflect.Descriptor instead.
+func (*EditUser) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *EditUser) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *EditUser) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *EditUser) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EditUser) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *EditUser) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *EditUser) GetAvatar() string {
+	if x != nil {
+		return x.Avatar
+	}
+	return ""
+}
+
+// No "id".
+type CreateUserReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User *NewUser `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+}
+
+func (x *CreateUserReq) Reset() {
+	*x = CreateUserReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateUserReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserReq) ProtoMessage() {}
+
+func (x *CreateUserReq) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserReq.ProtoReflect.Descriptor instead.
+func (*CreateUserReq) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateUserReq) GetUser() *NewUser {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// No "password".
+type CreateUserRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+}
+
+func (x *CreateUserRes) Reset() {
+	*x = CreateUserRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateUserRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRes) ProtoMessage() {}
+
+func (x *CreateUserRes) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRes.ProtoReflect.Descriptor instead.
+func (*CreateUserRes) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CreateUserRes) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// No "password".
+type ReadUserReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *ReadUserReq) Reset() {
+	*x = ReadUserReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadUserReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadUserReq) ProtoMessage() {}
+
+func (x *ReadUserReq) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadUserReq.ProtoReflect.Descriptor instead.
+func (*ReadUserReq) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ReadUserReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// No "password".
+type ReadUserRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+}
+
+func (x *ReadUserRes) Reset() {
+	*x = ReadUserRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadUserRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadUserRes) ProtoMessage() {}
+
+func (x *ReadUserRes) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadUserRes.ProtoReflect.Descriptor instead.
+func (*ReadUserRes) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReadUserRes) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// Finds the user who had "userName" until recently too, when nobody has it.
+type ReadUserByUserNameReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserName string `protobuf:"bytes,1,opt,name=userName,proto3" json:"userName,omitempty"`
+}
+
+func (x *ReadUserByUserNameReq) Reset() {
+	*x = ReadUserByUserNameReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadUserByUserNameReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadUserByUserNameReq) ProtoMessage() {}
+
+func (x *ReadUserByUserNameReq) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadUserByUserNameReq.ProtoReflect.Descriptor instead.
+func (*ReadUserByUserNameReq) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ReadUserByUserNameReq) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+// No "password". The "userName" of the user differs from the one asked for
+// when it was a former one.
+type ReadUserByUserNameRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+}
+
+func (x *ReadUserByUserNameRes) Reset() {
+	*x = ReadUserByUserNameRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadUserByUserNameRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadUserByUserNameRes) ProtoMessage() {}
+
+func (x *ReadUserByUserNameRes) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadUserByUserNameRes.ProtoReflect.Descriptor instead.
+func (*ReadUserByUserNameRes) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ReadUserByUserNameRes) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// Unknown and deleted IDs are left out of the response.
+type BatchReadUsersReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ids []string `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+}
+
+func (x *BatchReadUsersReq) Reset() {
+	*x = BatchReadUsersReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchReadUsersReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchReadUsersReq) ProtoMessage() {}
+
+func (x *BatchReadUsersReq) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchReadUsersReq.ProtoReflect.Descriptor instead.
+func (*BatchReadUsersReq) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *BatchReadUsersReq) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+// No "password". In no particular order.
+type BatchReadUsersRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Users []*User `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+}
+
+func (x *BatchReadUsersRes) Reset() {
+	*x = BatchReadUsersRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchReadUsersRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchReadUsersRes) ProtoMessage() {}
+
+func (x *BatchReadUsersRes) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchReadUsersRes.ProtoReflect.Descriptor instead.
+func (*BatchReadUsersRes) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *BatchReadUsersRes) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+// Has both "id" and "password".
+// "updateMask" names the fields of "user" to update, among "email", "name",
+// "userName" and "password". All of them are updated when it is unset.
+type UpdateUserReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User       *EditUser              `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=updateMask,proto3" json:"updateMask,omitempty"`
+}
+
+func (x *UpdateUserReq) Reset() {
+	*x = UpdateUserReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateUserReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserReq) ProtoMessage() {}
+
+func (x *UpdateUserReq) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserReq.ProtoReflect.Descriptor instead.
+func (*UpdateUserReq) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *UpdateUserReq) GetUser() *EditUser {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *UpdateUserReq) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+// No "password".
+type UpdateUserRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+}
+
+func (x *UpdateUserRes) Reset() {
+	*x = UpdateUserRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateUserRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserRes) ProtoMessage() {}
+
+func (x *UpdateUserRes) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserRes.ProtoReflect.Descriptor instead.
+func (*UpdateUserRes) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *UpdateUserRes) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// "updateMask" names the fields of "profile" to update, among "displayName",
+// "bio", "links", "location", "timezone", "locale" and "pronouns". All of
+// them are updated when it is unset. The "version" of "profile" is that of
+// the profile the changes were made to; the update is aborted when it is no
+// longer current.
+type UpdateProfileReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Profile    *Profile               `protobuf:"bytes,2,opt,name=profile,proto3" json:"profile,omitempty"`
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=updateMask,proto3" json:"updateMask,omitempty"`
+}
+
+func (x *UpdateProfileReq) Reset() {
+	*x = UpdateProfileReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateProfileReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProfileReq) ProtoMessage() {}
+
+func (x *UpdateProfileReq) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProfileReq.ProtoReflect.Descriptor instead.
+func (*UpdateProfileReq) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UpdateProfileReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateProfileReq) GetProfile() *Profile {
+	if x != nil {
+		return x.Profile
+	}
+	return nil
+}
+
+func (x *UpdateProfileReq) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+// No "password".
+type UpdateProfileRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+}
+
+func (x *UpdateProfileRes) Reset() {
+	*x = UpdateProfileRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateProfileRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProfileRes) ProtoMessage() {}
+
+func (x *UpdateProfileRes) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProfileRes.ProtoReflect.Descriptor instead.
+func (*UpdateProfileRes) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *UpdateProfileRes) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// "updateMask" names the fields of "preferences" to update, among "theme",
+// "notifications", "defaultViews" and "privacy", or their fields such as
+// "notifications.taskReminders". All of them are updated when it is unset.
+// Their "version" works as it does for profiles.
+type UpdatePreferencesReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Preferences *Preferences           `protobuf:"bytes,2,opt,name=preferences,proto3" json:"preferences,omitempty"`
+	UpdateMask  *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=updateMask,proto3" json:"updateMask,omitempty"`
+}
+
+func (x *UpdatePreferencesReq) Reset() {
+	*x = UpdatePreferencesReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdatePreferencesReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePreferencesReq) ProtoMessage() {}
+
+func (x *UpdatePreferencesReq) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePreferencesReq.ProtoReflect.Descriptor instead.
+func (*UpdatePreferencesReq) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *UpdatePreferencesReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdatePreferencesReq) GetPreferences() *Preferences {
+	if x != nil {
+		return x.Preferences
+	}
+	return nil
+}
+
+func (x *UpdatePreferencesReq) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+// No "password".
+type UpdatePreferencesRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+}
+
+func (x *UpdatePreferencesRes) Reset() {
+	*x = UpdatePreferencesRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdatePreferencesRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePreferencesRes) ProtoMessage() {}
+
+func (x *UpdatePreferencesRes) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePreferencesRes.ProtoReflect.Descriptor instead.
+func (*UpdatePreferencesRes) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *UpdatePreferencesRes) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// No "password".
+type DeleteUserReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *DeleteUserReq) Reset() {
+	*x = DeleteUserReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteUserReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserReq) ProtoMessage() {}
+
+func (x *DeleteUserReq) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserReq.ProtoReflect.Descriptor instead.
+func (*DeleteUserReq) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *DeleteUserReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Has the time the account will be purged.
+type DeleteUserRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Success     bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	DeleteAfter *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=deleteAfter,proto3" json:"deleteAfter,omitempty"`
+}
+
+func (x *DeleteUserRes) Reset() {
+	*x = DeleteUserRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteUserRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserRes) ProtoMessage() {}
+
+func (x *DeleteUserRes) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserRes.ProtoReflect.Descriptor instead.
+func (*DeleteUserRes) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *DeleteUserRes) GetSuccess() bool {
 	if x != nil {
-		return x.Success
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeleteUserRes) GetDeleteAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeleteAfter
+	}
+	return nil
+}
+
+// No "password".
+type CancelUserDeletionReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *CancelUserDeletionReq) Reset() {
+	*x = CancelUserDeletionReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelUserDeletionReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelUserDeletionReq) ProtoMessage() {}
+
+func (x *CancelUserDeletionReq) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelUserDeletionReq.ProtoReflect.Descriptor instead.
+func (*CancelUserDeletionReq) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *CancelUserDeletionReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// No "password".
+type CancelUserDeletionRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+}
+
+func (x *CancelUserDeletionRes) Reset() {
+	*x = CancelUserDeletionRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelUserDeletionRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelUserDeletionRes) ProtoMessage() {}
+
+func (x *CancelUserDeletionRes) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelUserDeletionRes.ProtoReflect.Descriptor instead.
+func (*CancelUserDeletionRes) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CancelUserDeletionRes) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// No "password".
+// Without "until" the suspension lasts until the user is reinstated.
+type SuspendUserReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Until  *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=until,proto3" json:"until,omitempty"`
+}
+
+func (x *SuspendUserReq) Reset() {
+	*x = SuspendUserReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SuspendUserReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuspendUserReq) ProtoMessage() {}
+
+func (x *SuspendUserReq) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuspendUserReq.ProtoReflect.Descriptor instead.
+func (*SuspendUserReq) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *SuspendUserReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SuspendUserReq) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *SuspendUserReq) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+// No "password".
+type SuspendUserRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+}
+
+func (x *SuspendUserRes) Reset() {
+	*x = SuspendUserRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SuspendUserRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuspendUserRes) ProtoMessage() {}
+
+func (x *SuspendUserRes) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuspendUserRes.ProtoReflect.Descriptor instead.
+func (*SuspendUserRes) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SuspendUserRes) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// No "password".
+type ReinstateUserReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *ReinstateUserReq) Reset() {
+	*x = ReinstateUserReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReinstateUserReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReinstateUserReq) ProtoMessage() {}
+
+func (x *ReinstateUserReq) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReinstateUserReq.ProtoReflect.Descriptor instead.
+func (*ReinstateUserReq) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ReinstateUserReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// No "password".
+type ReinstateUserRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+}
+
+func (x *ReinstateUserRes) Reset() {
+	*x = ReinstateUserRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReinstateUserRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReinstateUserRes) ProtoMessage() {}
+
+func (x *ReinstateUserRes) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReinstateUserRes.ProtoReflect.Descriptor instead.
+func (*ReinstateUserRes) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ReinstateUserRes) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// No "password".
+type ExportUserDataReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *ExportUserDataReq) Reset() {
+	*x = ExportUserDataReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportUserDataReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUserDataReq) ProtoMessage() {}
+
+func (x *ExportUserDataReq) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUserDataReq.ProtoReflect.Descriptor instead.
+func (*ExportUserDataReq) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ExportUserDataReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// One stored document, as relaxed extended JSON, along with the collection
+// it came from. Documents of the same collection are streamed together.
+// No "password".
+type ExportUserDataRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Collection string `protobuf:"bytes,1,opt,name=collection,proto3" json:"collection,omitempty"`
+	Document   string `protobuf:"bytes,2,opt,name=document,proto3" json:"document,omitempty"`
+}
+
+func (x *ExportUserDataRes) Reset() {
+	*x = ExportUserDataRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportUserDataRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUserDataRes) ProtoMessage() {}
+
+func (x *ExportUserDataRes) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUserDataRes.ProtoReflect.Descriptor instead.
+func (*ExportUserDataRes) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ExportUserDataRes) GetCollection() string {
+	if x != nil {
+		return x.Collection
+	}
+	return ""
+}
+
+func (x *ExportUserDataRes) GetDocument() string {
+	if x != nil {
+		return x.Document
+	}
+	return ""
+}
+
+// "id" and "time" of the event are set by the service. The caller, IP and
+// user agent are read from the call metadata when unset.
+type RecordAuditEventReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Event *AuditEvent `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+}
+
+func (x *RecordAuditEventReq) Reset() {
+	*x = RecordAuditEventReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RecordAuditEventReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordAuditEventReq) ProtoMessage() {}
+
+func (x *RecordAuditEventReq) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordAuditEventReq.ProtoReflect.Descriptor instead.
+func (*RecordAuditEventReq) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *RecordAuditEventReq) GetEvent() *AuditEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+// No args.
+type RecordAuditEventRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *RecordAuditEventRes) Reset() {
+	*x = RecordAuditEventRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RecordAuditEventRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordAuditEventRes) ProtoMessage() {}
+
+func (x *RecordAuditEventRes) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordAuditEventRes.ProtoReflect.Descriptor instead.
+func (*RecordAuditEventRes) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{35}
+}
+
+// Every filter that is set must match. "userId" matches the events whose
+// actor or target is that user. Events are listed newest first; "after" is
+// the ID of the last event of the previous page.
+type ListAuditEventsReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ActorId  string                 `protobuf:"bytes,1,opt,name=actorId,proto3" json:"actorId,omitempty"`
+	TargetId string                 `protobuf:"bytes,2,opt,name=targetId,proto3" json:"targetId,omitempty"`
+	UserId   string                 `protobuf:"bytes,3,opt,name=userId,proto3" json:"userId,omitempty"`
+	Action   string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	Since    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=since,proto3" json:"since,omitempty"`
+	Until    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=until,proto3" json:"until,omitempty"`
+	After    string                 `protobuf:"bytes,7,opt,name=after,proto3" json:"after,omitempty"`
+	First    int32                  `protobuf:"varint,8,opt,name=first,proto3" json:"first,omitempty"`
+}
+
+func (x *ListAuditEventsReq) Reset() {
+	*x = ListAuditEventsReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListAuditEventsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsReq) ProtoMessage() {}
+
+func (x *ListAuditEventsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsReq.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsReq) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ListAuditEventsReq) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *ListAuditEventsReq) GetTargetId() string {
+	if x != nil {
+		return x.TargetId
+	}
+	return ""
+}
+
+func (x *ListAuditEventsReq) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListAuditEventsReq) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ListAuditEventsReq) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *ListAuditEventsReq) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *ListAuditEventsReq) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+func (x *ListAuditEventsReq) GetFirst() int32 {
+	if x != nil {
+		return x.First
+	}
+	return 0
+}
+
+type ListAuditEventsRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Events      []*AuditEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	HasNextPage bool          `protobuf:"varint,2,opt,name=hasNextPage,proto3" json:"hasNextPage,omitempty"`
+}
+
+func (x *ListAuditEventsRes) Reset() {
+	*x = ListAuditEventsRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListAuditEventsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsRes) ProtoMessage() {}
+
+func (x *ListAuditEventsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsRes.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsRes) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ListAuditEventsRes) GetEvents() []*AuditEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListAuditEventsRes) GetHasNextPage() bool {
+	if x != nil {
+		return x.HasNextPage
+	}
+	return false
+}
+
+// No args.
+type PurgeUsersReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *PurgeUsersReq) Reset() {
+	*x = PurgeUsersReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PurgeUsersReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeUsersReq) ProtoMessage() {}
+
+func (x *PurgeUsersReq) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeUsersReq.ProtoReflect.Descriptor instead.
+func (*PurgeUsersReq) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{38}
+}
+
+// Has the ids of every purged user whose cleanup by the API is yet to be
+// confirmed with ConfirmPurgedUsers, whether purged by this call or an
+// earlier one.
+type PurgeUsersRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ids []string `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+}
+
+func (x *PurgeUsersRes) Reset() {
+	*x = PurgeUsersRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PurgeUsersRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeUsersRes) ProtoMessage() {}
+
+func (x *PurgeUsersRes) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeUsersRes.ProtoReflect.Descriptor instead.
+func (*PurgeUsersRes) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *PurgeUsersRes) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+// Has the ids of purged users of whom nothing is left in the API.
+type ConfirmPurgedUsersReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ids []string `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+}
+
+func (x *ConfirmPurgedUsersReq) Reset() {
+	*x = ConfirmPurgedUsersReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfirmPurgedUsersReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmPurgedUsersReq) ProtoMessage() {}
+
+func (x *ConfirmPurgedUsersReq) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmPurgedUsersReq.ProtoReflect.Descriptor instead.
+func (*ConfirmPurgedUsersReq) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ConfirmPurgedUsersReq) GetIds() []string {
+	if x != nil {
+		return x.Ids
 	}
-	return false
+	return nil
 }
 
 // No args.
+type ConfirmPurgedUsersRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ConfirmPurgedUsersRes) Reset() {
+	*x = ConfirmPurgedUsersRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfirmPurgedUsersRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmPurgedUsersRes) ProtoMessage() {}
+
+func (x *ConfirmPurgedUsersRes) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmPurgedUsersRes.ProtoReflect.Descriptor instead.
+func (*ConfirmPurgedUsersRes) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{41}
+}
+
+// Has a plain text "password" to compare against the stored hash.
+// Looks the user up by "id" when set, otherwise by "email".
+type AuthenticateUserReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Email    string `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Password string `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+}
+
+func (x *AuthenticateUserReq) Reset() {
+	*x = AuthenticateUserReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthenticateUserReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticateUserReq) ProtoMessage() {}
+
+func (x *AuthenticateUserReq) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticateUserReq.ProtoReflect.Descriptor instead.
+func (*AuthenticateUserReq) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *AuthenticateUserReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AuthenticateUserReq) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *AuthenticateUserReq) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+// No "password".
+type AuthenticateUserRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+}
+
+func (x *AuthenticateUserRes) Reset() {
+	*x = AuthenticateUserRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_user_proto_user_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthenticateUserRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticateUserRes) ProtoMessage() {}
+
+func (x *AuthenticateUserRes) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_user_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticateUserRes.ProtoReflect.Descriptor instead.
+func (*AuthenticateUserRes) Descriptor() ([]byte, []int) {
+	return file_user_proto_user_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *AuthenticateUserRes) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// Lists only active users unless "includeInactive" is set.
 type ListUsersReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	IncludeInactive bool `protobuf:"varint,1,opt,name=includeInactive,proto3" json:"includeInactive,omitempty"`
 }
 
 func (x *ListUsersReq) Reset() {
 	*x = ListUsersReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_user_proto_user_proto_msgTypes[11]
+		mi := &file_user_proto_user_proto_msgTypes[44]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -664,7 +3044,7 @@ func (x *ListUsersReq) String() string {
 func (*ListUsersReq) ProtoMessage() {}
 
 func (x *ListUsersReq) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_user_proto_msgTypes[11]
+	mi := &file_user_proto_user_proto_msgTypes[44]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -677,7 +3057,14 @@ func (x *ListUsersReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListUsersReq.ProtoReflect.Descriptor instead.
 func (*ListUsersReq) Descriptor() ([]byte, []int) {
-	return file_user_proto_user_proto_rawDescGZIP(), []int{11}
+	return file_user_proto_user_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ListUsersReq) GetIncludeInactive() bool {
+	if x != nil {
+		return x.IncludeInactive
+	}
+	return false
 }
 
 // No "password".
@@ -692,7 +3079,7 @@ type ListUsersRes struct {
 func (x *ListUsersRes) Reset() {
 	*x = ListUsersRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_user_proto_user_proto_msgTypes[12]
+		mi := &file_user_proto_user_proto_msgTypes[45]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -705,7 +3092,7 @@ func (x *ListUsersRes) String() string {
 func (*ListUsersRes) ProtoMessage() {}
 
 func (x *ListUsersRes) ProtoReflect() protoreflect.Message {
-	mi := &file_user_proto_user_proto_msgTypes[12]
+	mi := &file_user_proto_user_proto_msgTypes[45]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -718,7 +3105,7 @@ func (x *ListUsersRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListUsersRes.ProtoReflect.Descriptor instead.
 func (*ListUsersRes) Descriptor() ([]byte, []int) {
-	return file_user_proto_user_proto_rawDescGZIP(), []int{12}
+	return file_user_proto_user_proto_rawDescGZIP(), []int{45}
 }
 
 func (x *ListUsersRes) GetUser() *User {
@@ -732,73 +3119,455 @@ var File_user_proto_user_proto protoreflect.FileDescriptor
 
 var file_user_proto_user_proto_rawDesc = []byte{
 	0x0a, 0x15, 0x75, 0x73, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x75, 0x73, 0x65,
-	0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x6b, 0x0a,
-	0x07, 0x4e, 0x65, 0x77, 0x55, 0x73, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d, 0x61, 0x69,
-	0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x12,
-	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1a,
+	0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x75, 0x73, 0x65, 0x72, 0x1a, 0x1c, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x20, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x66, 0x69, 0x65,
+	0x6c, 0x64, 0x5f, 0x6d, 0x61, 0x73, 0x6b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x19,
+	0x75, 0x73, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x48, 0x0a, 0x0b, 0x50, 0x72, 0x6f,
+	0x66, 0x69, 0x6c, 0x65, 0x4c, 0x69, 0x6e, 0x6b, 0x12, 0x1c, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x06, 0x8a, 0xb5, 0x18, 0x02, 0x28, 0x32, 0x52,
+	0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x1b, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x42, 0x09, 0x8a, 0xb5, 0x18, 0x05, 0x08, 0x01, 0x28, 0x80, 0x10, 0x52, 0x03,
+	0x75, 0x72, 0x6c, 0x22, 0xa5, 0x02, 0x0a, 0x07, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12,
+	0x28, 0x0a, 0x0b, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x06, 0x8a, 0xb5, 0x18, 0x02, 0x28, 0x64, 0x52, 0x0b, 0x64, 0x69,
+	0x73, 0x70, 0x6c, 0x61, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x03, 0x62, 0x69, 0x6f,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0x8a, 0xb5, 0x18, 0x03, 0x28, 0xe8, 0x07, 0x52,
+	0x03, 0x62, 0x69, 0x6f, 0x12, 0x2f, 0x0a, 0x05, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x66, 0x69,
+	0x6c, 0x65, 0x4c, 0x69, 0x6e, 0x6b, 0x42, 0x06, 0x8a, 0xb5, 0x18, 0x02, 0x30, 0x05, 0x52, 0x05,
+	0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x12, 0x22, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x42, 0x06, 0x8a, 0xb5, 0x18, 0x02, 0x28, 0x64, 0x52,
+	0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x08, 0x74, 0x69, 0x6d,
+	0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x42, 0x06, 0x8a, 0xb5, 0x18,
+	0x02, 0x28, 0x40, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x12, 0x1e, 0x0a,
+	0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x42, 0x06, 0x8a,
+	0xb5, 0x18, 0x02, 0x28, 0x23, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x12, 0x22, 0x0a,
+	0x08, 0x70, 0x72, 0x6f, 0x6e, 0x6f, 0x75, 0x6e, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x42,
+	0x06, 0x8a, 0xb5, 0x18, 0x02, 0x28, 0x28, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x6e, 0x6f, 0x75, 0x6e,
+	0x73, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x8f, 0x01, 0x0a, 0x17,
+	0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x65, 0x66,
+	0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x73, 0x65, 0x63, 0x75, 0x72,
+	0x69, 0x74, 0x79, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x73, 0x12,
+	0x24, 0x0a, 0x0d, 0x74, 0x61, 0x73, 0x6b, 0x52, 0x65, 0x6d, 0x69, 0x6e, 0x64, 0x65, 0x72, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x74, 0x61, 0x73, 0x6b, 0x52, 0x65, 0x6d, 0x69,
+	0x6e, 0x64, 0x65, 0x72, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x70,
+	0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x22, 0x64, 0x0a,
+	0x0c, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x56, 0x69, 0x65, 0x77, 0x73, 0x12, 0x24, 0x0a,
+	0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x75,
+	0x73, 0x65, 0x72, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x56, 0x69, 0x65, 0x77, 0x52, 0x05, 0x74, 0x61,
+	0x73, 0x6b, 0x73, 0x12, 0x2e, 0x0a, 0x08, 0x63, 0x61, 0x6c, 0x65, 0x6e, 0x64, 0x61, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x43, 0x61, 0x6c,
+	0x65, 0x6e, 0x64, 0x61, 0x72, 0x56, 0x69, 0x65, 0x77, 0x52, 0x08, 0x63, 0x61, 0x6c, 0x65, 0x6e,
+	0x64, 0x61, 0x72, 0x22, 0x5b, 0x0a, 0x12, 0x50, 0x72, 0x69, 0x76, 0x61, 0x63, 0x79, 0x50, 0x72,
+	0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x45, 0x0a, 0x11, 0x70, 0x72, 0x6f,
+	0x66, 0x69, 0x6c, 0x65, 0x56, 0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x66,
+	0x69, 0x6c, 0x65, 0x56, 0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x11, 0x70,
+	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x56, 0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79,
+	0x22, 0xfb, 0x01, 0x0a, 0x0b, 0x50, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73,
+	0x12, 0x21, 0x0a, 0x05, 0x74, 0x68, 0x65, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x0b, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x54, 0x68, 0x65, 0x6d, 0x65, 0x52, 0x05, 0x74, 0x68,
+	0x65, 0x6d, 0x65, 0x12, 0x43, 0x0a, 0x0d, 0x6e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x75, 0x73, 0x65,
+	0x72, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72,
+	0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x0d, 0x6e, 0x6f, 0x74, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x36, 0x0a, 0x0c, 0x64, 0x65, 0x66, 0x61,
+	0x75, 0x6c, 0x74, 0x56, 0x69, 0x65, 0x77, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x56, 0x69, 0x65,
+	0x77, 0x73, 0x52, 0x0c, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x56, 0x69, 0x65, 0x77, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x32, 0x0a, 0x07, 0x70, 0x72,
+	0x69, 0x76, 0x61, 0x63, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x75, 0x73,
+	0x65, 0x72, 0x2e, 0x50, 0x72, 0x69, 0x76, 0x61, 0x63, 0x79, 0x50, 0x72, 0x65, 0x66, 0x65, 0x72,
+	0x65, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x07, 0x70, 0x72, 0x69, 0x76, 0x61, 0x63, 0x79, 0x22, 0x98,
+	0x02, 0x0a, 0x0a, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x20, 0x0a,
+	0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x08, 0x8a,
+	0xb5, 0x18, 0x04, 0x08, 0x01, 0x28, 0x64, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x18, 0x0a, 0x07, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x49, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x69, 0x70, 0x12, 0x1c, 0x0a, 0x09, 0x75, 0x73, 0x65, 0x72, 0x41, 0x67, 0x65,
+	0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x75, 0x73, 0x65, 0x72, 0x41, 0x67,
+	0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74,
+	0x69, 0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x41, 0x75, 0x64, 0x69,
+	0x74, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x52, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x22, 0x96, 0x01, 0x0a, 0x07, 0x4e, 0x65,
+	0x77, 0x55, 0x73, 0x65, 0x72, 0x12, 0x21, 0x0a, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x0b, 0x8a, 0xb5, 0x18, 0x07, 0x08, 0x01, 0x18, 0x01, 0x28, 0xfe,
+	0x01, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x1c, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x08, 0x8a, 0xb5, 0x18, 0x04, 0x08, 0x01, 0x28, 0x64,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x26, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x4e, 0x61,
+	0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x0a, 0x8a, 0xb5, 0x18, 0x06, 0x28, 0x20,
+	0x08, 0x01, 0x20, 0x03, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x22,
 	0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x5c, 0x0a, 0x04, 0x55, 0x73,
-	0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
-	0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08,
-	0x75, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
-	0x75, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x7c, 0x0a, 0x08, 0x45, 0x64, 0x69, 0x74,
-	0x55, 0x73, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a,
-	0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61,
-	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61,
-	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x32, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
-	0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x12, 0x21, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x4e, 0x65, 0x77,
-	0x55, 0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x2f, 0x0a, 0x0d, 0x43, 0x72,
-	0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x75,
-	0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x75, 0x73, 0x65, 0x72,
-	0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x1d, 0x0a, 0x0b, 0x52,
-	0x65, 0x61, 0x64, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x2d, 0x0a, 0x0b, 0x52, 0x65,
-	0x61, 0x64, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x75, 0x73, 0x65,
+	0x42, 0x06, 0x8a, 0xb5, 0x18, 0x02, 0x08, 0x01, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f,
+	0x72, 0x64, 0x22, 0xc6, 0x03, 0x0a, 0x04, 0x55, 0x73, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65,
+	0x6d, 0x61, 0x69, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69,
+	0x6c, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x3c, 0x0a, 0x0b, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x66, 0x74, 0x65, 0x72,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x0b, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12,
+	0x24, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x0c, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x52,
+	0x04, 0x72, 0x6f, 0x6c, 0x65, 0x12, 0x2a, 0x0a, 0x10, 0x73, 0x75, 0x73, 0x70, 0x65, 0x6e, 0x73,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x10, 0x73, 0x75, 0x73, 0x70, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x12, 0x42, 0x0a, 0x0e, 0x73, 0x75, 0x73, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x55, 0x6e,
+	0x74, 0x69, 0x6c, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0e, 0x73, 0x75, 0x73, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x64,
+	0x55, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x12, 0x27, 0x0a,
+	0x07, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d,
+	0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x07, 0x70,
+	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x33, 0x0a, 0x0b, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72,
+	0x65, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x75, 0x73,
+	0x65, 0x72, 0x2e, 0x50, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x0b,
+	0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x22, 0xc3, 0x01, 0x0a, 0x08,
+	0x45, 0x64, 0x69, 0x74, 0x55, 0x73, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x08, 0x8a, 0xb5, 0x18, 0x04, 0x08, 0x01, 0x10, 0x01, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x1f, 0x0a, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x42, 0x09, 0x8a, 0xb5, 0x18, 0x05, 0x18, 0x01, 0x28, 0xfe, 0x01, 0x52, 0x05, 0x65, 0x6d,
+	0x61, 0x69, 0x6c, 0x12, 0x1a, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x42, 0x06, 0x8a, 0xb5, 0x18, 0x02, 0x28, 0x64, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x24, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x42, 0x08, 0x8a, 0xb5, 0x18, 0x04, 0x20, 0x03, 0x28, 0x20, 0x52, 0x08, 0x75, 0x73, 0x65,
+	0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72,
+	0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72,
+	0x64, 0x12, 0x1e, 0x0a, 0x06, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x42, 0x06, 0x8a, 0xb5, 0x18, 0x02, 0x28, 0x40, 0x52, 0x06, 0x61, 0x76, 0x61, 0x74, 0x61,
+	0x72, 0x22, 0x3a, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52,
+	0x65, 0x71, 0x12, 0x29, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0d, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x4e, 0x65, 0x77, 0x55, 0x73, 0x65, 0x72, 0x42,
+	0x06, 0x8a, 0xb5, 0x18, 0x02, 0x08, 0x01, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x2f, 0x0a,
+	0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x12, 0x1e,
+	0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x75,
+	0x73, 0x65, 0x72, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x27,
+	0x0a, 0x0b, 0x52, 0x65, 0x61, 0x64, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x12, 0x18, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x08, 0x8a, 0xb5, 0x18, 0x04, 0x08,
+	0x01, 0x10, 0x01, 0x52, 0x02, 0x69, 0x64, 0x22, 0x2d, 0x0a, 0x0b, 0x52, 0x65, 0x61, 0x64, 0x55,
+	0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x55, 0x73, 0x65, 0x72,
+	0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x3d, 0x0a, 0x15, 0x52, 0x65, 0x61, 0x64, 0x55, 0x73,
+	0x65, 0x72, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x12,
+	0x24, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x42, 0x08, 0x8a, 0xb5, 0x18, 0x04, 0x28, 0x20, 0x08, 0x01, 0x52, 0x08, 0x75, 0x73, 0x65,
+	0x72, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x37, 0x0a, 0x15, 0x52, 0x65, 0x61, 0x64, 0x55, 0x73, 0x65,
+	0x72, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x12, 0x1e,
+	0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x75,
+	0x73, 0x65, 0x72, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x31,
+	0x0a, 0x11, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x61, 0x64, 0x55, 0x73, 0x65, 0x72, 0x73,
+	0x52, 0x65, 0x71, 0x12, 0x1c, 0x0a, 0x03, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09,
+	0x42, 0x0a, 0x8a, 0xb5, 0x18, 0x06, 0x08, 0x01, 0x10, 0x01, 0x30, 0x64, 0x52, 0x03, 0x69, 0x64,
+	0x73, 0x22, 0x35, 0x0a, 0x11, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x61, 0x64, 0x55, 0x73,
+	0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x55, 0x73, 0x65,
+	0x72, 0x52, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x22, 0x77, 0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x12, 0x2a, 0x0a, 0x04, 0x75, 0x73, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x45,
+	0x64, 0x69, 0x74, 0x55, 0x73, 0x65, 0x72, 0x42, 0x06, 0x8a, 0xb5, 0x18, 0x02, 0x08, 0x01, 0x52,
+	0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x3a, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d,
+	0x61, 0x73, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c,
+	0x64, 0x4d, 0x61, 0x73, 0x6b, 0x52, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x73,
+	0x6b, 0x22, 0x2f, 0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52,
+	0x65, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0a, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73,
+	0x65, 0x72, 0x22, 0x99, 0x01, 0x0a, 0x10, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f,
+	0x66, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x12, 0x18, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x42, 0x08, 0x8a, 0xb5, 0x18, 0x04, 0x10, 0x01, 0x08, 0x01, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x2f, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c,
+	0x65, 0x42, 0x06, 0x8a, 0xb5, 0x18, 0x02, 0x08, 0x01, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x66, 0x69,
+	0x6c, 0x65, 0x12, 0x3a, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x73, 0x6b,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4d, 0x61,
+	0x73, 0x6b, 0x52, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x73, 0x6b, 0x22, 0x32,
+	0x0a, 0x10, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52,
+	0x65, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0a, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73,
+	0x65, 0x72, 0x22, 0xa9, 0x01, 0x0a, 0x14, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x72, 0x65,
+	0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x12, 0x18, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x08, 0x8a, 0xb5, 0x18, 0x04, 0x08, 0x01, 0x10,
+	0x01, 0x52, 0x02, 0x69, 0x64, 0x12, 0x3b, 0x0a, 0x0b, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65,
+	0x6e, 0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x75, 0x73, 0x65,
+	0x72, 0x2e, 0x50, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x42, 0x06, 0x8a,
+	0xb5, 0x18, 0x02, 0x08, 0x01, 0x52, 0x0b, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63,
+	0x65, 0x73, 0x12, 0x3a, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x73, 0x6b,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4d, 0x61,
+	0x73, 0x6b, 0x52, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x73, 0x6b, 0x22, 0x36,
+	0x0a, 0x14, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e,
+	0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x55, 0x73, 0x65, 0x72,
+	0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x29, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x12, 0x18, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x42, 0x08, 0x8a, 0xb5, 0x18, 0x04, 0x10, 0x01, 0x08, 0x01, 0x52, 0x02, 0x69,
+	0x64, 0x22, 0x67, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52,
+	0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x3c, 0x0a, 0x0b,
+	0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x66, 0x74, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x64,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x66, 0x74, 0x65, 0x72, 0x22, 0x31, 0x0a, 0x15, 0x43, 0x61,
+	0x6e, 0x63, 0x65, 0x6c, 0x55, 0x73, 0x65, 0x72, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x71, 0x12, 0x18, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42,
+	0x08, 0x8a, 0xb5, 0x18, 0x04, 0x08, 0x01, 0x10, 0x01, 0x52, 0x02, 0x69, 0x64, 0x22, 0x37, 0x0a,
+	0x15, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x55, 0x73, 0x65, 0x72, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x55, 0x73, 0x65, 0x72,
+	0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x7f, 0x0a, 0x0e, 0x53, 0x75, 0x73, 0x70, 0x65, 0x6e,
+	0x64, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x12, 0x18, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x08, 0x8a, 0xb5, 0x18, 0x04, 0x10, 0x01, 0x08, 0x01, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x21, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x42, 0x09, 0x8a, 0xb5, 0x18, 0x05, 0x08, 0x01, 0x28, 0xf4, 0x03, 0x52, 0x06, 0x72,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x30, 0x0a, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x22, 0x30, 0x0a, 0x0e, 0x53, 0x75, 0x73, 0x70, 0x65,
+	0x6e, 0x64, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x75, 0x73, 0x65,
 	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x55,
-	0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x33, 0x0a, 0x0d, 0x55, 0x70, 0x64,
-	0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x12, 0x22, 0x0a, 0x04, 0x75, 0x73,
-	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e,
-	0x45, 0x64, 0x69, 0x74, 0x55, 0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x2f,
-	0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x12,
-	0x1e, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e,
-	0x75, 0x73, 0x65, 0x72, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22,
-	0x1f, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71,
-	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
-	0x22, 0x29, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65,
-	0x73, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22, 0x0e, 0x0a, 0x0c, 0x4c,
-	0x69, 0x73, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x22, 0x2e, 0x0a, 0x0c, 0x4c,
-	0x69, 0x73, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x75,
+	0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x2c, 0x0a, 0x10, 0x52, 0x65, 0x69,
+	0x6e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x12, 0x18, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x08, 0x8a, 0xb5, 0x18, 0x04, 0x08,
+	0x01, 0x10, 0x01, 0x52, 0x02, 0x69, 0x64, 0x22, 0x32, 0x0a, 0x10, 0x52, 0x65, 0x69, 0x6e, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x75,
 	0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x75, 0x73, 0x65, 0x72,
-	0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x32, 0x9b, 0x02, 0x0a, 0x08,
-	0x55, 0x73, 0x65, 0x72, 0x43, 0x52, 0x55, 0x44, 0x12, 0x36, 0x0a, 0x0a, 0x43, 0x72, 0x65, 0x61,
-	0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x13, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x43, 0x72,
-	0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e, 0x75, 0x73,
-	0x65, 0x72, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73,
-	0x12, 0x30, 0x0a, 0x08, 0x52, 0x65, 0x61, 0x64, 0x55, 0x73, 0x65, 0x72, 0x12, 0x11, 0x2e, 0x75,
-	0x73, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x1a,
+	0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x2d, 0x0a, 0x11, 0x45,
+	0x78, 0x70, 0x6f, 0x72, 0x74, 0x55, 0x73, 0x65, 0x72, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71,
+	0x12, 0x18, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x08, 0x8a, 0xb5,
+	0x18, 0x04, 0x08, 0x01, 0x10, 0x01, 0x52, 0x02, 0x69, 0x64, 0x22, 0x4f, 0x0a, 0x11, 0x45, 0x78,
+	0x70, 0x6f, 0x72, 0x74, 0x55, 0x73, 0x65, 0x72, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x12,
+	0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x1a, 0x0a, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x45, 0x0a, 0x13, 0x52,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52,
+	0x65, 0x71, 0x12, 0x2e, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x42, 0x06, 0x8a, 0xb5, 0x18, 0x02, 0x08, 0x01, 0x52, 0x05, 0x65, 0x76, 0x65,
+	0x6e, 0x74, 0x22, 0x15, 0x0a, 0x13, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x41, 0x75, 0x64, 0x69,
+	0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x22, 0xaa, 0x02, 0x0a, 0x12, 0x4c, 0x69,
+	0x73, 0x74, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71,
+	0x12, 0x20, 0x0a, 0x07, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x42, 0x06, 0x8a, 0xb5, 0x18, 0x02, 0x10, 0x01, 0x52, 0x07, 0x61, 0x63, 0x74, 0x6f, 0x72,
+	0x49, 0x64, 0x12, 0x22, 0x0a, 0x08, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x49, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x06, 0x8a, 0xb5, 0x18, 0x02, 0x10, 0x01, 0x52, 0x08, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x06, 0x8a, 0xb5, 0x18, 0x02, 0x10, 0x01, 0x52, 0x06,
+	0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x30,
+	0x0a, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65,
+	0x12, 0x30, 0x0a, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x75, 0x6e, 0x74,
+	0x69, 0x6c, 0x12, 0x1c, 0x0a, 0x05, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x09, 0x42, 0x06, 0x8a, 0xb5, 0x18, 0x02, 0x10, 0x01, 0x52, 0x05, 0x61, 0x66, 0x74, 0x65, 0x72,
+	0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x72, 0x73, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x66, 0x69, 0x72, 0x73, 0x74, 0x22, 0x60, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x75,
+	0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x12, 0x28, 0x0a, 0x06,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x75,
+	0x73, 0x65, 0x72, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x68, 0x61, 0x73, 0x4e, 0x65, 0x78,
+	0x74, 0x50, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x68, 0x61, 0x73,
+	0x4e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x22, 0x0f, 0x0a, 0x0d, 0x50, 0x75, 0x72, 0x67,
+	0x65, 0x55, 0x73, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x22, 0x21, 0x0a, 0x0d, 0x50, 0x75, 0x72,
+	0x67, 0x65, 0x55, 0x73, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x64,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x69, 0x64, 0x73, 0x22, 0x35, 0x0a, 0x15,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x50, 0x75, 0x72, 0x67, 0x65, 0x64, 0x55, 0x73, 0x65,
+	0x72, 0x73, 0x52, 0x65, 0x71, 0x12, 0x1c, 0x0a, 0x03, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x09, 0x42, 0x0a, 0x8a, 0xb5, 0x18, 0x06, 0x08, 0x01, 0x10, 0x01, 0x30, 0x64, 0x52, 0x03,
+	0x69, 0x64, 0x73, 0x22, 0x17, 0x0a, 0x15, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x50, 0x75,
+	0x72, 0x67, 0x65, 0x64, 0x55, 0x73, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x22, 0x6f, 0x0a, 0x13,
+	0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x12, 0x16, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42,
+	0x06, 0x8a, 0xb5, 0x18, 0x02, 0x10, 0x01, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1c, 0x0a, 0x05, 0x65,
+	0x6d, 0x61, 0x69, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x06, 0x8a, 0xb5, 0x18, 0x02,
+	0x18, 0x01, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x22, 0x0a, 0x08, 0x70, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x06, 0x8a, 0xb5, 0x18,
+	0x02, 0x08, 0x01, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x35, 0x0a,
+	0x13, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65,
+	0x72, 0x52, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x04,
+	0x75, 0x73, 0x65, 0x72, 0x22, 0x38, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x73, 0x65, 0x72,
+	0x73, 0x52, 0x65, 0x71, 0x12, 0x28, 0x0a, 0x0f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x49,
+	0x6e, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x69,
+	0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x49, 0x6e, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x22, 0x2e,
+	0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x12, 0x1e,
+	0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x75,
+	0x73, 0x65, 0x72, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x2a, 0x9b,
+	0x01, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x12, 0x53, 0x54, 0x41,
+	0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10,
+	0x00, 0x12, 0x1f, 0x0a, 0x1b, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x50, 0x45, 0x4e, 0x44,
+	0x49, 0x4e, 0x47, 0x5f, 0x56, 0x45, 0x52, 0x49, 0x46, 0x49, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e,
+	0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x41, 0x43, 0x54,
+	0x49, 0x56, 0x45, 0x10, 0x02, 0x12, 0x14, 0x0a, 0x10, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f,
+	0x53, 0x55, 0x53, 0x50, 0x45, 0x4e, 0x44, 0x45, 0x44, 0x10, 0x03, 0x12, 0x1b, 0x0a, 0x17, 0x53,
+	0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x44, 0x45,
+	0x4c, 0x45, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x04, 0x12, 0x12, 0x0a, 0x0e, 0x53, 0x54, 0x41, 0x54,
+	0x55, 0x53, 0x5f, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x05, 0x2a, 0x3b, 0x0a, 0x04,
+	0x52, 0x6f, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x10, 0x52, 0x4f, 0x4c, 0x45, 0x5f, 0x55, 0x4e, 0x53,
+	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x52, 0x4f,
+	0x4c, 0x45, 0x5f, 0x55, 0x53, 0x45, 0x52, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x52, 0x4f, 0x4c,
+	0x45, 0x5f, 0x41, 0x44, 0x4d, 0x49, 0x4e, 0x10, 0x02, 0x2a, 0x63, 0x0a, 0x0c, 0x41, 0x75, 0x64,
+	0x69, 0x74, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x19, 0x41, 0x55, 0x44,
+	0x49, 0x54, 0x5f, 0x4f, 0x55, 0x54, 0x43, 0x4f, 0x4d, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x19, 0x0a, 0x15, 0x41, 0x55, 0x44, 0x49,
+	0x54, 0x5f, 0x4f, 0x55, 0x54, 0x43, 0x4f, 0x4d, 0x45, 0x5f, 0x53, 0x55, 0x43, 0x43, 0x45, 0x53,
+	0x53, 0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x41, 0x55, 0x44, 0x49, 0x54, 0x5f, 0x4f, 0x55, 0x54,
+	0x43, 0x4f, 0x4d, 0x45, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x55, 0x52, 0x45, 0x10, 0x02, 0x2a, 0x51,
+	0x0a, 0x05, 0x54, 0x68, 0x65, 0x6d, 0x65, 0x12, 0x15, 0x0a, 0x11, 0x54, 0x48, 0x45, 0x4d, 0x45,
+	0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x10,
+	0x0a, 0x0c, 0x54, 0x48, 0x45, 0x4d, 0x45, 0x5f, 0x53, 0x59, 0x53, 0x54, 0x45, 0x4d, 0x10, 0x01,
+	0x12, 0x0f, 0x0a, 0x0b, 0x54, 0x48, 0x45, 0x4d, 0x45, 0x5f, 0x4c, 0x49, 0x47, 0x48, 0x54, 0x10,
+	0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x54, 0x48, 0x45, 0x4d, 0x45, 0x5f, 0x44, 0x41, 0x52, 0x4b, 0x10,
+	0x03, 0x2a, 0x66, 0x0a, 0x08, 0x54, 0x61, 0x73, 0x6b, 0x56, 0x69, 0x65, 0x77, 0x12, 0x19, 0x0a,
+	0x15, 0x54, 0x41, 0x53, 0x4b, 0x5f, 0x56, 0x49, 0x45, 0x57, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x54, 0x41, 0x53, 0x4b,
+	0x5f, 0x56, 0x49, 0x45, 0x57, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f,
+	0x54, 0x41, 0x53, 0x4b, 0x5f, 0x56, 0x49, 0x45, 0x57, 0x5f, 0x42, 0x4f, 0x41, 0x52, 0x44, 0x10,
+	0x02, 0x12, 0x16, 0x0a, 0x12, 0x54, 0x41, 0x53, 0x4b, 0x5f, 0x56, 0x49, 0x45, 0x57, 0x5f, 0x43,
+	0x41, 0x4c, 0x45, 0x4e, 0x44, 0x41, 0x52, 0x10, 0x03, 0x2a, 0x75, 0x0a, 0x0c, 0x43, 0x61, 0x6c,
+	0x65, 0x6e, 0x64, 0x61, 0x72, 0x56, 0x69, 0x65, 0x77, 0x12, 0x1d, 0x0a, 0x19, 0x43, 0x41, 0x4c,
+	0x45, 0x4e, 0x44, 0x41, 0x52, 0x5f, 0x56, 0x49, 0x45, 0x57, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x43, 0x41, 0x4c, 0x45,
+	0x4e, 0x44, 0x41, 0x52, 0x5f, 0x56, 0x49, 0x45, 0x57, 0x5f, 0x44, 0x41, 0x59, 0x10, 0x01, 0x12,
+	0x16, 0x0a, 0x12, 0x43, 0x41, 0x4c, 0x45, 0x4e, 0x44, 0x41, 0x52, 0x5f, 0x56, 0x49, 0x45, 0x57,
+	0x5f, 0x57, 0x45, 0x45, 0x4b, 0x10, 0x02, 0x12, 0x17, 0x0a, 0x13, 0x43, 0x41, 0x4c, 0x45, 0x4e,
+	0x44, 0x41, 0x52, 0x5f, 0x56, 0x49, 0x45, 0x57, 0x5f, 0x4d, 0x4f, 0x4e, 0x54, 0x48, 0x10, 0x03,
+	0x2a, 0x95, 0x01, 0x0a, 0x11, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x56, 0x69, 0x73, 0x69,
+	0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x22, 0x0a, 0x1e, 0x50, 0x52, 0x4f, 0x46, 0x49, 0x4c,
+	0x45, 0x5f, 0x56, 0x49, 0x53, 0x49, 0x42, 0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f, 0x55, 0x4e, 0x53,
+	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1d, 0x0a, 0x19, 0x50, 0x52,
+	0x4f, 0x46, 0x49, 0x4c, 0x45, 0x5f, 0x56, 0x49, 0x53, 0x49, 0x42, 0x49, 0x4c, 0x49, 0x54, 0x59,
+	0x5f, 0x50, 0x55, 0x42, 0x4c, 0x49, 0x43, 0x10, 0x01, 0x12, 0x1e, 0x0a, 0x1a, 0x50, 0x52, 0x4f,
+	0x46, 0x49, 0x4c, 0x45, 0x5f, 0x56, 0x49, 0x53, 0x49, 0x42, 0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f,
+	0x4d, 0x45, 0x4d, 0x42, 0x45, 0x52, 0x53, 0x10, 0x02, 0x12, 0x1d, 0x0a, 0x19, 0x50, 0x52, 0x4f,
+	0x46, 0x49, 0x4c, 0x45, 0x5f, 0x56, 0x49, 0x53, 0x49, 0x42, 0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f,
+	0x48, 0x49, 0x44, 0x44, 0x45, 0x4e, 0x10, 0x03, 0x32, 0xb8, 0x0c, 0x0a, 0x08, 0x55, 0x73, 0x65,
+	0x72, 0x43, 0x52, 0x55, 0x44, 0x12, 0x5c, 0x0a, 0x0a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x55,
+	0x73, 0x65, 0x72, 0x12, 0x13, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x22, 0x24, 0x92,
+	0xb5, 0x18, 0x09, 0x0a, 0x03, 0x61, 0x70, 0x69, 0x12, 0x02, 0x08, 0x0a, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x11, 0x3a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x09, 0x2f, 0x76, 0x31, 0x2f, 0x75, 0x73,
+	0x65, 0x72, 0x73, 0x12, 0x55, 0x0a, 0x08, 0x52, 0x65, 0x61, 0x64, 0x55, 0x73, 0x65, 0x72, 0x12,
 	0x11, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x55, 0x73, 0x65, 0x72, 0x52,
-	0x65, 0x73, 0x12, 0x36, 0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72,
-	0x12, 0x13, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73,
-	0x65, 0x72, 0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x55, 0x70, 0x64,
-	0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x12, 0x36, 0x0a, 0x0a, 0x44, 0x65,
-	0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x13, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e,
-	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e,
-	0x75, 0x73, 0x65, 0x72, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52,
-	0x65, 0x73, 0x12, 0x35, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x12,
-	0x12, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73,
-	0x52, 0x65, 0x71, 0x1a, 0x12, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x55,
-	0x73, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x30, 0x01, 0x42, 0x0d, 0x5a, 0x0b, 0x75, 0x73, 0x65,
-	0x72, 0x3b, 0x75, 0x73, 0x65, 0x72, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x71, 0x1a, 0x11, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x55, 0x73,
+	0x65, 0x72, 0x52, 0x65, 0x73, 0x22, 0x23, 0x92, 0xb5, 0x18, 0x09, 0x0a, 0x03, 0x61, 0x70, 0x69,
+	0x12, 0x02, 0x08, 0x05, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x10, 0x12, 0x0e, 0x2f, 0x76, 0x31, 0x2f,
+	0x75, 0x73, 0x65, 0x72, 0x73, 0x2f, 0x7b, 0x69, 0x64, 0x7d, 0x12, 0x5d, 0x0a, 0x12, 0x52, 0x65,
+	0x61, 0x64, 0x55, 0x73, 0x65, 0x72, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x1b, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x55, 0x73, 0x65, 0x72,
+	0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x1b, 0x2e,
+	0x75, 0x73, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x55, 0x73, 0x65, 0x72, 0x42, 0x79, 0x55,
+	0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x22, 0x0d, 0x92, 0xb5, 0x18, 0x09,
+	0x0a, 0x03, 0x61, 0x70, 0x69, 0x12, 0x02, 0x08, 0x05, 0x12, 0x51, 0x0a, 0x0e, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x65, 0x61, 0x64, 0x55, 0x73, 0x65, 0x72, 0x73, 0x12, 0x17, 0x2e, 0x75, 0x73,
+	0x65, 0x72, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x61, 0x64, 0x55, 0x73, 0x65, 0x72,
+	0x73, 0x52, 0x65, 0x71, 0x1a, 0x17, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x65, 0x61, 0x64, 0x55, 0x73, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x22, 0x0d, 0x92,
+	0xb5, 0x18, 0x09, 0x0a, 0x03, 0x61, 0x70, 0x69, 0x12, 0x02, 0x08, 0x05, 0x12, 0x66, 0x0a, 0x0a,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x13, 0x2e, 0x75, 0x73, 0x65,
+	0x72, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x1a,
+	0x13, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65,
+	0x72, 0x52, 0x65, 0x73, 0x22, 0x2e, 0x92, 0xb5, 0x18, 0x09, 0x0a, 0x03, 0x61, 0x70, 0x69, 0x12,
+	0x02, 0x08, 0x0a, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1b, 0x32, 0x13, 0x2f, 0x76, 0x31, 0x2f, 0x75,
+	0x73, 0x65, 0x72, 0x73, 0x2f, 0x7b, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x69, 0x64, 0x7d, 0x3a, 0x04,
+	0x75, 0x73, 0x65, 0x72, 0x12, 0x4e, 0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x72,
+	0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x16, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x16, 0x2e,
+	0x75, 0x73, 0x65, 0x72, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x66, 0x69,
+	0x6c, 0x65, 0x52, 0x65, 0x73, 0x22, 0x0d, 0x92, 0xb5, 0x18, 0x09, 0x0a, 0x03, 0x61, 0x70, 0x69,
+	0x12, 0x02, 0x08, 0x0a, 0x12, 0x5a, 0x0a, 0x11, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x72,
+	0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x1a, 0x2e, 0x75, 0x73, 0x65, 0x72,
+	0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63,
+	0x65, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x1a, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x50, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x22, 0x0d, 0x92, 0xb5, 0x18, 0x09, 0x0a, 0x03, 0x61, 0x70, 0x69, 0x12, 0x02, 0x08, 0x0a,
+	0x12, 0x5b, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x13,
+	0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x22, 0x23, 0x92, 0xb5, 0x18, 0x09, 0x0a, 0x03,
+	0x61, 0x70, 0x69, 0x12, 0x02, 0x08, 0x0a, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x10, 0x2a, 0x0e, 0x2f,
+	0x76, 0x31, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x73, 0x2f, 0x7b, 0x69, 0x64, 0x7d, 0x12, 0x56, 0x0a,
+	0x09, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x12, 0x12, 0x2e, 0x75, 0x73, 0x65,
+	0x72, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x12,
+	0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x52,
+	0x65, 0x73, 0x22, 0x1f, 0x92, 0xb5, 0x18, 0x0a, 0x0a, 0x03, 0x61, 0x70, 0x69, 0x12, 0x03, 0x08,
+	0xac, 0x02, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0b, 0x12, 0x09, 0x2f, 0x76, 0x31, 0x2f, 0x75, 0x73,
+	0x65, 0x72, 0x73, 0x30, 0x01, 0x12, 0x5d, 0x0a, 0x12, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x55,
+	0x73, 0x65, 0x72, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x2e, 0x75, 0x73,
+	0x65, 0x72, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x55, 0x73, 0x65, 0x72, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x1a, 0x1b, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e,
+	0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x55, 0x73, 0x65, 0x72, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x73, 0x22, 0x0d, 0x92, 0xb5, 0x18, 0x09, 0x0a, 0x03, 0x61, 0x70, 0x69,
+	0x12, 0x02, 0x08, 0x0a, 0x12, 0x54, 0x0a, 0x0e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x55, 0x73,
+	0x65, 0x72, 0x44, 0x61, 0x74, 0x61, 0x12, 0x17, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x45, 0x78,
+	0x70, 0x6f, 0x72, 0x74, 0x55, 0x73, 0x65, 0x72, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x1a,
+	0x17, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x55, 0x73, 0x65,
+	0x72, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x22, 0x0e, 0x92, 0xb5, 0x18, 0x0a, 0x0a, 0x03,
+	0x61, 0x70, 0x69, 0x12, 0x03, 0x08, 0xac, 0x02, 0x30, 0x01, 0x12, 0x46, 0x0a, 0x0a, 0x50, 0x75,
+	0x72, 0x67, 0x65, 0x55, 0x73, 0x65, 0x72, 0x73, 0x12, 0x13, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e,
+	0x50, 0x75, 0x72, 0x67, 0x65, 0x55, 0x73, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e,
+	0x75, 0x73, 0x65, 0x72, 0x2e, 0x50, 0x75, 0x72, 0x67, 0x65, 0x55, 0x73, 0x65, 0x72, 0x73, 0x52,
+	0x65, 0x73, 0x22, 0x0e, 0x92, 0xb5, 0x18, 0x0a, 0x0a, 0x03, 0x61, 0x70, 0x69, 0x12, 0x03, 0x08,
+	0xac, 0x02, 0x12, 0x5d, 0x0a, 0x12, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x50, 0x75, 0x72,
+	0x67, 0x65, 0x64, 0x55, 0x73, 0x65, 0x72, 0x73, 0x12, 0x1b, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x50, 0x75, 0x72, 0x67, 0x65, 0x64, 0x55, 0x73, 0x65,
+	0x72, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x1b, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x72, 0x6d, 0x50, 0x75, 0x72, 0x67, 0x65, 0x64, 0x55, 0x73, 0x65, 0x72, 0x73, 0x52,
+	0x65, 0x73, 0x22, 0x0d, 0x92, 0xb5, 0x18, 0x09, 0x0a, 0x03, 0x61, 0x70, 0x69, 0x12, 0x02, 0x08,
+	0x0a, 0x12, 0x48, 0x0a, 0x0b, 0x53, 0x75, 0x73, 0x70, 0x65, 0x6e, 0x64, 0x55, 0x73, 0x65, 0x72,
+	0x12, 0x14, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x53, 0x75, 0x73, 0x70, 0x65, 0x6e, 0x64, 0x55,
+	0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x1a, 0x14, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x53, 0x75,
+	0x73, 0x70, 0x65, 0x6e, 0x64, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x22, 0x0d, 0x92, 0xb5,
+	0x18, 0x09, 0x0a, 0x03, 0x61, 0x70, 0x69, 0x12, 0x02, 0x08, 0x0a, 0x12, 0x4e, 0x0a, 0x0d, 0x52,
+	0x65, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x16, 0x2e, 0x75,
+	0x73, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65,
+	0x72, 0x52, 0x65, 0x71, 0x1a, 0x16, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x69, 0x6e,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x22, 0x0d, 0x92, 0xb5,
+	0x18, 0x09, 0x12, 0x02, 0x08, 0x0a, 0x0a, 0x03, 0x61, 0x70, 0x69, 0x12, 0x57, 0x0a, 0x10, 0x41,
+	0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x12,
+	0x19, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63,
+	0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x1a, 0x19, 0x2e, 0x75, 0x73, 0x65,
+	0x72, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x55, 0x73,
+	0x65, 0x72, 0x52, 0x65, 0x73, 0x22, 0x0d, 0x92, 0xb5, 0x18, 0x09, 0x12, 0x02, 0x08, 0x05, 0x0a,
+	0x03, 0x61, 0x70, 0x69, 0x12, 0x57, 0x0a, 0x10, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x41, 0x75,
+	0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x19, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e,
+	0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x52, 0x65, 0x71, 0x1a, 0x19, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x22, 0x0d,
+	0x92, 0xb5, 0x18, 0x09, 0x12, 0x02, 0x08, 0x0a, 0x0a, 0x03, 0x61, 0x70, 0x69, 0x12, 0x54, 0x0a,
+	0x0f, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73,
+	0x12, 0x18, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x75, 0x64, 0x69,
+	0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x18, 0x2e, 0x75, 0x73, 0x65,
+	0x72, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x22, 0x0d, 0x92, 0xb5, 0x18, 0x09, 0x0a, 0x03, 0x61, 0x70, 0x69, 0x12,
+	0x02, 0x08, 0x1e, 0x42, 0x0d, 0x5a, 0x0b, 0x75, 0x73, 0x65, 0x72, 0x3b, 0x75, 0x73, 0x65, 0x72,
+	0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -813,44 +3582,148 @@ func file_user_proto_user_proto_rawDescGZIP() []byte {
 	return file_user_proto_user_proto_rawDescData
 }
 
-var file_user_proto_user_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_user_proto_user_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
+var file_user_proto_user_proto_msgTypes = make([]protoimpl.MessageInfo, 46)
 var file_user_proto_user_proto_goTypes = []interface{}{
-	(*NewUser)(nil),       // 0: user.NewUser
-	(*User)(nil),          // 1: user.User
-	(*EditUser)(nil),      // 2: user.EditUser
-	(*CreateUserReq)(nil), // 3: user.CreateUserReq
-	(*CreateUserRes)(nil), // 4: user.CreateUserRes
-	(*ReadUserReq)(nil),   // 5: user.ReadUserReq
-	(*ReadUserRes)(nil),   // 6: user.ReadUserRes
-	(*UpdateUserReq)(nil), // 7: user.UpdateUserReq
-	(*UpdateUserRes)(nil), // 8: user.UpdateUserRes
-	(*DeleteUserReq)(nil), // 9: user.DeleteUserReq
-	(*DeleteUserRes)(nil), // 10: user.DeleteUserRes
-	(*ListUsersReq)(nil),  // 11: user.ListUsersReq
-	(*ListUsersRes)(nil),  // 12: user.ListUsersRes
+	(Status)(0),                     // 0: user.Status
+	(Role)(0),                       // 1: user.Role
+	(AuditOutcome)(0),               // 2: user.AuditOutcome
+	(Theme)(0),                      // 3: user.Theme
+	(TaskView)(0),                   // 4: user.TaskView
+	(CalendarView)(0),               // 5: user.CalendarView
+	(ProfileVisibility)(0),          // 6: user.ProfileVisibility
+	(*ProfileLink)(nil),             // 7: user.ProfileLink
+	(*Profile)(nil),                 // 8: user.Profile
+	(*NotificationPreferences)(nil), // 9: user.NotificationPreferences
+	(*DefaultViews)(nil),            // 10: user.DefaultViews
+	(*PrivacyPreferences)(nil),      // 11: user.PrivacyPreferences
+	(*Preferences)(nil),             // 12: user.Preferences
+	(*AuditEvent)(nil),              // 13: user.AuditEvent
+	(*NewUser)(nil),                 // 14: user.NewUser
+	(*User)(nil),                    // 15: user.User
+	(*EditUser)(nil),                // 16: user.EditUser
+	(*CreateUserReq)(nil),           // 17: user.CreateUserReq
+	(*CreateUserRes)(nil),           // 18: user.CreateUserRes
+	(*ReadUserReq)(nil),             // 19: user.ReadUserReq
+	(*ReadUserRes)(nil),             // 20: user.ReadUserRes
+	(*ReadUserByUserNameReq)(nil),   // 21: user.ReadUserByUserNameReq
+	(*ReadUserByUserNameRes)(nil),   // 22: user.ReadUserByUserNameRes
+	(*BatchReadUsersReq)(nil),       // 23: user.BatchReadUsersReq
+	(*BatchReadUsersRes)(nil),       // 24: user.BatchReadUsersRes
+	(*UpdateUserReq)(nil),           // 25: user.UpdateUserReq
+	(*UpdateUserRes)(nil),           // 26: user.UpdateUserRes
+	(*UpdateProfileReq)(nil),        // 27: user.UpdateProfileReq
+	(*UpdateProfileRes)(nil),        // 28: user.UpdateProfileRes
+	(*UpdatePreferencesReq)(nil),    // 29: user.UpdatePreferencesReq
+	(*UpdatePreferencesRes)(nil),    // 30: user.UpdatePreferencesRes
+	(*DeleteUserReq)(nil),           // 31: user.DeleteUserReq
+	(*DeleteUserRes)(nil),           // 32: user.DeleteUserRes
+	(*CancelUserDeletionReq)(nil),   // 33: user.CancelUserDeletionReq
+	(*CancelUserDeletionRes)(nil),   // 34: user.CancelUserDeletionRes
+	(*SuspendUserReq)(nil),          // 35: user.SuspendUserReq
+	(*SuspendUserRes)(nil),          // 36: user.SuspendUserRes
+	(*ReinstateUserReq)(nil),        // 37: user.ReinstateUserReq
+	(*ReinstateUserRes)(nil),        // 38: user.ReinstateUserRes
+	(*ExportUserDataReq)(nil),       // 39: user.ExportUserDataReq
+	(*ExportUserDataRes)(nil),       // 40: user.ExportUserDataRes
+	(*RecordAuditEventReq)(nil),     // 41: user.RecordAuditEventReq
+	(*RecordAuditEventRes)(nil),     // 42: user.RecordAuditEventRes
+	(*ListAuditEventsReq)(nil),      // 43: user.ListAuditEventsReq
+	(*ListAuditEventsRes)(nil),      // 44: user.ListAuditEventsRes
+	(*PurgeUsersReq)(nil),           // 45: user.PurgeUsersReq
+	(*PurgeUsersRes)(nil),           // 46: user.PurgeUsersRes
+	(*ConfirmPurgedUsersReq)(nil),   // 47: user.ConfirmPurgedUsersReq
+	(*ConfirmPurgedUsersRes)(nil),   // 48: user.ConfirmPurgedUsersRes
+	(*AuthenticateUserReq)(nil),     // 49: user.AuthenticateUserReq
+	(*AuthenticateUserRes)(nil),     // 50: user.AuthenticateUserRes
+	(*ListUsersReq)(nil),            // 51: user.ListUsersReq
+	(*ListUsersRes)(nil),            // 52: user.ListUsersRes
+	(*timestamppb.Timestamp)(nil),   // 53: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),   // 54: google.protobuf.FieldMask
 }
 var file_user_proto_user_proto_depIdxs = []int32{
-	0,  // 0: user.CreateUserReq.user:type_name -> user.NewUser
-	1,  // 1: user.CreateUserRes.user:type_name -> user.User
-	1,  // 2: user.ReadUserRes.user:type_name -> user.User
-	2,  // 3: user.UpdateUserReq.user:type_name -> user.EditUser
-	1,  // 4: user.UpdateUserRes.user:type_name -> user.User
-	1,  // 5: user.ListUsersRes.user:type_name -> user.User
-	3,  // 6: user.UserCRUD.CreateUser:input_type -> user.CreateUserReq
-	5,  // 7: user.UserCRUD.ReadUser:input_type -> user.ReadUserReq
-	7,  // 8: user.UserCRUD.UpdateUser:input_type -> user.UpdateUserReq
-	9,  // 9: user.UserCRUD.DeleteUser:input_type -> user.DeleteUserReq
-	11, // 10: user.UserCRUD.ListUsers:input_type -> user.ListUsersReq
-	4,  // 11: user.UserCRUD.CreateUser:output_type -> user.CreateUserRes
-	6,  // 12: user.UserCRUD.ReadUser:output_type -> user.ReadUserRes
-	8,  // 13: user.UserCRUD.UpdateUser:output_type -> user.UpdateUserRes
-	10, // 14: user.UserCRUD.DeleteUser:output_type -> user.DeleteUserRes
-	12, // 15: user.UserCRUD.ListUsers:output_type -> user.ListUsersRes
-	11, // [11:16] is the sub-list for method output_type
-	6,  // [6:11] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	7,  // 0: user.Profile.links:type_name -> user.ProfileLink
+	4,  // 1: user.DefaultViews.tasks:type_name -> user.TaskView
+	5,  // 2: user.DefaultViews.calendar:type_name -> user.CalendarView
+	6,  // 3: user.PrivacyPreferences.profileVisibility:type_name -> user.ProfileVisibility
+	3,  // 4: user.Preferences.theme:type_name -> user.Theme
+	9,  // 5: user.Preferences.notifications:type_name -> user.NotificationPreferences
+	10, // 6: user.Preferences.defaultViews:type_name -> user.DefaultViews
+	11, // 7: user.Preferences.privacy:type_name -> user.PrivacyPreferences
+	53, // 8: user.AuditEvent.time:type_name -> google.protobuf.Timestamp
+	2,  // 9: user.AuditEvent.outcome:type_name -> user.AuditOutcome
+	53, // 10: user.User.deleteAfter:type_name -> google.protobuf.Timestamp
+	0,  // 11: user.User.status:type_name -> user.Status
+	1,  // 12: user.User.role:type_name -> user.Role
+	53, // 13: user.User.suspendedUntil:type_name -> google.protobuf.Timestamp
+	8,  // 14: user.User.profile:type_name -> user.Profile
+	12, // 15: user.User.preferences:type_name -> user.Preferences
+	14, // 16: user.CreateUserReq.user:type_name -> user.NewUser
+	15, // 17: user.CreateUserRes.user:type_name -> user.User
+	15, // 18: user.ReadUserRes.user:type_name -> user.User
+	15, // 19: user.ReadUserByUserNameRes.user:type_name -> user.User
+	15, // 20: user.BatchReadUsersRes.users:type_name -> user.User
+	16, // 21: user.UpdateUserReq.user:type_name -> user.EditUser
+	54, // 22: user.UpdateUserReq.updateMask:type_name -> google.protobuf.FieldMask
+	15, // 23: user.UpdateUserRes.user:type_name -> user.User
+	8,  // 24: user.UpdateProfileReq.profile:type_name -> user.Profile
+	54, // 25: user.UpdateProfileReq.updateMask:type_name -> google.protobuf.FieldMask
+	15, // 26: user.UpdateProfileRes.user:type_name -> user.User
+	12, // 27: user.UpdatePreferencesReq.preferences:type_name -> user.Preferences
+	54, // 28: user.UpdatePreferencesReq.updateMask:type_name -> google.protobuf.FieldMask
+	15, // 29: user.UpdatePreferencesRes.user:type_name -> user.User
+	53, // 30: user.DeleteUserRes.deleteAfter:type_name -> google.protobuf.Timestamp
+	15, // 31: user.CancelUserDeletionRes.user:type_name -> user.User
+	53, // 32: user.SuspendUserReq.until:type_name -> google.protobuf.Timestamp
+	15, // 33: user.SuspendUserRes.user:type_name -> user.User
+	15, // 34: user.ReinstateUserRes.user:type_name -> user.User
+	13, // 35: user.RecordAuditEventReq.event:type_name -> user.AuditEvent
+	53, // 36: user.ListAuditEventsReq.since:type_name -> google.protobuf.Timestamp
+	53, // 37: user.ListAuditEventsReq.until:type_name -> google.protobuf.Timestamp
+	13, // 38: user.ListAuditEventsRes.events:type_name -> user.AuditEvent
+	15, // 39: user.AuthenticateUserRes.user:type_name -> user.User
+	15, // 40: user.ListUsersRes.user:type_name -> user.User
+	17, // 41: user.UserCRUD.CreateUser:input_type -> user.CreateUserReq
+	19, // 42: user.UserCRUD.ReadUser:input_type -> user.ReadUserReq
+	21, // 43: user.UserCRUD.ReadUserByUserName:input_type -> user.ReadUserByUserNameReq
+	23, // 44: user.UserCRUD.BatchReadUsers:input_type -> user.BatchReadUsersReq
+	25, // 45: user.UserCRUD.UpdateUser:input_type -> user.UpdateUserReq
+	27, // 46: user.UserCRUD.UpdateProfile:input_type -> user.UpdateProfileReq
+	29, // 47: user.UserCRUD.UpdatePreferences:input_type -> user.UpdatePreferencesReq
+	31, // 48: user.UserCRUD.DeleteUser:input_type -> user.DeleteUserReq
+	51, // 49: user.UserCRUD.ListUsers:input_type -> user.ListUsersReq
+	33, // 50: user.UserCRUD.CancelUserDeletion:input_type -> user.CancelUserDeletionReq
+	39, // 51: user.UserCRUD.ExportUserData:input_type -> user.ExportUserDataReq
+	45, // 52: user.UserCRUD.PurgeUsers:input_type -> user.PurgeUsersReq
+	47, // 53: user.UserCRUD.ConfirmPurgedUsers:input_type -> user.ConfirmPurgedUsersReq
+	35, // 54: user.UserCRUD.SuspendUser:input_type -> user.SuspendUserReq
+	37, // 55: user.UserCRUD.ReinstateUser:input_type -> user.ReinstateUserReq
+	49, // 56: user.UserCRUD.AuthenticateUser:input_type -> user.AuthenticateUserReq
+	41, // 57: user.UserCRUD.RecordAuditEvent:input_type -> user.RecordAuditEventReq
+	43, // 58: user.UserCRUD.ListAuditEvents:input_type -> user.ListAuditEventsReq
+	18, // 59: user.UserCRUD.CreateUser:output_type -> user.CreateUserRes
+	20, // 60: user.UserCRUD.ReadUser:output_type -> user.ReadUserRes
+	22, // 61: user.UserCRUD.ReadUserByUserName:output_type -> user.ReadUserByUserNameRes
+	24, // 62: user.UserCRUD.BatchReadUsers:output_type -> user.BatchReadUsersRes
+	26, // 63: user.UserCRUD.UpdateUser:output_type -> user.UpdateUserRes
+	28, // 64: user.UserCRUD.UpdateProfile:output_type -> user.UpdateProfileRes
+	30, // 65: user.UserCRUD.UpdatePreferences:output_type -> user.UpdatePreferencesRes
+	32, // 66: user.UserCRUD.DeleteUser:output_type -> user.DeleteUserRes
+	52, // 67: user.UserCRUD.ListUsers:output_type -> user.ListUsersRes
+	34, // 68: user.UserCRUD.CancelUserDeletion:output_type -> user.CancelUserDeletionRes
+	40, // 69: user.UserCRUD.ExportUserData:output_type -> user.ExportUserDataRes
+	46, // 70: user.UserCRUD.PurgeUsers:output_type -> user.PurgeUsersRes
+	48, // 71: user.UserCRUD.ConfirmPurgedUsers:output_type -> user.ConfirmPurgedUsersRes
+	36, // 72: user.UserCRUD.SuspendUser:output_type -> user.SuspendUserRes
+	38, // 73: user.UserCRUD.ReinstateUser:output_type -> user.ReinstateUserRes
+	50, // 74: user.UserCRUD.AuthenticateUser:output_type -> user.AuthenticateUserRes
+	42, // 75: user.UserCRUD.RecordAuditEvent:output_type -> user.RecordAuditEventRes
+	44, // 76: user.UserCRUD.ListAuditEvents:output_type -> user.ListAuditEventsRes
+	59, // [59:77] is the sub-list for method output_type
+	41, // [41:59] is the sub-list for method input_type
+	41, // [41:41] is the sub-list for extension type_name
+	41, // [41:41] is the sub-list for extension extendee
+	0,  // [0:41] is the sub-list for field type_name
 }
 
 func init() { file_user_proto_user_proto_init() }
@@ -858,8 +3731,93 @@ func file_user_proto_user_proto_init() {
 	if File_user_proto_user_proto != nil {
 		return
 	}
+	file_user_proto_validate_proto_init()
 	if !protoimpl.UnsafeEnabled {
 		file_user_proto_user_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProfileLink); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Profile); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NotificationPreferences); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DefaultViews); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrivacyPreferences); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Preferences); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuditEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*NewUser); i {
 			case 0:
 				return &v.state
@@ -871,8 +3829,44 @@ func file_user_proto_user_proto_init() {
 				return nil
 			}
 		}
-		file_user_proto_user_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*User); i {
+		file_user_proto_user_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*User); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EditUser); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateUserReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateUserRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -883,8 +3877,8 @@ func file_user_proto_user_proto_init() {
 				return nil
 			}
 		}
-		file_user_proto_user_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*EditUser); i {
+		file_user_proto_user_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadUserReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -895,8 +3889,8 @@ func file_user_proto_user_proto_init() {
 				return nil
 			}
 		}
-		file_user_proto_user_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateUserReq); i {
+		file_user_proto_user_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadUserRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -907,8 +3901,8 @@ func file_user_proto_user_proto_init() {
 				return nil
 			}
 		}
-		file_user_proto_user_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateUserRes); i {
+		file_user_proto_user_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadUserByUserNameReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -919,8 +3913,8 @@ func file_user_proto_user_proto_init() {
 				return nil
 			}
 		}
-		file_user_proto_user_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReadUserReq); i {
+		file_user_proto_user_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadUserByUserNameRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -931,8 +3925,8 @@ func file_user_proto_user_proto_init() {
 				return nil
 			}
 		}
-		file_user_proto_user_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReadUserRes); i {
+		file_user_proto_user_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchReadUsersReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -943,7 +3937,19 @@ func file_user_proto_user_proto_init() {
 				return nil
 			}
 		}
-		file_user_proto_user_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+		file_user_proto_user_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchReadUsersRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*UpdateUserReq); i {
 			case 0:
 				return &v.state
@@ -955,7 +3961,7 @@ func file_user_proto_user_proto_init() {
 				return nil
 			}
 		}
-		file_user_proto_user_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+		file_user_proto_user_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*UpdateUserRes); i {
 			case 0:
 				return &v.state
@@ -967,7 +3973,55 @@ func file_user_proto_user_proto_init() {
 				return nil
 			}
 		}
-		file_user_proto_user_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+		file_user_proto_user_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateProfileReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateProfileRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdatePreferencesReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdatePreferencesRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DeleteUserReq); i {
 			case 0:
 				return &v.state
@@ -979,7 +4033,7 @@ func file_user_proto_user_proto_init() {
 				return nil
 			}
 		}
-		file_user_proto_user_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+		file_user_proto_user_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DeleteUserRes); i {
 			case 0:
 				return &v.state
@@ -991,7 +4045,223 @@ func file_user_proto_user_proto_init() {
 				return nil
 			}
 		}
-		file_user_proto_user_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+		file_user_proto_user_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelUserDeletionReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelUserDeletionRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SuspendUserReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SuspendUserRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReinstateUserReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReinstateUserRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportUserDataReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportUserDataRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RecordAuditEventReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RecordAuditEventRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListAuditEventsReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListAuditEventsRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PurgeUsersReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PurgeUsersRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfirmPurgedUsersReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfirmPurgedUsersRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuthenticateUserReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuthenticateUserRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_user_proto_user_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ListUsersReq); i {
 			case 0:
 				return &v.state
@@ -1003,7 +4273,7 @@ func file_user_proto_user_proto_init() {
 				return nil
 			}
 		}
-		file_user_proto_user_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+		file_user_proto_user_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ListUsersRes); i {
 			case 0:
 				return &v.state
@@ -1021,13 +4291,14 @@ func file_user_proto_user_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_user_proto_user_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   13,
+			NumEnums:      7,
+			NumMessages:   46,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_user_proto_user_proto_goTypes,
 		DependencyIndexes: file_user_proto_user_proto_depIdxs,
+		EnumInfos:         file_user_proto_user_proto_enumTypes,
 		MessageInfos:      file_user_proto_user_proto_msgTypes,
 	}.Build()
 	File_user_proto_user_proto = out.File
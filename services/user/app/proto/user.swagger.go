@@ -0,0 +1,741 @@
+// Code generated by swaggergo from user.swagger.json. DO NOT EDIT.
+
+package userpb
+
+// OpenAPIv2 is the OpenAPI v2 spec of the HTTP routes of UserCRUD, as
+// served by the REST gateway of the API.
+const OpenAPIv2 = `{
+  "swagger": "2.0",
+  "info": {
+    "title": "user/proto/user.proto",
+    "version": "version not set"
+  },
+  "consumes": [
+    "application/json"
+  ],
+  "produces": [
+    "application/json"
+  ],
+  "paths": {
+    "/v1/users": {
+      "get": {
+        "operationId": "UserCRUD_ListUsers",
+        "responses": {
+          "200": {
+            "description": "A successful response.(streaming responses)",
+            "schema": {
+              "type": "object",
+              "properties": {
+                "result": {
+                  "$ref": "#/definitions/userListUsersRes"
+                },
+                "error": {
+                  "$ref": "#/definitions/runtimeStreamError"
+                }
+              },
+              "title": "Stream result of userListUsersRes"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response.",
+            "schema": {
+              "$ref": "#/definitions/runtimeError"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "includeInactive",
+            "in": "query",
+            "required": false,
+            "type": "boolean"
+          }
+        ],
+        "tags": [
+          "UserCRUD"
+        ]
+      },
+      "post": {
+        "operationId": "UserCRUD_CreateUser",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/userCreateUserRes"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response.",
+            "schema": {
+              "$ref": "#/definitions/runtimeError"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/userNewUser"
+            }
+          }
+        ],
+        "tags": [
+          "UserCRUD"
+        ]
+      }
+    },
+    "/v1/users/{id}": {
+      "get": {
+        "operationId": "UserCRUD_ReadUser",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/userReadUserRes"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response.",
+            "schema": {
+              "$ref": "#/definitions/runtimeError"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "UserCRUD"
+        ]
+      },
+      "delete": {
+        "operationId": "UserCRUD_DeleteUser",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/userDeleteUserRes"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response.",
+            "schema": {
+              "$ref": "#/definitions/runtimeError"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          }
+        ],
+        "tags": [
+          "UserCRUD"
+        ]
+      }
+    },
+    "/v1/users/{user.id}": {
+      "patch": {
+        "operationId": "UserCRUD_UpdateUser",
+        "responses": {
+          "200": {
+            "description": "A successful response.",
+            "schema": {
+              "$ref": "#/definitions/userUpdateUserRes"
+            }
+          },
+          "default": {
+            "description": "An unexpected error response.",
+            "schema": {
+              "$ref": "#/definitions/runtimeError"
+            }
+          }
+        },
+        "parameters": [
+          {
+            "name": "user.id",
+            "in": "path",
+            "required": true,
+            "type": "string"
+          },
+          {
+            "name": "body",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/userEditUser"
+            }
+          },
+          {
+            "name": "updateMask.paths",
+            "in": "query",
+            "required": false,
+            "type": "array",
+            "items": {
+              "type": "string"
+            },
+            "collectionFormat": "multi"
+          }
+        ],
+        "tags": [
+          "UserCRUD"
+        ]
+      }
+    }
+  },
+  "definitions": {
+    "protobufAny": {
+      "type": "object",
+      "properties": {
+        "type_url": {
+          "type": "string"
+        },
+        "value": {
+          "type": "string",
+          "format": "byte"
+        }
+      }
+    },
+    "protobufFieldMask": {
+      "type": "object",
+      "properties": {
+        "paths": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "runtimeError": {
+      "type": "object",
+      "properties": {
+        "error": {
+          "type": "string"
+        },
+        "code": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "message": {
+          "type": "string"
+        },
+        "details": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/protobufAny"
+          }
+        }
+      }
+    },
+    "runtimeStreamError": {
+      "type": "object",
+      "properties": {
+        "grpc_code": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "http_code": {
+          "type": "integer",
+          "format": "int32"
+        },
+        "message": {
+          "type": "string"
+        },
+        "http_status": {
+          "type": "string"
+        },
+        "details": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/protobufAny"
+          }
+        }
+      }
+    },
+    "userAuditEvent": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "action": {
+          "type": "string"
+        },
+        "actorId": {
+          "type": "string"
+        },
+        "targetId": {
+          "type": "string"
+        },
+        "ip": {
+          "type": "string"
+        },
+        "userAgent": {
+          "type": "string"
+        },
+        "time": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "outcome": {
+          "$ref": "#/definitions/userAuditOutcome"
+        },
+        "detail": {
+          "type": "string"
+        }
+      },
+      "description": "Create a message for an entry of the security audit log.\nIMPORTANT:\n- Entries are append-only, they are never updated.\n- No \"actorId\" when nobody was authenticated, or the system acted.\n- No \"targetId\" when the action was not about a known user."
+    },
+    "userAuditOutcome": {
+      "type": "string",
+      "enum": [
+        "AUDIT_OUTCOME_UNSPECIFIED",
+        "AUDIT_OUTCOME_SUCCESS",
+        "AUDIT_OUTCOME_FAILURE"
+      ],
+      "default": "AUDIT_OUTCOME_UNSPECIFIED",
+      "description": "Whether an audited action went through."
+    },
+    "userAuthenticateUserRes": {
+      "type": "object",
+      "properties": {
+        "user": {
+          "$ref": "#/definitions/userUser"
+        }
+      },
+      "description": "No \"password\"."
+    },
+    "userBatchReadUsersRes": {
+      "type": "object",
+      "properties": {
+        "users": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/userUser"
+          }
+        }
+      },
+      "description": "No \"password\". In no particular order."
+    },
+    "userCalendarView": {
+      "type": "string",
+      "enum": [
+        "CALENDAR_VIEW_UNSPECIFIED",
+        "CALENDAR_VIEW_DAY",
+        "CALENDAR_VIEW_WEEK",
+        "CALENDAR_VIEW_MONTH"
+      ],
+      "default": "CALENDAR_VIEW_UNSPECIFIED",
+      "description": "The span of the calendar."
+    },
+    "userCancelUserDeletionRes": {
+      "type": "object",
+      "properties": {
+        "user": {
+          "$ref": "#/definitions/userUser"
+        }
+      },
+      "description": "No \"password\"."
+    },
+    "userConfirmPurgedUsersRes": {
+      "type": "object",
+      "description": "No args."
+    },
+    "userCreateUserRes": {
+      "type": "object",
+      "properties": {
+        "user": {
+          "$ref": "#/definitions/userUser"
+        }
+      },
+      "description": "No \"password\"."
+    },
+    "userDefaultViews": {
+      "type": "object",
+      "properties": {
+        "tasks": {
+          "$ref": "#/definitions/userTaskView"
+        },
+        "calendar": {
+          "$ref": "#/definitions/userCalendarView"
+        }
+      },
+      "description": "The views the app opens on."
+    },
+    "userDeleteUserRes": {
+      "type": "object",
+      "properties": {
+        "success": {
+          "type": "boolean"
+        },
+        "deleteAfter": {
+          "type": "string",
+          "format": "date-time"
+        }
+      },
+      "description": "Has the time the account will be purged."
+    },
+    "userEditUser": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "email": {
+          "type": "string"
+        },
+        "name": {
+          "type": "string"
+        },
+        "userName": {
+          "type": "string"
+        },
+        "password": {
+          "type": "string"
+        },
+        "avatar": {
+          "type": "string",
+          "description": "Only changed when named by the update mask."
+        }
+      },
+      "description": "Create a message for updating a registered user.\nIMPORTANT:\n- Has \"id\" field because the user is registered.\n- Has \"password\" field because this user is \"me\"."
+    },
+    "userExportUserDataRes": {
+      "type": "object",
+      "properties": {
+        "collection": {
+          "type": "string"
+        },
+        "document": {
+          "type": "string"
+        }
+      },
+      "description": "One stored document, as relaxed extended JSON, along with the collection\nit came from. Documents of the same collection are streamed together.\nNo \"password\"."
+    },
+    "userListAuditEventsRes": {
+      "type": "object",
+      "properties": {
+        "events": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/userAuditEvent"
+          }
+        },
+        "hasNextPage": {
+          "type": "boolean"
+        }
+      }
+    },
+    "userListUsersRes": {
+      "type": "object",
+      "properties": {
+        "user": {
+          "$ref": "#/definitions/userUser"
+        }
+      },
+      "description": "No \"password\"."
+    },
+    "userNewUser": {
+      "type": "object",
+      "properties": {
+        "email": {
+          "type": "string"
+        },
+        "name": {
+          "type": "string"
+        },
+        "userName": {
+          "type": "string"
+        },
+        "password": {
+          "type": "string"
+        }
+      },
+      "description": "Create a message for new users.\nIMPORTANT:\n- No \"id\" field because the user is new.\n- Has hashed and salted \"password\" field."
+    },
+    "userNotificationPreferences": {
+      "type": "object",
+      "properties": {
+        "securityAlerts": {
+          "type": "boolean"
+        },
+        "taskReminders": {
+          "type": "boolean"
+        },
+        "productUpdates": {
+          "type": "boolean"
+        }
+      },
+      "description": "The emails a user wants to receive."
+    },
+    "userPreferences": {
+      "type": "object",
+      "properties": {
+        "theme": {
+          "$ref": "#/definitions/userTheme"
+        },
+        "notifications": {
+          "$ref": "#/definitions/userNotificationPreferences"
+        },
+        "defaultViews": {
+          "$ref": "#/definitions/userDefaultViews"
+        },
+        "version": {
+          "type": "string",
+          "format": "int64"
+        },
+        "privacy": {
+          "$ref": "#/definitions/userPrivacyPreferences"
+        }
+      },
+      "description": "Create a message for how a user wants the app to behave.\nIMPORTANT:\n- Users who never set theirs get the defaults, at version 0.\n- \"version\" counts the times the preferences were changed."
+    },
+    "userPrivacyPreferences": {
+      "type": "object",
+      "properties": {
+        "profileVisibility": {
+          "$ref": "#/definitions/userProfileVisibility"
+        }
+      },
+      "description": "What a user shows others."
+    },
+    "userProfile": {
+      "type": "object",
+      "properties": {
+        "displayName": {
+          "type": "string"
+        },
+        "bio": {
+          "type": "string"
+        },
+        "links": {
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/userProfileLink"
+          }
+        },
+        "location": {
+          "type": "string"
+        },
+        "timezone": {
+          "type": "string"
+        },
+        "locale": {
+          "type": "string"
+        },
+        "pronouns": {
+          "type": "string"
+        },
+        "version": {
+          "type": "string",
+          "format": "int64"
+        }
+      },
+      "description": "Create a message for what a user shows others about themself.\nIMPORTANT:\n- Every field is optional.\n- \"timezone\" is an IANA time zone, such as \"Europe/Paris\".\n- \"locale\" is a BCP 47 language tag, such as \"en-US\".\n- \"version\" counts the times the profile was changed, from 0 for none."
+    },
+    "userProfileLink": {
+      "type": "object",
+      "properties": {
+        "label": {
+          "type": "string"
+        },
+        "url": {
+          "type": "string"
+        }
+      },
+      "description": "Create a message for a website of a user.\nIMPORTANT:\n- \"url\" is an absolute http or https URL."
+    },
+    "userProfileVisibility": {
+      "type": "string",
+      "enum": [
+        "PROFILE_VISIBILITY_UNSPECIFIED",
+        "PROFILE_VISIBILITY_PUBLIC",
+        "PROFILE_VISIBILITY_MEMBERS",
+        "PROFILE_VISIBILITY_HIDDEN"
+      ],
+      "default": "PROFILE_VISIBILITY_UNSPECIFIED",
+      "description": "Who may see the profile of a user, besides the user and admins."
+    },
+    "userPurgeUsersRes": {
+      "type": "object",
+      "properties": {
+        "ids": {
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      },
+      "description": "Has the ids of every purged user whose cleanup by the API is yet to be\nconfirmed with ConfirmPurgedUsers, whether purged by this call or an\nearlier one."
+    },
+    "userReadUserByUserNameRes": {
+      "type": "object",
+      "properties": {
+        "user": {
+          "$ref": "#/definitions/userUser"
+        }
+      },
+      "description": "No \"password\". The \"userName\" of the user differs from the one asked for\nwhen it was a former one."
+    },
+    "userReadUserRes": {
+      "type": "object",
+      "properties": {
+        "user": {
+          "$ref": "#/definitions/userUser"
+        }
+      },
+      "description": "No \"password\"."
+    },
+    "userRecordAuditEventRes": {
+      "type": "object",
+      "description": "No args."
+    },
+    "userReinstateUserRes": {
+      "type": "object",
+      "properties": {
+        "user": {
+          "$ref": "#/definitions/userUser"
+        }
+      },
+      "description": "No \"password\"."
+    },
+    "userRole": {
+      "type": "string",
+      "enum": [
+        "ROLE_UNSPECIFIED",
+        "ROLE_USER",
+        "ROLE_ADMIN"
+      ],
+      "default": "ROLE_UNSPECIFIED",
+      "description": "The role of an account. Roles are only ever granted in the database."
+    },
+    "userStatus": {
+      "type": "string",
+      "enum": [
+        "STATUS_UNSPECIFIED",
+        "STATUS_PENDING_VERIFICATION",
+        "STATUS_ACTIVE",
+        "STATUS_SUSPENDED",
+        "STATUS_PENDING_DELETION",
+        "STATUS_DELETED"
+      ],
+      "default": "STATUS_UNSPECIFIED",
+      "description": "The lifecycle of an account. New accounts start out active for as long as\nemail addresses are not verified."
+    },
+    "userSuspendUserRes": {
+      "type": "object",
+      "properties": {
+        "user": {
+          "$ref": "#/definitions/userUser"
+        }
+      },
+      "description": "No \"password\"."
+    },
+    "userTaskView": {
+      "type": "string",
+      "enum": [
+        "TASK_VIEW_UNSPECIFIED",
+        "TASK_VIEW_LIST",
+        "TASK_VIEW_BOARD",
+        "TASK_VIEW_CALENDAR"
+      ],
+      "default": "TASK_VIEW_UNSPECIFIED",
+      "description": "The view tasks are shown in."
+    },
+    "userTheme": {
+      "type": "string",
+      "enum": [
+        "THEME_UNSPECIFIED",
+        "THEME_SYSTEM",
+        "THEME_LIGHT",
+        "THEME_DARK"
+      ],
+      "default": "THEME_UNSPECIFIED",
+      "description": "The theme of the app. The system theme follows the settings of the device."
+    },
+    "userUpdatePreferencesRes": {
+      "type": "object",
+      "properties": {
+        "user": {
+          "$ref": "#/definitions/userUser"
+        }
+      },
+      "description": "No \"password\"."
+    },
+    "userUpdateProfileRes": {
+      "type": "object",
+      "properties": {
+        "user": {
+          "$ref": "#/definitions/userUser"
+        }
+      },
+      "description": "No \"password\"."
+    },
+    "userUpdateUserRes": {
+      "type": "object",
+      "properties": {
+        "user": {
+          "$ref": "#/definitions/userUser"
+        }
+      },
+      "description": "No \"password\"."
+    },
+    "userUser": {
+      "type": "object",
+      "properties": {
+        "id": {
+          "type": "string"
+        },
+        "email": {
+          "type": "string"
+        },
+        "name": {
+          "type": "string"
+        },
+        "userName": {
+          "type": "string"
+        },
+        "deleteAfter": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "status": {
+          "$ref": "#/definitions/userStatus"
+        },
+        "role": {
+          "$ref": "#/definitions/userRole"
+        },
+        "suspensionReason": {
+          "type": "string"
+        },
+        "suspendedUntil": {
+          "type": "string",
+          "format": "date-time"
+        },
+        "avatar": {
+          "type": "string",
+          "description": "The ID of the current avatar, whose images the API stores; empty when the\nuser has none."
+        },
+        "profile": {
+          "$ref": "#/definitions/userProfile"
+        },
+        "preferences": {
+          "$ref": "#/definitions/userPreferences"
+        }
+      },
+      "description": "Create a message for a registered user.\nIMPORTANT:\n- Has \"id\" field because the user is registered.\n- No \"password\" field because we should never return it.\n- Has \"deleteAfter\" only while the account is pending deletion.\n- Has \"suspensionReason\" and maybe \"suspendedUntil\" only while suspended."
+    }
+  }
+}
+`
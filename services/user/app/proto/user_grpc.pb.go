@@ -19,9 +19,22 @@ const _ = grpc.SupportPackageIsVersion7
 type UserCRUDClient interface {
 	CreateUser(ctx context.Context, in *CreateUserReq, opts ...grpc.CallOption) (*CreateUserRes, error)
 	ReadUser(ctx context.Context, in *ReadUserReq, opts ...grpc.CallOption) (*ReadUserRes, error)
+	ReadUserByUserName(ctx context.Context, in *ReadUserByUserNameReq, opts ...grpc.CallOption) (*ReadUserByUserNameRes, error)
+	BatchReadUsers(ctx context.Context, in *BatchReadUsersReq, opts ...grpc.CallOption) (*BatchReadUsersRes, error)
 	UpdateUser(ctx context.Context, in *UpdateUserReq, opts ...grpc.CallOption) (*UpdateUserRes, error)
+	UpdateProfile(ctx context.Context, in *UpdateProfileReq, opts ...grpc.CallOption) (*UpdateProfileRes, error)
+	UpdatePreferences(ctx context.Context, in *UpdatePreferencesReq, opts ...grpc.CallOption) (*UpdatePreferencesRes, error)
 	DeleteUser(ctx context.Context, in *DeleteUserReq, opts ...grpc.CallOption) (*DeleteUserRes, error)
 	ListUsers(ctx context.Context, in *ListUsersReq, opts ...grpc.CallOption) (UserCRUD_ListUsersClient, error)
+	CancelUserDeletion(ctx context.Context, in *CancelUserDeletionReq, opts ...grpc.CallOption) (*CancelUserDeletionRes, error)
+	ExportUserData(ctx context.Context, in *ExportUserDataReq, opts ...grpc.CallOption) (UserCRUD_ExportUserDataClient, error)
+	PurgeUsers(ctx context.Context, in *PurgeUsersReq, opts ...grpc.CallOption) (*PurgeUsersRes, error)
+	ConfirmPurgedUsers(ctx context.Context, in *ConfirmPurgedUsersReq, opts ...grpc.CallOption) (*ConfirmPurgedUsersRes, error)
+	SuspendUser(ctx context.Context, in *SuspendUserReq, opts ...grpc.CallOption) (*SuspendUserRes, error)
+	ReinstateUser(ctx context.Context, in *ReinstateUserReq, opts ...grpc.CallOption) (*ReinstateUserRes, error)
+	AuthenticateUser(ctx context.Context, in *AuthenticateUserReq, opts ...grpc.CallOption) (*AuthenticateUserRes, error)
+	RecordAuditEvent(ctx context.Context, in *RecordAuditEventReq, opts ...grpc.CallOption) (*RecordAuditEventRes, error)
+	ListAuditEvents(ctx context.Context, in *ListAuditEventsReq, opts ...grpc.CallOption) (*ListAuditEventsRes, error)
 }
 
 type userCRUDClient struct {
@@ -58,6 +71,32 @@ func (c *userCRUDClient) ReadUser(ctx context.Context, in *ReadUserReq, opts ...
 	return out, nil
 }
 
+var userCRUDReadUserByUserNameStreamDesc = &grpc.StreamDesc{
+	StreamName: "ReadUserByUserName",
+}
+
+func (c *userCRUDClient) ReadUserByUserName(ctx context.Context, in *ReadUserByUserNameReq, opts ...grpc.CallOption) (*ReadUserByUserNameRes, error) {
+	out := new(ReadUserByUserNameRes)
+	err := c.cc.Invoke(ctx, "/user.UserCRUD/ReadUserByUserName", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+var userCRUDBatchReadUsersStreamDesc = &grpc.StreamDesc{
+	StreamName: "BatchReadUsers",
+}
+
+func (c *userCRUDClient) BatchReadUsers(ctx context.Context, in *BatchReadUsersReq, opts ...grpc.CallOption) (*BatchReadUsersRes, error) {
+	out := new(BatchReadUsersRes)
+	err := c.cc.Invoke(ctx, "/user.UserCRUD/BatchReadUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 var userCRUDUpdateUserStreamDesc = &grpc.StreamDesc{
 	StreamName: "UpdateUser",
 }
@@ -71,6 +110,32 @@ func (c *userCRUDClient) UpdateUser(ctx context.Context, in *UpdateUserReq, opts
 	return out, nil
 }
 
+var userCRUDUpdateProfileStreamDesc = &grpc.StreamDesc{
+	StreamName: "UpdateProfile",
+}
+
+func (c *userCRUDClient) UpdateProfile(ctx context.Context, in *UpdateProfileReq, opts ...grpc.CallOption) (*UpdateProfileRes, error) {
+	out := new(UpdateProfileRes)
+	err := c.cc.Invoke(ctx, "/user.UserCRUD/UpdateProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+var userCRUDUpdatePreferencesStreamDesc = &grpc.StreamDesc{
+	StreamName: "UpdatePreferences",
+}
+
+func (c *userCRUDClient) UpdatePreferences(ctx context.Context, in *UpdatePreferencesReq, opts ...grpc.CallOption) (*UpdatePreferencesRes, error) {
+	out := new(UpdatePreferencesRes)
+	err := c.cc.Invoke(ctx, "/user.UserCRUD/UpdatePreferences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 var userCRUDDeleteUserStreamDesc = &grpc.StreamDesc{
 	StreamName: "DeleteUser",
 }
@@ -121,16 +186,170 @@ func (x *userCRUDListUsersClient) Recv() (*ListUsersRes, error) {
 	return m, nil
 }
 
+var userCRUDCancelUserDeletionStreamDesc = &grpc.StreamDesc{
+	StreamName: "CancelUserDeletion",
+}
+
+func (c *userCRUDClient) CancelUserDeletion(ctx context.Context, in *CancelUserDeletionReq, opts ...grpc.CallOption) (*CancelUserDeletionRes, error) {
+	out := new(CancelUserDeletionRes)
+	err := c.cc.Invoke(ctx, "/user.UserCRUD/CancelUserDeletion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+var userCRUDExportUserDataStreamDesc = &grpc.StreamDesc{
+	StreamName:    "ExportUserData",
+	ServerStreams: true,
+}
+
+func (c *userCRUDClient) ExportUserData(ctx context.Context, in *ExportUserDataReq, opts ...grpc.CallOption) (UserCRUD_ExportUserDataClient, error) {
+	stream, err := c.cc.NewStream(ctx, userCRUDExportUserDataStreamDesc, "/user.UserCRUD/ExportUserData", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &userCRUDExportUserDataClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type UserCRUD_ExportUserDataClient interface {
+	Recv() (*ExportUserDataRes, error)
+	grpc.ClientStream
+}
+
+type userCRUDExportUserDataClient struct {
+	grpc.ClientStream
+}
+
+func (x *userCRUDExportUserDataClient) Recv() (*ExportUserDataRes, error) {
+	m := new(ExportUserDataRes)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var userCRUDPurgeUsersStreamDesc = &grpc.StreamDesc{
+	StreamName: "PurgeUsers",
+}
+
+func (c *userCRUDClient) PurgeUsers(ctx context.Context, in *PurgeUsersReq, opts ...grpc.CallOption) (*PurgeUsersRes, error) {
+	out := new(PurgeUsersRes)
+	err := c.cc.Invoke(ctx, "/user.UserCRUD/PurgeUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+var userCRUDConfirmPurgedUsersStreamDesc = &grpc.StreamDesc{
+	StreamName: "ConfirmPurgedUsers",
+}
+
+func (c *userCRUDClient) ConfirmPurgedUsers(ctx context.Context, in *ConfirmPurgedUsersReq, opts ...grpc.CallOption) (*ConfirmPurgedUsersRes, error) {
+	out := new(ConfirmPurgedUsersRes)
+	err := c.cc.Invoke(ctx, "/user.UserCRUD/ConfirmPurgedUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+var userCRUDSuspendUserStreamDesc = &grpc.StreamDesc{
+	StreamName: "SuspendUser",
+}
+
+func (c *userCRUDClient) SuspendUser(ctx context.Context, in *SuspendUserReq, opts ...grpc.CallOption) (*SuspendUserRes, error) {
+	out := new(SuspendUserRes)
+	err := c.cc.Invoke(ctx, "/user.UserCRUD/SuspendUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+var userCRUDReinstateUserStreamDesc = &grpc.StreamDesc{
+	StreamName: "ReinstateUser",
+}
+
+func (c *userCRUDClient) ReinstateUser(ctx context.Context, in *ReinstateUserReq, opts ...grpc.CallOption) (*ReinstateUserRes, error) {
+	out := new(ReinstateUserRes)
+	err := c.cc.Invoke(ctx, "/user.UserCRUD/ReinstateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+var userCRUDAuthenticateUserStreamDesc = &grpc.StreamDesc{
+	StreamName: "AuthenticateUser",
+}
+
+func (c *userCRUDClient) AuthenticateUser(ctx context.Context, in *AuthenticateUserReq, opts ...grpc.CallOption) (*AuthenticateUserRes, error) {
+	out := new(AuthenticateUserRes)
+	err := c.cc.Invoke(ctx, "/user.UserCRUD/AuthenticateUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+var userCRUDRecordAuditEventStreamDesc = &grpc.StreamDesc{
+	StreamName: "RecordAuditEvent",
+}
+
+func (c *userCRUDClient) RecordAuditEvent(ctx context.Context, in *RecordAuditEventReq, opts ...grpc.CallOption) (*RecordAuditEventRes, error) {
+	out := new(RecordAuditEventRes)
+	err := c.cc.Invoke(ctx, "/user.UserCRUD/RecordAuditEvent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+var userCRUDListAuditEventsStreamDesc = &grpc.StreamDesc{
+	StreamName: "ListAuditEvents",
+}
+
+func (c *userCRUDClient) ListAuditEvents(ctx context.Context, in *ListAuditEventsReq, opts ...grpc.CallOption) (*ListAuditEventsRes, error) {
+	out := new(ListAuditEventsRes)
+	err := c.cc.Invoke(ctx, "/user.UserCRUD/ListAuditEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserCRUDService is the service API for UserCRUD service.
 // Fields should be assigned to their respective handler implementations only before
 // RegisterUserCRUDService is called.  Any unassigned fields will result in the
 // handler for that method returning an Unimplemented error.
 type UserCRUDService struct {
-	CreateUser func(context.Context, *CreateUserReq) (*CreateUserRes, error)
-	ReadUser   func(context.Context, *ReadUserReq) (*ReadUserRes, error)
-	UpdateUser func(context.Context, *UpdateUserReq) (*UpdateUserRes, error)
-	DeleteUser func(context.Context, *DeleteUserReq) (*DeleteUserRes, error)
-	ListUsers  func(*ListUsersReq, UserCRUD_ListUsersServer) error
+	CreateUser         func(context.Context, *CreateUserReq) (*CreateUserRes, error)
+	ReadUser           func(context.Context, *ReadUserReq) (*ReadUserRes, error)
+	ReadUserByUserName func(context.Context, *ReadUserByUserNameReq) (*ReadUserByUserNameRes, error)
+	BatchReadUsers     func(context.Context, *BatchReadUsersReq) (*BatchReadUsersRes, error)
+	UpdateUser         func(context.Context, *UpdateUserReq) (*UpdateUserRes, error)
+	UpdateProfile      func(context.Context, *UpdateProfileReq) (*UpdateProfileRes, error)
+	UpdatePreferences  func(context.Context, *UpdatePreferencesReq) (*UpdatePreferencesRes, error)
+	DeleteUser         func(context.Context, *DeleteUserReq) (*DeleteUserRes, error)
+	ListUsers          func(*ListUsersReq, UserCRUD_ListUsersServer) error
+	CancelUserDeletion func(context.Context, *CancelUserDeletionReq) (*CancelUserDeletionRes, error)
+	ExportUserData     func(*ExportUserDataReq, UserCRUD_ExportUserDataServer) error
+	PurgeUsers         func(context.Context, *PurgeUsersReq) (*PurgeUsersRes, error)
+	ConfirmPurgedUsers func(context.Context, *ConfirmPurgedUsersReq) (*ConfirmPurgedUsersRes, error)
+	SuspendUser        func(context.Context, *SuspendUserReq) (*SuspendUserRes, error)
+	ReinstateUser      func(context.Context, *ReinstateUserReq) (*ReinstateUserRes, error)
+	AuthenticateUser   func(context.Context, *AuthenticateUserReq) (*AuthenticateUserRes, error)
+	RecordAuditEvent   func(context.Context, *RecordAuditEventReq) (*RecordAuditEventRes, error)
+	ListAuditEvents    func(context.Context, *ListAuditEventsReq) (*ListAuditEventsRes, error)
 }
 
 func (s *UserCRUDService) createUser(_ interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
@@ -167,6 +386,40 @@ func (s *UserCRUDService) readUser(_ interface{}, ctx context.Context, dec func(
 	}
 	return interceptor(ctx, in, info, handler)
 }
+func (s *UserCRUDService) readUserByUserName(_ interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadUserByUserNameReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return s.ReadUserByUserName(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     s,
+		FullMethod: "/user.UserCRUD/ReadUserByUserName",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.ReadUserByUserName(ctx, req.(*ReadUserByUserNameReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+func (s *UserCRUDService) batchReadUsers(_ interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchReadUsersReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return s.BatchReadUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     s,
+		FullMethod: "/user.UserCRUD/BatchReadUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.BatchReadUsers(ctx, req.(*BatchReadUsersReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 func (s *UserCRUDService) updateUser(_ interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdateUserReq)
 	if err := dec(in); err != nil {
@@ -184,6 +437,40 @@ func (s *UserCRUDService) updateUser(_ interface{}, ctx context.Context, dec fun
 	}
 	return interceptor(ctx, in, info, handler)
 }
+func (s *UserCRUDService) updateProfile(_ interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProfileReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return s.UpdateProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     s,
+		FullMethod: "/user.UserCRUD/UpdateProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.UpdateProfile(ctx, req.(*UpdateProfileReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+func (s *UserCRUDService) updatePreferences(_ interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePreferencesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return s.UpdatePreferences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     s,
+		FullMethod: "/user.UserCRUD/UpdatePreferences",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.UpdatePreferences(ctx, req.(*UpdatePreferencesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 func (s *UserCRUDService) deleteUser(_ interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteUserReq)
 	if err := dec(in); err != nil {
@@ -222,6 +509,164 @@ func (x *userCRUDListUsersServer) Send(m *ListUsersRes) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func (s *UserCRUDService) cancelUserDeletion(_ interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelUserDeletionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return s.CancelUserDeletion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     s,
+		FullMethod: "/user.UserCRUD/CancelUserDeletion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.CancelUserDeletion(ctx, req.(*CancelUserDeletionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+func (s *UserCRUDService) exportUserData(_ interface{}, stream grpc.ServerStream) error {
+	m := new(ExportUserDataReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.ExportUserData(m, &userCRUDExportUserDataServer{stream})
+}
+
+type UserCRUD_ExportUserDataServer interface {
+	Send(*ExportUserDataRes) error
+	grpc.ServerStream
+}
+
+type userCRUDExportUserDataServer struct {
+	grpc.ServerStream
+}
+
+func (x *userCRUDExportUserDataServer) Send(m *ExportUserDataRes) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (s *UserCRUDService) purgeUsers(_ interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeUsersReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return s.PurgeUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     s,
+		FullMethod: "/user.UserCRUD/PurgeUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.PurgeUsers(ctx, req.(*PurgeUsersReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+func (s *UserCRUDService) confirmPurgedUsers(_ interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmPurgedUsersReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return s.ConfirmPurgedUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     s,
+		FullMethod: "/user.UserCRUD/ConfirmPurgedUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.ConfirmPurgedUsers(ctx, req.(*ConfirmPurgedUsersReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+func (s *UserCRUDService) suspendUser(_ interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuspendUserReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return s.SuspendUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     s,
+		FullMethod: "/user.UserCRUD/SuspendUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.SuspendUser(ctx, req.(*SuspendUserReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+func (s *UserCRUDService) reinstateUser(_ interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReinstateUserReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return s.ReinstateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     s,
+		FullMethod: "/user.UserCRUD/ReinstateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.ReinstateUser(ctx, req.(*ReinstateUserReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+func (s *UserCRUDService) authenticateUser(_ interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthenticateUserReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return s.AuthenticateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     s,
+		FullMethod: "/user.UserCRUD/AuthenticateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.AuthenticateUser(ctx, req.(*AuthenticateUserReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+func (s *UserCRUDService) recordAuditEvent(_ interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordAuditEventReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return s.RecordAuditEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     s,
+		FullMethod: "/user.UserCRUD/RecordAuditEvent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.RecordAuditEvent(ctx, req.(*RecordAuditEventReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+func (s *UserCRUDService) listAuditEvents(_ interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditEventsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return s.ListAuditEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     s,
+		FullMethod: "/user.UserCRUD/ListAuditEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.ListAuditEvents(ctx, req.(*ListAuditEventsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // RegisterUserCRUDService registers a service implementation with a gRPC server.
 func RegisterUserCRUDService(s grpc.ServiceRegistrar, srv *UserCRUDService) {
 	srvCopy := *srv
@@ -235,11 +680,31 @@ func RegisterUserCRUDService(s grpc.ServiceRegistrar, srv *UserCRUDService) {
 			return nil, status.Errorf(codes.Unimplemented, "method ReadUser not implemented")
 		}
 	}
+	if srvCopy.ReadUserByUserName == nil {
+		srvCopy.ReadUserByUserName = func(context.Context, *ReadUserByUserNameReq) (*ReadUserByUserNameRes, error) {
+			return nil, status.Errorf(codes.Unimplemented, "method ReadUserByUserName not implemented")
+		}
+	}
+	if srvCopy.BatchReadUsers == nil {
+		srvCopy.BatchReadUsers = func(context.Context, *BatchReadUsersReq) (*BatchReadUsersRes, error) {
+			return nil, status.Errorf(codes.Unimplemented, "method BatchReadUsers not implemented")
+		}
+	}
 	if srvCopy.UpdateUser == nil {
 		srvCopy.UpdateUser = func(context.Context, *UpdateUserReq) (*UpdateUserRes, error) {
 			return nil, status.Errorf(codes.Unimplemented, "method UpdateUser not implemented")
 		}
 	}
+	if srvCopy.UpdateProfile == nil {
+		srvCopy.UpdateProfile = func(context.Context, *UpdateProfileReq) (*UpdateProfileRes, error) {
+			return nil, status.Errorf(codes.Unimplemented, "method UpdateProfile not implemented")
+		}
+	}
+	if srvCopy.UpdatePreferences == nil {
+		srvCopy.UpdatePreferences = func(context.Context, *UpdatePreferencesReq) (*UpdatePreferencesRes, error) {
+			return nil, status.Errorf(codes.Unimplemented, "method UpdatePreferences not implemented")
+		}
+	}
 	if srvCopy.DeleteUser == nil {
 		srvCopy.DeleteUser = func(context.Context, *DeleteUserReq) (*DeleteUserRes, error) {
 			return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
@@ -250,6 +715,51 @@ func RegisterUserCRUDService(s grpc.ServiceRegistrar, srv *UserCRUDService) {
 			return status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
 		}
 	}
+	if srvCopy.CancelUserDeletion == nil {
+		srvCopy.CancelUserDeletion = func(context.Context, *CancelUserDeletionReq) (*CancelUserDeletionRes, error) {
+			return nil, status.Errorf(codes.Unimplemented, "method CancelUserDeletion not implemented")
+		}
+	}
+	if srvCopy.ExportUserData == nil {
+		srvCopy.ExportUserData = func(*ExportUserDataReq, UserCRUD_ExportUserDataServer) error {
+			return status.Errorf(codes.Unimplemented, "method ExportUserData not implemented")
+		}
+	}
+	if srvCopy.PurgeUsers == nil {
+		srvCopy.PurgeUsers = func(context.Context, *PurgeUsersReq) (*PurgeUsersRes, error) {
+			return nil, status.Errorf(codes.Unimplemented, "method PurgeUsers not implemented")
+		}
+	}
+	if srvCopy.ConfirmPurgedUsers == nil {
+		srvCopy.ConfirmPurgedUsers = func(context.Context, *ConfirmPurgedUsersReq) (*ConfirmPurgedUsersRes, error) {
+			return nil, status.Errorf(codes.Unimplemented, "method ConfirmPurgedUsers not implemented")
+		}
+	}
+	if srvCopy.SuspendUser == nil {
+		srvCopy.SuspendUser = func(context.Context, *SuspendUserReq) (*SuspendUserRes, error) {
+			return nil, status.Errorf(codes.Unimplemented, "method SuspendUser not implemented")
+		}
+	}
+	if srvCopy.ReinstateUser == nil {
+		srvCopy.ReinstateUser = func(context.Context, *ReinstateUserReq) (*ReinstateUserRes, error) {
+			return nil, status.Errorf(codes.Unimplemented, "method ReinstateUser not implemented")
+		}
+	}
+	if srvCopy.AuthenticateUser == nil {
+		srvCopy.AuthenticateUser = func(context.Context, *AuthenticateUserReq) (*AuthenticateUserRes, error) {
+			return nil, status.Errorf(codes.Unimplemented, "method AuthenticateUser not implemented")
+		}
+	}
+	if srvCopy.RecordAuditEvent == nil {
+		srvCopy.RecordAuditEvent = func(context.Context, *RecordAuditEventReq) (*RecordAuditEventRes, error) {
+			return nil, status.Errorf(codes.Unimplemented, "method RecordAuditEvent not implemented")
+		}
+	}
+	if srvCopy.ListAuditEvents == nil {
+		srvCopy.ListAuditEvents = func(context.Context, *ListAuditEventsReq) (*ListAuditEventsRes, error) {
+			return nil, status.Errorf(codes.Unimplemented, "method ListAuditEvents not implemented")
+		}
+	}
 	sd := grpc.ServiceDesc{
 		ServiceName: "user.UserCRUD",
 		Methods: []grpc.MethodDesc{
@@ -261,14 +771,62 @@ func RegisterUserCRUDService(s grpc.ServiceRegistrar, srv *UserCRUDService) {
 				MethodName: "ReadUser",
 				Handler:    srvCopy.readUser,
 			},
+			{
+				MethodName: "ReadUserByUserName",
+				Handler:    srvCopy.readUserByUserName,
+			},
+			{
+				MethodName: "BatchReadUsers",
+				Handler:    srvCopy.batchReadUsers,
+			},
 			{
 				MethodName: "UpdateUser",
 				Handler:    srvCopy.updateUser,
 			},
+			{
+				MethodName: "UpdateProfile",
+				Handler:    srvCopy.updateProfile,
+			},
+			{
+				MethodName: "UpdatePreferences",
+				Handler:    srvCopy.updatePreferences,
+			},
 			{
 				MethodName: "DeleteUser",
 				Handler:    srvCopy.deleteUser,
 			},
+			{
+				MethodName: "CancelUserDeletion",
+				Handler:    srvCopy.cancelUserDeletion,
+			},
+			{
+				MethodName: "PurgeUsers",
+				Handler:    srvCopy.purgeUsers,
+			},
+			{
+				MethodName: "ConfirmPurgedUsers",
+				Handler:    srvCopy.confirmPurgedUsers,
+			},
+			{
+				MethodName: "SuspendUser",
+				Handler:    srvCopy.suspendUser,
+			},
+			{
+				MethodName: "ReinstateUser",
+				Handler:    srvCopy.reinstateUser,
+			},
+			{
+				MethodName: "AuthenticateUser",
+				Handler:    srvCopy.authenticateUser,
+			},
+			{
+				MethodName: "RecordAuditEvent",
+				Handler:    srvCopy.recordAuditEvent,
+			},
+			{
+				MethodName: "ListAuditEvents",
+				Handler:    srvCopy.listAuditEvents,
+			},
 		},
 		Streams: []grpc.StreamDesc{
 			{
@@ -276,9 +834,37 @@ func RegisterUserCRUDService(s grpc.ServiceRegistrar, srv *UserCRUDService) {
 				Handler:       srvCopy.listUsers,
 				ServerStreams: true,
 			},
+			{
+				StreamName:    "ExportUserData",
+				Handler:       srvCopy.exportUserData,
+				ServerStreams: true,
+			},
 		},
 		Metadata: "user/proto/user.proto",
 	}
 
 	s.RegisterService(&sd, nil)
 }
+
+// UserCRUDServer is the server API for UserCRUD service, as implemented by a single
+// value rather than by the fields of UserCRUDService.
+type UserCRUDServer interface {
+	CreateUser(context.Context, *CreateUserReq) (*CreateUserRes, error)
+	ReadUser(context.Context, *ReadUserReq) (*ReadUserRes, error)
+	ReadUserByUserName(context.Context, *ReadUserByUserNameReq) (*ReadUserByUserNameRes, error)
+	BatchReadUsers(context.Context, *BatchReadUsersReq) (*BatchReadUsersRes, error)
+	UpdateUser(context.Context, *UpdateUserReq) (*UpdateUserRes, error)
+	UpdateProfile(context.Context, *UpdateProfileReq) (*UpdateProfileRes, error)
+	UpdatePreferences(context.Context, *UpdatePreferencesReq) (*UpdatePreferencesRes, error)
+	DeleteUser(context.Context, *DeleteUserReq) (*DeleteUserRes, error)
+	ListUsers(*ListUsersReq, UserCRUD_ListUsersServer) error
+	CancelUserDeletion(context.Context, *CancelUserDeletionReq) (*CancelUserDeletionRes, error)
+	ExportUserData(*ExportUserDataReq, UserCRUD_ExportUserDataServer) error
+	PurgeUsers(context.Context, *PurgeUsersReq) (*PurgeUsersRes, error)
+	ConfirmPurgedUsers(context.Context, *ConfirmPurgedUsersReq) (*ConfirmPurgedUsersRes, error)
+	SuspendUser(context.Context, *SuspendUserReq) (*SuspendUserRes, error)
+	ReinstateUser(context.Context, *ReinstateUserReq) (*ReinstateUserRes, error)
+	AuthenticateUser(context.Context, *AuthenticateUserReq) (*AuthenticateUserRes, error)
+	RecordAuditEvent(context.Context, *RecordAuditEventReq) (*RecordAuditEventRes, error)
+	ListAuditEvents(context.Context, *ListAuditEventsReq) (*ListAuditEventsRes, error)
+}
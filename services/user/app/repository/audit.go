@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Outcomes of an audited action, as stored in the "outcome" field.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// AuditEvent is the struct used for entries of the security audit log.
+type AuditEvent struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Action    string             `bson:"action"`
+	ActorID   string             `bson:"actorId,omitempty"`
+	TargetID  string             `bson:"targetId,omitempty"`
+	IP        string             `bson:"ip,omitempty"`
+	UserAgent string             `bson:"userAgent,omitempty"`
+	Time      time.Time          `bson:"time"`
+	Outcome   string             `bson:"outcome"`
+	Detail    string             `bson:"detail,omitempty"`
+}
+
+// AuditQuery pages through the audit log, newest first. Every filter that is
+// set must match.
+type AuditQuery struct {
+	ActorID  string
+	TargetID string
+	// UserID matches the events whose actor or target is that user.
+	UserID string
+	Action string
+	Since  *time.Time
+	Until  *time.Time
+	// After is the ID of the last event of the previous page.
+	After primitive.ObjectID
+	// Limit is the size of the page; zero lists every event.
+	Limit int
+}
+
+// AuditRepository stores the security audit log. Events are only ever
+// appended, and expire after the retention period of the store.
+type AuditRepository interface {
+	// Insert appends e to the log.
+	Insert(ctx context.Context, e *AuditEvent) error
+	// List returns a page of events, and whether there are more.
+	List(ctx context.Context, q AuditQuery) ([]*AuditEvent, bool, error)
+}
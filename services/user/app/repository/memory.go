@@ -0,0 +1,472 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryUserRepository stores user accounts in memory, with the semantics of
+// MongoUserRepository. It is meant for tests and local development.
+type MemoryUserRepository struct {
+	mu       sync.Mutex
+	accounts map[primitive.ObjectID]*EditUserAccount
+	purged   map[primitive.ObjectID]bool
+}
+
+// NewMemoryUserRepository returns an empty repository.
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{
+		accounts: map[primitive.ObjectID]*EditUserAccount{},
+		purged:   map[primitive.ObjectID]bool{},
+	}
+}
+
+// storedTime rounds t the way MongoDB stores dates.
+func storedTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Millisecond)
+}
+
+func storedTimePtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	stored := storedTime(*t)
+	return &stored
+}
+
+func copySuspension(s *Suspension) *Suspension {
+	if s == nil {
+		return nil
+	}
+
+	return &Suspension{
+		Reason: s.Reason,
+		Until:  storedTimePtr(s.Until),
+		At:     storedTime(s.At),
+	}
+}
+
+func copyProfile(p *Profile) *Profile {
+	if p == nil {
+		return nil
+	}
+
+	c := *p
+	c.Links = nil
+
+	// An empty list of links is left out when stored, like in MongoDB.
+	if len(p.Links) > 0 {
+		c.Links = append([]ProfileLink{}, p.Links...)
+	}
+
+	return &c
+}
+
+func copyPreferences(p *Preferences) *Preferences {
+	if p == nil {
+		return nil
+	}
+
+	c := *p
+	return &c
+}
+
+func copyAccount(data *EditUserAccount) *EditUserAccount {
+	c := *data
+	c.Suspension = copySuspension(data.Suspension)
+	c.DeleteAfter = storedTimePtr(data.DeleteAfter)
+	c.Profile = copyProfile(data.Profile)
+	c.Preferences = copyPreferences(data.Preferences)
+	c.FormerUserNames = nil
+
+	if len(data.FormerUserNames) > 0 {
+		c.FormerUserNames = append([]FormerUserName{}, data.FormerUserNames...)
+	}
+
+	return &c
+}
+
+// statusOf is the stored status of an account, where none counts as active.
+func statusOf(data *EditUserAccount) string {
+	if data.Status == "" {
+		return StatusActive
+	}
+
+	return data.Status
+}
+
+// duplicate returns the field that another account than id shares with data.
+func (r *MemoryUserRepository) duplicate(id primitive.ObjectID, email string, userName string) error {
+	for other, data := range r.accounts {
+		if other == id {
+			continue
+		}
+		if data.Email == email {
+			return &DuplicateError{Field: FieldEmail}
+		}
+		if strings.EqualFold(data.UserName, userName) {
+			return &DuplicateError{Field: FieldUserName}
+		}
+	}
+
+	return nil
+}
+
+// Create stores a new account and returns its ID.
+func (r *MemoryUserRepository) Create(ctx context.Context, data *NewUserAccount) (primitive.ObjectID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := r.duplicate(primitive.NilObjectID, data.Email, data.UserName)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	id := primitive.NewObjectID()
+
+	r.accounts[id] = &EditUserAccount{
+		ID:       id,
+		Email:    data.Email,
+		Name:     data.Name,
+		UserName: data.UserName,
+		Password: data.Password,
+		Status:   data.Status,
+		Role:     data.Role,
+	}
+
+	return id, nil
+}
+
+// Get returns the account with the given ID.
+func (r *MemoryUserRepository) Get(ctx context.Context, id primitive.ObjectID) (*UserAccount, error) {
+	data, err := r.GetWithPassword(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.UserAccount(), nil
+}
+
+// GetMany returns the accounts with the given IDs, in no particular order.
+// Unknown IDs are left out.
+func (r *MemoryUserRepository) GetMany(ctx context.Context, ids []primitive.ObjectID) ([]*UserAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	accounts := []*UserAccount{}
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+
+	for _, id := range ids {
+		data, ok := r.accounts[id]
+		if !ok || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		accounts = append(accounts, copyAccount(data).UserAccount())
+	}
+
+	return accounts, nil
+}
+
+// GetByUserName returns the account with the given user name or, when none
+// has it, the account that had it until a change made since formerSince.
+func (r *MemoryUserRepository) GetByUserName(ctx context.Context, userName string, formerSince time.Time) (*UserAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, data := range r.accounts {
+		if strings.EqualFold(data.UserName, userName) {
+			return copyAccount(data).UserAccount(), nil
+		}
+	}
+
+	for _, data := range r.accounts {
+		for _, former := range data.FormerUserNames {
+			if strings.EqualFold(former.UserName, userName) && !former.ChangedAt.Before(formerSince) {
+				return copyAccount(data).UserAccount(), nil
+			}
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// GetWithPassword returns the account with the given ID, password included.
+func (r *MemoryUserRepository) GetWithPassword(ctx context.Context, id primitive.ObjectID) (*EditUserAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, ok := r.accounts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return copyAccount(data), nil
+}
+
+// GetByEmailWithPassword returns the account with the given email, password
+// included.
+func (r *MemoryUserRepository) GetByEmailWithPassword(ctx context.Context, email string) (*EditUserAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, data := range r.accounts {
+		if data.Email == email {
+			return copyAccount(data), nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// Update copies the fields named by mask, or every updatable field when mask
+// is empty, from data to the account with the given ID. A change of the user
+// name adds the former one to FormerUserNames.
+func (r *MemoryUserRepository) Update(ctx context.Context, id primitive.ObjectID, data *EditUserAccount, mask []string) (*UserAccount, error) {
+	values, err := maskedValues(data, mask)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.accounts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	updated := copyAccount(current)
+
+	for field, value := range values {
+		switch field {
+		case FieldEmail:
+			updated.Email = value
+		case FieldName:
+			updated.Name = value
+		case FieldUserName:
+			if value != current.UserName {
+				updated.FormerUserNames = append(updated.FormerUserNames, FormerUserName{UserName: current.UserName, ChangedAt: storedTime(time.Now())})
+			}
+			updated.UserName = value
+		case FieldPassword:
+			updated.Password = value
+		case FieldAvatar:
+			updated.Avatar = value
+		}
+	}
+
+	err = r.duplicate(id, updated.Email, updated.UserName)
+	if err != nil {
+		return nil, err
+	}
+
+	r.accounts[id] = updated
+
+	return copyAccount(updated).UserAccount(), nil
+}
+
+// Transition applies change to the account with the given ID, provided its
+// status is one of from.
+func (r *MemoryUserRepository) Transition(ctx context.Context, id primitive.ObjectID, from []string, change StatusChange) (*UserAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.accounts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	allowed := false
+	for _, s := range from {
+		if s == statusOf(current) {
+			allowed = true
+		}
+	}
+
+	if !allowed {
+		data := copyAccount(current).UserAccount()
+		return data, &TransitionError{Status: EffectiveStatus(data)}
+	}
+
+	updated := copyAccount(current)
+
+	if change.Status != "" {
+		updated.Status = change.Status
+	} else {
+		updated.Status = current.PreviousStatus
+		if updated.Status == "" {
+			updated.Status = StatusActive
+		}
+		updated.PreviousStatus = ""
+	}
+
+	if change.KeepStatus && statusOf(current) != change.Status {
+		updated.PreviousStatus = statusOf(current)
+	}
+
+	updated.DeleteAfter = storedTimePtr(change.DeleteAfter)
+	updated.Suspension = copySuspension(change.Suspension)
+
+	r.accounts[id] = updated
+
+	return copyAccount(updated).UserAccount(), nil
+}
+
+// SetProfile replaces the profile of the account with the given ID, provided
+// it is still at version.
+func (r *MemoryUserRepository) SetProfile(ctx context.Context, id primitive.ObjectID, profile *Profile, version int64) (*UserAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.accounts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	if profileVersion(current.Profile) != version {
+		data := copyAccount(current).UserAccount()
+		return data, &VersionError{Field: "profile", Current: profileVersion(current.Profile)}
+	}
+
+	updated := copyAccount(current)
+	updated.Profile = copyProfile(profile)
+	updated.Profile.Version = version + 1
+
+	r.accounts[id] = updated
+
+	return copyAccount(updated).UserAccount(), nil
+}
+
+// SetPreferences replaces the preferences of the account with the given ID,
+// provided they are still at version.
+func (r *MemoryUserRepository) SetPreferences(ctx context.Context, id primitive.ObjectID, prefs *Preferences, version int64) (*UserAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.accounts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	if preferencesVersion(current.Preferences) != version {
+		data := copyAccount(current).UserAccount()
+		return data, &VersionError{Field: "preferences", Current: preferencesVersion(current.Preferences)}
+	}
+
+	updated := copyAccount(current)
+	updated.Preferences = copyPreferences(prefs)
+	updated.Preferences.Version = version + 1
+
+	r.accounts[id] = updated
+
+	return copyAccount(updated).UserAccount(), nil
+}
+
+// active tells whether data may use their account, including when their
+// suspension has run out but they have not been seen since.
+func active(data *EditUserAccount) bool {
+	return EffectiveStatus(data.UserAccount()) == StatusActive
+}
+
+// List returns a page of accounts.
+func (r *MemoryUserRepository) List(ctx context.Context, opts ListOptions) ([]*UserAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	accounts := []*UserAccount{}
+
+	for id, data := range r.accounts {
+		if !opts.IncludeInactive && !active(data) {
+			continue
+		}
+		if !opts.After.IsZero() && bytes.Compare(id[:], opts.After[:]) <= 0 {
+			continue
+		}
+
+		accounts = append(accounts, copyAccount(data).UserAccount())
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		return bytes.Compare(accounts[i].ID[:], accounts[j].ID[:]) < 0
+	})
+
+	if opts.Limit > 0 && len(accounts) > opts.Limit {
+		accounts = accounts[:opts.Limit]
+	}
+
+	return accounts, nil
+}
+
+// Purge removes every account pending deletion since before now and returns
+// their IDs. No tombstone is needed, as IDs are never reused in memory.
+func (r *MemoryUserRepository) Purge(ctx context.Context, now time.Time) ([]primitive.ObjectID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ids := []primitive.ObjectID{}
+
+	for id, data := range r.accounts {
+		if data.Status != StatusPendingDeletion || data.DeleteAfter == nil || data.DeleteAfter.After(now) {
+			continue
+		}
+
+		delete(r.accounts, id)
+		r.purged[id] = true
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// PendingCleanup returns the IDs of the purged accounts whose cleanup is yet
+// to be confirmed, in no particular order.
+func (r *MemoryUserRepository) PendingCleanup(ctx context.Context) ([]primitive.ObjectID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ids := []primitive.ObjectID{}
+
+	for id := range r.purged {
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// ConfirmCleanup forgets the purged accounts with the given IDs.
+func (r *MemoryUserRepository) ConfirmCleanup(ctx context.Context, ids []primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range ids {
+		delete(r.purged, id)
+	}
+
+	return nil
+}
+
+// Export calls send with the account with the given ID, as a document of the
+// "users" collection. Nothing else is owned in memory.
+func (r *MemoryUserRepository) Export(ctx context.Context, id primitive.ObjectID, send func(collection string, document []byte) error) error {
+	data, err := r.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	document, err := bson.MarshalExtJSON(data, false, false)
+	if err != nil {
+		return err
+	}
+
+	return send("users", document)
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryAuditRepository stores the audit log in memory, with the semantics of
+// MongoAuditRepository save for retention: events never expire.
+type MemoryAuditRepository struct {
+	mu     sync.Mutex
+	events []*AuditEvent
+}
+
+// NewMemoryAuditRepository returns an empty audit log.
+func NewMemoryAuditRepository() *MemoryAuditRepository {
+	return &MemoryAuditRepository{}
+}
+
+// Insert appends e to the log.
+func (r *MemoryAuditRepository) Insert(ctx context.Context, e *AuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *e
+	if stored.ID.IsZero() {
+		stored.ID = primitive.NewObjectID()
+	}
+	stored.Time = storedTime(e.Time)
+
+	r.events = append(r.events, &stored)
+
+	return nil
+}
+
+func (q *AuditQuery) matches(e *AuditEvent) bool {
+	switch {
+	case q.ActorID != "" && e.ActorID != q.ActorID:
+		return false
+	case q.TargetID != "" && e.TargetID != q.TargetID:
+		return false
+	case q.UserID != "" && e.ActorID != q.UserID && e.TargetID != q.UserID:
+		return false
+	case q.Action != "" && e.Action != q.Action:
+		return false
+	case q.Since != nil && e.Time.Before(*q.Since):
+		return false
+	case q.Until != nil && !e.Time.Before(*q.Until):
+		return false
+	case !q.After.IsZero() && bytes.Compare(e.ID[:], q.After[:]) >= 0:
+		return false
+	}
+
+	return true
+}
+
+// List returns a page of events, and whether there are more.
+func (r *MemoryAuditRepository) List(ctx context.Context, q AuditQuery) ([]*AuditEvent, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := []*AuditEvent{}
+
+	for _, e := range r.events {
+		if q.matches(e) {
+			c := *e
+			events = append(events, &c)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return bytes.Compare(events[i].ID[:], events[j].ID[:]) > 0
+	})
+
+	if q.Limit > 0 && len(events) > q.Limit {
+		return events[:q.Limit], true, nil
+	}
+
+	return events, false, nil
+}
@@ -0,0 +1,20 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/allen-woods/the-supertask/services/user/repository"
+	"github.com/allen-woods/the-supertask/services/user/repository/repotest"
+)
+
+func TestMemoryUserRepository(t *testing.T) {
+	repotest.TestUserRepository(t, func(t *testing.T) repository.UserRepository {
+		return repository.NewMemoryUserRepository()
+	})
+}
+
+func TestMemoryAuditRepository(t *testing.T) {
+	repotest.TestAuditRepository(t, func(t *testing.T) repository.AuditRepository {
+		return repository.NewMemoryAuditRepository()
+	})
+}
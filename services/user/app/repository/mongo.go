@@ -0,0 +1,640 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// duplicateKey is the MongoDB error code for a unique index violation.
+const duplicateKey = 11000
+
+// MongoDB error codes of the indexes and collections that do not exist.
+const (
+	namespaceNotFound = 26
+	indexNotFound     = 27
+)
+
+// Names of the unique indexes, by the field they cover.
+var uniqueIndexes = map[string]string{
+	FieldEmail:    "email_unique",
+	FieldUserName: "userName_unique_ci",
+}
+
+// userNameCollation compares user names regardless of their case, so that
+// Alice and alice are one and the same user. Queries on user names must use
+// it, or they neither use the indexes nor match the same users.
+var userNameCollation = &options.Collation{Locale: "en", Strength: 2}
+
+// MongoUserRepository stores user accounts in a MongoDB collection.
+type MongoUserRepository struct {
+	users *mongo.Collection
+	owned []*mongo.Collection
+}
+
+// NewMongoUserRepository stores user accounts in users, and creates its
+// indexes. owned are the collections holding documents that belong to a user
+// through their "ownerId" field; they are exported and purged along with the
+// user.
+func NewMongoUserRepository(ctx context.Context, users *mongo.Collection, owned ...*mongo.Collection) (*MongoUserRepository, error) {
+	// User names used to be unique along with their case. Accounts whose
+	// names only differ by it must be renamed before the index replacing
+	// this one can be created.
+	_, err := users.Indexes().DropOne(ctx, "userName_unique")
+	if e, ok := err.(mongo.CommandError); ok && (e.Code == indexNotFound || e.Code == namespaceNotFound) {
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	models := []mongo.IndexModel{}
+
+	// Tombstones have neither an email nor a user name, so the indexes
+	// leave them out rather than count them as duplicates of each other.
+	for field, name := range uniqueIndexes {
+		opts := options.Index().
+			SetName(name).
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{field: bson.M{"$exists": true}})
+		if field == FieldUserName {
+			opts.SetCollation(userNameCollation)
+		}
+
+		models = append(models, mongo.IndexModel{Keys: bson.M{field: 1}, Options: opts})
+	}
+
+	// Only the few tombstones pending cleanup are indexed.
+	models = append(models, mongo.IndexModel{
+		Keys: bson.M{"cleanupPending": 1},
+		Options: options.Index().
+			SetName("cleanupPending").
+			SetPartialFilterExpression(bson.M{"cleanupPending": true}),
+	})
+
+	// Former user names are looked up by GetByUserName, and shared by
+	// accounts once the names are free again.
+	models = append(models, mongo.IndexModel{
+		Keys:    bson.M{"formerUserNames.userName": 1},
+		Options: options.Index().SetName("formerUserNames_userName_ci").SetCollation(userNameCollation),
+	})
+
+	_, err = users.Indexes().CreateMany(ctx, models)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoUserRepository{users: users, owned: owned}, nil
+}
+
+// statusIn matches the user documents having one of the given statuses.
+// Documents written before statuses existed have none and count as active.
+func statusIn(statuses ...string) bson.M {
+	for _, s := range statuses {
+		if s == StatusActive {
+			return bson.M{"$or": bson.A{
+				bson.M{"status": bson.M{"$in": statuses}},
+				bson.M{"status": bson.M{"$exists": false}},
+			}}
+		}
+	}
+
+	return bson.M{"status": bson.M{"$in": statuses}}
+}
+
+// activeFilter matches the users who may use their account, including those
+// who were active before a suspension that has run out but who have not been
+// seen since.
+func activeFilter() bson.M {
+	return bson.M{"$or": bson.A{
+		statusIn(StatusActive),
+		bson.M{
+			"status":           StatusSuspended,
+			"suspension.until": bson.M{"$lte": time.Now().UTC()},
+			"previousStatus":   bson.M{"$in": bson.A{StatusActive, nil}},
+		},
+	}}
+}
+
+func notDeleted(id primitive.ObjectID) bson.M {
+	return bson.M{"_id": id, "status": bson.M{"$ne": StatusDeleted}}
+}
+
+// mongoError converts a unique index violation to a *DuplicateError.
+func mongoError(err error) error {
+	var errs []mongo.WriteError
+
+	switch e := err.(type) {
+	case mongo.WriteException:
+		errs = e.WriteErrors
+	case mongo.CommandError:
+		if e.Code == duplicateKey {
+			errs = []mongo.WriteError{{Code: duplicateKey, Message: e.Message}}
+		}
+	}
+
+	for _, we := range errs {
+		if we.Code != duplicateKey {
+			continue
+		}
+
+		for field, name := range uniqueIndexes {
+			if strings.Contains(we.Message, name) {
+				return &DuplicateError{Field: field}
+			}
+		}
+
+		return &DuplicateError{Field: "key"}
+	}
+
+	return err
+}
+
+// Create stores a new account and returns its ID.
+func (r *MongoUserRepository) Create(ctx context.Context, data *NewUserAccount) (primitive.ObjectID, error) {
+	result, err := r.users.InsertOne(ctx, data)
+	if err != nil {
+		return primitive.NilObjectID, mongoError(err)
+	}
+
+	return result.InsertedID.(primitive.ObjectID), nil
+}
+
+// Get returns the account with the given ID.
+func (r *MongoUserRepository) Get(ctx context.Context, id primitive.ObjectID) (*UserAccount, error) {
+	data := &UserAccount{}
+
+	err := r.users.FindOne(ctx, notDeleted(id)).Decode(data)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// GetMany returns the accounts with the given IDs with a single query, in no
+// particular order. Unknown IDs are left out.
+func (r *MongoUserRepository) GetMany(ctx context.Context, ids []primitive.ObjectID) ([]*UserAccount, error) {
+	accounts := []*UserAccount{}
+	if len(ids) == 0 {
+		return accounts, nil
+	}
+
+	filter := bson.M{"_id": bson.M{"$in": ids}, "status": bson.M{"$ne": StatusDeleted}}
+
+	cursor, err := r.users.Find(ctx, filter, options.Find().SetProjection(bson.M{"password": 0}))
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		data := &UserAccount{}
+
+		err := cursor.Decode(data)
+		if err != nil {
+			return nil, err
+		}
+
+		accounts = append(accounts, data)
+	}
+
+	return accounts, cursor.Err()
+}
+
+// GetByUserName returns the account with the given user name or, when none
+// has it, the account that had it until a change made since formerSince.
+func (r *MongoUserRepository) GetByUserName(ctx context.Context, userName string, formerSince time.Time) (*UserAccount, error) {
+	data := &UserAccount{}
+
+	opts := options.FindOne().SetProjection(bson.M{"password": 0}).SetCollation(userNameCollation)
+
+	err := r.users.FindOne(ctx, bson.M{"userName": userName, "status": bson.M{"$ne": StatusDeleted}}, opts).Decode(data)
+	if err == nil {
+		return data, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	former := bson.M{
+		"formerUserNames": bson.M{"$elemMatch": bson.M{"userName": userName, "changedAt": bson.M{"$gte": formerSince.UTC()}}},
+		"status":          bson.M{"$ne": StatusDeleted},
+	}
+
+	err = r.users.FindOne(ctx, former, opts).Decode(data)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// GetWithPassword returns the account with the given ID, password included.
+func (r *MongoUserRepository) GetWithPassword(ctx context.Context, id primitive.ObjectID) (*EditUserAccount, error) {
+	return r.findWithPassword(ctx, notDeleted(id))
+}
+
+// GetByEmailWithPassword returns the account with the given email, password
+// included.
+func (r *MongoUserRepository) GetByEmailWithPassword(ctx context.Context, email string) (*EditUserAccount, error) {
+	return r.findWithPassword(ctx, bson.M{"email": email, "status": bson.M{"$ne": StatusDeleted}})
+}
+
+func (r *MongoUserRepository) findWithPassword(ctx context.Context, filter bson.M) (*EditUserAccount, error) {
+	data := &EditUserAccount{}
+
+	err := r.users.FindOne(ctx, filter).Decode(data)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Update copies the fields named by mask, or every updatable field when mask
+// is empty, from data to the account with the given ID. A change of the user
+// name adds the former one to FormerUserNames.
+func (r *MongoUserRepository) Update(ctx context.Context, id primitive.ObjectID, data *EditUserAccount, mask []string) (*UserAccount, error) {
+	values, err := maskedValues(data, mask)
+	if err != nil {
+		return nil, err
+	}
+
+	// The update is a pipeline, so that the former user name is read from
+	// the account as it is replaced. The values are literals, lest one
+	// starting with "$" is read as a field path.
+	update := bson.A{}
+
+	if userName, ok := values[FieldUserName]; ok {
+		former := bson.A{bson.M{"userName": "$userName", "changedAt": time.Now().UTC()}}
+
+		update = append(update, bson.M{"$set": bson.M{"formerUserNames": bson.M{"$cond": bson.A{
+			bson.M{"$ne": bson.A{"$userName", bson.M{"$literal": userName}}},
+			bson.M{"$concatArrays": bson.A{bson.M{"$ifNull": bson.A{"$formerUserNames", bson.A{}}}, former}},
+			"$formerUserNames",
+		}}}})
+	}
+
+	set := bson.M{}
+	for field, value := range values {
+		set[field] = bson.M{"$literal": value}
+	}
+
+	update = append(update, bson.M{"$set": set})
+
+	updated := &UserAccount{}
+
+	err = r.users.FindOneAndUpdate(ctx, notDeleted(id), update, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(updated)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, mongoError(err)
+	}
+
+	return updated, nil
+}
+
+// maskedValues returns the values of data named by mask, keyed by field.
+func maskedValues(data *EditUserAccount, mask []string) (map[string]string, error) {
+	if len(mask) == 0 {
+		mask = UpdatableFields
+	}
+
+	all := map[string]string{
+		FieldEmail:    data.Email,
+		FieldName:     data.Name,
+		FieldUserName: data.UserName,
+		FieldPassword: data.Password,
+		FieldAvatar:   data.Avatar,
+	}
+
+	values := map[string]string{}
+
+	for _, field := range mask {
+		value, ok := all[field]
+		if !ok {
+			return nil, ErrUnknownField
+		}
+		values[field] = value
+	}
+
+	return values, nil
+}
+
+// SetProfile replaces the profile of the account with the given ID, provided
+// it is still at version.
+func (r *MongoUserRepository) SetProfile(ctx context.Context, id primitive.ObjectID, profile *Profile, version int64) (*UserAccount, error) {
+	stored := *profile
+	stored.Version = version + 1
+
+	return r.setVersioned(ctx, id, "profile", stored, version, func(data *UserAccount) int64 {
+		return profileVersion(data.Profile)
+	})
+}
+
+// SetPreferences replaces the preferences of the account with the given ID,
+// provided they are still at version.
+func (r *MongoUserRepository) SetPreferences(ctx context.Context, id primitive.ObjectID, prefs *Preferences, version int64) (*UserAccount, error) {
+	stored := *prefs
+	stored.Version = version + 1
+
+	return r.setVersioned(ctx, id, "preferences", stored, version, func(data *UserAccount) int64 {
+		return preferencesVersion(data.Preferences)
+	})
+}
+
+// setVersioned sets the embedded document field to value, provided its
+// "version" is still version. Documents written before the field existed have
+// none, which counts as version 0. current reads the version from an account,
+// for the *VersionError returned otherwise.
+func (r *MongoUserRepository) setVersioned(ctx context.Context, id primitive.ObjectID, field string, value interface{}, version int64, current func(*UserAccount) int64) (*UserAccount, error) {
+	filter := notDeleted(id)
+	if version == 0 {
+		filter[field+".version"] = bson.M{"$exists": false}
+	} else {
+		filter[field+".version"] = version
+	}
+
+	data := &UserAccount{}
+
+	err := r.users.FindOneAndUpdate(ctx, filter, bson.M{"$set": bson.M{field: value}}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(data)
+	if err == nil {
+		return data, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	data, err = r.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, &VersionError{Field: field, Current: current(data)}
+}
+
+// Transition applies change to the account with the given ID, provided its
+// status is one of from.
+func (r *MongoUserRepository) Transition(ctx context.Context, id primitive.ObjectID, from []string, change StatusChange) (*UserAccount, error) {
+	filter := statusIn(from...)
+	filter["_id"] = id
+
+	set := bson.M{}
+	unset := bson.A{}
+
+	// The change is applied in a pipeline update, so that the status can be
+	// computed from the current one.
+	if change.Status != "" {
+		set["status"] = change.Status
+	} else {
+		set["status"] = bson.M{"$ifNull": bson.A{"$previousStatus", StatusActive}}
+		unset = append(unset, "previousStatus")
+	}
+
+	if change.KeepStatus {
+		status := bson.M{"$ifNull": bson.A{"$status", StatusActive}}
+		set["previousStatus"] = bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{status, change.Status}}, "$previousStatus", status}}
+	}
+
+	if change.DeleteAfter != nil {
+		set["deleteAfter"] = change.DeleteAfter.UTC()
+	} else {
+		unset = append(unset, "deleteAfter")
+	}
+
+	if change.Suspension != nil {
+		// The reason is a literal, lest one starting with "$" is read as
+		// a field path.
+		suspension := bson.M{"reason": bson.M{"$literal": change.Suspension.Reason}, "at": change.Suspension.At.UTC()}
+		if change.Suspension.Until != nil {
+			suspension["until"] = change.Suspension.Until.UTC()
+		}
+		set["suspension"] = suspension
+	} else {
+		unset = append(unset, "suspension")
+	}
+
+	update := bson.A{bson.M{"$set": set}}
+	if len(unset) > 0 {
+		update = append(update, bson.M{"$unset": unset})
+	}
+
+	data := &UserAccount{}
+
+	err := r.users.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(data)
+	if err == nil {
+		return data, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	data, err = r.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, &TransitionError{Status: EffectiveStatus(data)}
+}
+
+// List returns a page of accounts.
+func (r *MongoUserRepository) List(ctx context.Context, opts ListOptions) ([]*UserAccount, error) {
+	filter := bson.M{"status": bson.M{"$ne": StatusDeleted}}
+	if !opts.IncludeInactive {
+		filter = activeFilter()
+	}
+
+	if !opts.After.IsZero() {
+		filter = bson.M{"$and": bson.A{filter, bson.M{"_id": bson.M{"$gt": opts.After}}}}
+	}
+
+	findOptions := options.Find().SetSort(bson.M{"_id": 1})
+	if opts.Limit > 0 {
+		findOptions.SetLimit(int64(opts.Limit))
+	}
+
+	cursor, err := r.users.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	accounts := []*UserAccount{}
+
+	for cursor.Next(ctx) {
+		data := &UserAccount{}
+
+		err := cursor.Decode(data)
+		if err != nil {
+			return nil, err
+		}
+
+		accounts = append(accounts, data)
+	}
+
+	return accounts, cursor.Err()
+}
+
+// Purge replaces every account pending deletion since before now with a
+// tombstone, removes the documents they own and returns their IDs. Only the
+// status is kept, so that the ID is never mistaken for one that never existed,
+// along with a mark for PendingCleanup that is set in the same write.
+func (r *MongoUserRepository) Purge(ctx context.Context, now time.Time) ([]primitive.ObjectID, error) {
+	due := bson.M{"status": StatusPendingDeletion, "deleteAfter": bson.M{"$lte": now.UTC()}}
+
+	cursor, err := r.users.Find(ctx, due, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	ids := []primitive.ObjectID{}
+
+	for cursor.Next(ctx) {
+		data := UserAccount{}
+
+		err := cursor.Decode(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, coll := range r.owned {
+			_, err := coll.DeleteMany(ctx, bson.M{"ownerId": data.ID})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Repeat the due date in the filter so a deletion cancelled in the
+		// meantime is not purged.
+		tombstone := bson.M{"status": StatusDeleted, "deletedAt": now.UTC(), "cleanupPending": true}
+
+		result, err := r.users.ReplaceOne(ctx, bson.M{"_id": data.ID, "status": due["status"], "deleteAfter": due["deleteAfter"]}, tombstone)
+		if err != nil {
+			return nil, err
+		}
+
+		if result.ModifiedCount == 1 {
+			ids = append(ids, data.ID)
+		}
+	}
+
+	return ids, cursor.Err()
+}
+
+// PendingCleanup returns the IDs of the tombstones still marked as pending
+// cleanup, in no particular order.
+func (r *MongoUserRepository) PendingCleanup(ctx context.Context) ([]primitive.ObjectID, error) {
+	cursor, err := r.users.Find(ctx, bson.M{"cleanupPending": true}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	ids := []primitive.ObjectID{}
+
+	for cursor.Next(ctx) {
+		data := UserAccount{}
+
+		err := cursor.Decode(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, data.ID)
+	}
+
+	return ids, cursor.Err()
+}
+
+// ConfirmCleanup unmarks the tombstones with the given IDs.
+func (r *MongoUserRepository) ConfirmCleanup(ctx context.Context, ids []primitive.ObjectID) error {
+	_, err := r.users.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}, "cleanupPending": true}, bson.M{"$unset": bson.M{"cleanupPending": ""}})
+	return err
+}
+
+// Export calls send with every document stored about the account with the
+// given ID. Accounts pending deletion can still be exported until they are
+// purged.
+func (r *MongoUserRepository) Export(ctx context.Context, id primitive.ObjectID, send func(collection string, document []byte) error) error {
+	user := bson.M{}
+
+	err := r.users.FindOne(ctx, notDeleted(id)).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	delete(user, "password")
+
+	err = sendExtJSON(send, r.users.Name(), user)
+	if err != nil {
+		return err
+	}
+
+	for _, coll := range r.owned {
+		err := exportCollection(ctx, coll, bson.M{"ownerId": id}, send)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exportCollection calls send with the documents of coll matching filter.
+func exportCollection(ctx context.Context, coll *mongo.Collection, filter bson.M, send func(collection string, document []byte) error) error {
+	cursor, err := coll.Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		doc := bson.M{}
+
+		err := cursor.Decode(&doc)
+		if err != nil {
+			return err
+		}
+
+		err = sendExtJSON(send, coll.Name(), doc)
+		if err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+func sendExtJSON(send func(collection string, document []byte) error, collection string, doc interface{}) error {
+	document, err := bson.MarshalExtJSON(doc, false, false)
+	if err != nil {
+		return err
+	}
+
+	return send(collection, document)
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// indexOptionsConflict is the MongoDB error code for an index created again
+// with different options.
+const indexOptionsConflict = 85
+
+// MongoAuditRepository stores the audit log in a MongoDB collection, which is
+// only ever inserted into.
+type MongoAuditRepository struct {
+	events *mongo.Collection
+}
+
+// NewMongoAuditRepository stores the audit log in events, and creates its
+// indexes, including the TTL index expiring events after retention.
+func NewMongoAuditRepository(ctx context.Context, events *mongo.Collection, retention time.Duration) (*MongoAuditRepository, error) {
+	expireAfter := int32(retention.Seconds())
+
+	_, err := events.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.M{"time": 1},
+		Options: options.Index().SetName("time_ttl").SetExpireAfterSeconds(expireAfter),
+	})
+
+	// The index exists with another retention, which is changed in place.
+	if cmdErr, ok := err.(mongo.CommandError); ok && cmdErr.Code == indexOptionsConflict {
+		err = events.Database().RunCommand(ctx, bson.D{
+			{Key: "collMod", Value: events.Name()},
+			{Key: "index", Value: bson.M{"name": "time_ttl", "expireAfterSeconds": expireAfter}},
+		}).Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = events.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "actorId", Value: 1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "targetId", Value: 1}, {Key: "_id", Value: -1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoAuditRepository{events: events}, nil
+}
+
+// Insert appends e to the log.
+func (r *MongoAuditRepository) Insert(ctx context.Context, e *AuditEvent) error {
+	_, err := r.events.InsertOne(ctx, e)
+	return err
+}
+
+// List returns a page of events, and whether there are more.
+func (r *MongoAuditRepository) List(ctx context.Context, q AuditQuery) ([]*AuditEvent, bool, error) {
+	filter := bson.M{}
+
+	if q.ActorID != "" {
+		filter["actorId"] = q.ActorID
+	}
+	if q.TargetID != "" {
+		filter["targetId"] = q.TargetID
+	}
+	if q.UserID != "" {
+		filter["$or"] = bson.A{
+			bson.M{"actorId": q.UserID},
+			bson.M{"targetId": q.UserID},
+		}
+	}
+	if q.Action != "" {
+		filter["action"] = q.Action
+	}
+
+	if q.Since != nil || q.Until != nil {
+		window := bson.M{}
+		if q.Since != nil {
+			window["$gte"] = q.Since.UTC()
+		}
+		if q.Until != nil {
+			window["$lt"] = q.Until.UTC()
+		}
+		filter["time"] = window
+	}
+
+	if !q.After.IsZero() {
+		filter["_id"] = bson.M{"$lt": q.After}
+	}
+
+	// Fetch one more than asked, to know whether there is a next page.
+	findOptions := options.Find().SetSort(bson.M{"_id": -1})
+	if q.Limit > 0 {
+		findOptions.SetLimit(int64(q.Limit) + 1)
+	}
+
+	cursor, err := r.events.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer cursor.Close(ctx)
+
+	events := []*AuditEvent{}
+
+	for cursor.Next(ctx) {
+		e := &AuditEvent{}
+
+		err := cursor.Decode(e)
+		if err != nil {
+			return nil, false, err
+		}
+
+		events = append(events, e)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, false, err
+	}
+
+	if q.Limit > 0 && len(events) > q.Limit {
+		return events[:q.Limit], true, nil
+	}
+
+	return events, false, nil
+}
@@ -0,0 +1,93 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/allen-woods/the-supertask/services/user/repository"
+	"github.com/allen-woods/the-supertask/services/user/repository/repotest"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoDatabases skips t unless REPOTEST_MONGO_URI names a MongoDB to check
+// the MongoDB repositories against, such as a local container:
+//
+//	docker run --rm -d -p 27017:27017 mongo:bionic
+//	REPOTEST_MONGO_URI=mongodb://localhost:27017 go test ./repository
+//
+// Each check runs in a database of its own, returned by newDatabase and
+// dropped by dropAll once the test is over.
+func mongoDatabases(t *testing.T) (newDatabase func() *mongo.Database, dropAll func()) {
+	uri := os.Getenv("REPOTEST_MONGO_URI")
+	if uri == "" {
+		t.Skip("REPOTEST_MONGO_URI is not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("Could not connect to MongoDB: %v", err)
+	}
+
+	err = client.Ping(ctx, nil)
+	if err != nil {
+		t.Fatalf("Could not reach MongoDB: %v", err)
+	}
+
+	databases := []*mongo.Database{}
+
+	newDatabase = func() *mongo.Database {
+		db := client.Database("repotest_" + primitive.NewObjectID().Hex())
+		databases = append(databases, db)
+		return db
+	}
+
+	dropAll = func() {
+		for _, db := range databases {
+			err := db.Drop(context.Background())
+			if err != nil {
+				t.Errorf("Could not drop %s: %v", db.Name(), err)
+			}
+		}
+
+		client.Disconnect(context.Background())
+	}
+
+	return newDatabase, dropAll
+}
+
+func TestMongoUserRepository(t *testing.T) {
+	newDatabase, dropAll := mongoDatabases(t)
+	defer dropAll()
+
+	repotest.TestUserRepository(t, func(t *testing.T) repository.UserRepository {
+		db := newDatabase()
+
+		r, err := repository.NewMongoUserRepository(context.Background(), db.Collection("users"), db.Collection("owned"))
+		if err != nil {
+			t.Fatalf("Could not create repository: %v", err)
+		}
+
+		return r
+	})
+}
+
+func TestMongoAuditRepository(t *testing.T) {
+	newDatabase, dropAll := mongoDatabases(t)
+	defer dropAll()
+
+	repotest.TestAuditRepository(t, func(t *testing.T) repository.AuditRepository {
+		r, err := repository.NewMongoAuditRepository(context.Background(), newDatabase().Collection("auditEvents"), time.Hour)
+		if err != nil {
+			t.Fatalf("Could not create repository: %v", err)
+		}
+
+		return r
+	})
+}
@@ -0,0 +1,385 @@
+// Package repository stores the user accounts and security audit log of the
+// User service. Every store implements the same interfaces with the same
+// semantics, which the repotest package checks.
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Account statuses, as stored in the "status" field of a user document.
+//
+// An account moves through them as follows:
+//
+//	pendingVerification -> active, suspended, pendingDeletion
+//	active              -> suspended, pendingDeletion
+//	suspended           -> (its previous status)
+//	pendingDeletion     -> (its previous status), deleted
+//	deleted             -> (none)
+//
+// Accounts are created active for as long as email addresses are not
+// verified.
+const (
+	StatusPendingVerification = "pendingVerification"
+	StatusActive              = "active"
+	StatusSuspended           = "suspended"
+	StatusPendingDeletion     = "pendingDeletion"
+	StatusDeleted             = "deleted"
+)
+
+// Account roles, as stored in the "role" field of a user document.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Themes of the app, as stored in the "preferences.theme" field of a user
+// document. The system theme follows the settings of the device.
+const (
+	ThemeSystem = "system"
+	ThemeLight  = "light"
+	ThemeDark   = "dark"
+)
+
+// Views the tasks are shown in by default, as stored in the
+// "preferences.defaultViews.tasks" field of a user document.
+const (
+	TaskViewList     = "list"
+	TaskViewBoard    = "board"
+	TaskViewCalendar = "calendar"
+)
+
+// Spans of the calendar shown by default, as stored in the
+// "preferences.defaultViews.calendar" field of a user document.
+const (
+	CalendarViewDay   = "day"
+	CalendarViewWeek  = "week"
+	CalendarViewMonth = "month"
+)
+
+// Who may see the profile of a user besides the user and admins, as stored in
+// the "preferences.privacy.profileVisibility" field of a user document.
+// Members are the users who are logged in.
+const (
+	VisibilityPublic  = "public"
+	VisibilityMembers = "members"
+	VisibilityHidden  = "hidden"
+)
+
+// Fields of an account that Update can change, named as in FieldMask paths.
+const (
+	FieldEmail    = "email"
+	FieldName     = "name"
+	FieldUserName = "userName"
+	FieldPassword = "password"
+	// FieldAvatar is only changed when named, as the API replaces the
+	// images of the avatar first.
+	FieldAvatar = "avatar"
+)
+
+// UpdatableFields are the fields Update changes when given no field mask.
+var UpdatableFields = []string{FieldEmail, FieldName, FieldUserName, FieldPassword}
+
+var (
+	// ErrNotFound is returned when no account, or no deleted one, has the
+	// requested ID, email or user name.
+	ErrNotFound = errors.New("user not found")
+	// ErrUnknownField is returned for a field mask path Update cannot change.
+	ErrUnknownField = errors.New("unknown field")
+)
+
+// DuplicateError is returned when an account would share its email or user
+// name with another.
+type DuplicateError struct {
+	Field string
+}
+
+func (e *DuplicateError) Error() string {
+	return "another user has the same " + e.Field
+}
+
+// VersionError is returned by SetProfile and SetPreferences when what they
+// replace has changed since the version the caller read.
+type VersionError struct {
+	Field   string
+	Current int64
+}
+
+func (e *VersionError) Error() string {
+	return fmt.Sprintf("the %s is at version %d", e.Field, e.Current)
+}
+
+// TransitionError is returned by Transition when the account is not in one of
+// the statuses the transition starts from.
+type TransitionError struct {
+	Status string
+}
+
+func (e *TransitionError) Error() string {
+	return "user is " + e.Status
+}
+
+// Suspension is the embedded document describing why, and until when, an
+// account is suspended.
+type Suspension struct {
+	Reason string     `bson:"reason"`
+	Until  *time.Time `bson:"until,omitempty"`
+	At     time.Time  `bson:"at"`
+}
+
+// Profile is the embedded document describing a user to others. Every field
+// is optional. Version counts the times it was replaced, starting from 1;
+// accounts that never had one have none, which is version 0.
+type Profile struct {
+	DisplayName string        `bson:"displayName,omitempty"`
+	Bio         string        `bson:"bio,omitempty"`
+	Links       []ProfileLink `bson:"links,omitempty"`
+	Location    string        `bson:"location,omitempty"`
+	Timezone    string        `bson:"timezone,omitempty"`
+	Locale      string        `bson:"locale,omitempty"`
+	Pronouns    string        `bson:"pronouns,omitempty"`
+	Version     int64         `bson:"version"`
+}
+
+// ProfileLink is a website of a user, such as a blog or a social profile.
+type ProfileLink struct {
+	Label string `bson:"label,omitempty"`
+	URL   string `bson:"url"`
+}
+
+// Preferences is the embedded document describing how a user wants the app to
+// behave. It is always stored whole, and versioned like Profile.
+type Preferences struct {
+	Theme         string                  `bson:"theme"`
+	Notifications NotificationPreferences `bson:"notifications"`
+	DefaultViews  DefaultViews            `bson:"defaultViews"`
+	Privacy       PrivacyPreferences      `bson:"privacy"`
+	Version       int64                   `bson:"version"`
+}
+
+// NotificationPreferences are the emails a user wants to receive.
+type NotificationPreferences struct {
+	SecurityAlerts bool `bson:"securityAlerts"`
+	TaskReminders  bool `bson:"taskReminders"`
+	ProductUpdates bool `bson:"productUpdates"`
+}
+
+// DefaultViews are the views the app opens on.
+type DefaultViews struct {
+	Tasks    string `bson:"tasks"`
+	Calendar string `bson:"calendar"`
+}
+
+// PrivacyPreferences are what a user shows others. Preferences stored before
+// they existed have none, which counts as public.
+type PrivacyPreferences struct {
+	ProfileVisibility string `bson:"profileVisibility"`
+}
+
+// DefaultPreferences are the preferences of the users who never set theirs,
+// at version 0.
+func DefaultPreferences() *Preferences {
+	return &Preferences{
+		Theme: ThemeSystem,
+		Notifications: NotificationPreferences{
+			SecurityAlerts: true,
+			TaskReminders:  true,
+		},
+		DefaultViews: DefaultViews{
+			Tasks:    TaskViewList,
+			Calendar: CalendarViewWeek,
+		},
+		Privacy: PrivacyPreferences{
+			ProfileVisibility: VisibilityPublic,
+		},
+	}
+}
+
+func profileVersion(p *Profile) int64 {
+	if p == nil {
+		return 0
+	}
+	return p.Version
+}
+
+func preferencesVersion(p *Preferences) int64 {
+	if p == nil {
+		return 0
+	}
+	return p.Version
+}
+
+// FormerUserName is a user name an account had, along with when it changed.
+type FormerUserName struct {
+	UserName  string    `bson:"userName"`
+	ChangedAt time.Time `bson:"changedAt"`
+}
+
+// NewUserAccount is the struct used for a new User signing up. It contains hashed and salted password information.
+type NewUserAccount struct {
+	Email    string `bson:"email"`
+	Name     string `bson:"name"`
+	UserName string `bson:"userName"`
+	Password string `bson:"password"`
+	Status   string `bson:"status"`
+	Role     string `bson:"role"`
+}
+
+// UserAccount is the struct used for registered User accounts. It does not contain password information.
+type UserAccount struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	Email          string             `bson:"email"`
+	Name           string             `bson:"name"`
+	UserName       string             `bson:"userName"`
+	Status         string             `bson:"status,omitempty"`
+	PreviousStatus string             `bson:"previousStatus,omitempty"`
+	Role           string             `bson:"role,omitempty"`
+	Suspension     *Suspension        `bson:"suspension,omitempty"`
+	DeleteAfter    *time.Time         `bson:"deleteAfter,omitempty"`
+	Avatar         string             `bson:"avatar,omitempty"`
+	Profile        *Profile           `bson:"profile,omitempty"`
+	Preferences    *Preferences       `bson:"preferences,omitempty"`
+	// FormerUserNames are the user names the account had, oldest first.
+	FormerUserNames []FormerUserName `bson:"formerUserNames,omitempty"`
+}
+
+// EditUserAccount is the struct used for an account that needs to be updated by its owner. It contains hashed and salted password information because this user is "Me" in the API.
+type EditUserAccount struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	Email          string             `bson:"email"`
+	Name           string             `bson:"name"`
+	UserName       string             `bson:"userName"`
+	Password       string             `bson:"password"`
+	Status         string             `bson:"status,omitempty"`
+	PreviousStatus string             `bson:"previousStatus,omitempty"`
+	Role           string             `bson:"role,omitempty"`
+	Suspension     *Suspension        `bson:"suspension,omitempty"`
+	DeleteAfter    *time.Time         `bson:"deleteAfter,omitempty"`
+	Avatar         string             `bson:"avatar,omitempty"`
+	Profile        *Profile           `bson:"profile,omitempty"`
+	Preferences    *Preferences       `bson:"preferences,omitempty"`
+	// FormerUserNames are the user names the account had, oldest first.
+	FormerUserNames []FormerUserName `bson:"formerUserNames,omitempty"`
+}
+
+// UserAccount drops the password information of the account.
+func (data *EditUserAccount) UserAccount() *UserAccount {
+	return &UserAccount{
+		ID:              data.ID,
+		Email:           data.Email,
+		Name:            data.Name,
+		UserName:        data.UserName,
+		Status:          data.Status,
+		PreviousStatus:  data.PreviousStatus,
+		Role:            data.Role,
+		Suspension:      data.Suspension,
+		DeleteAfter:     data.DeleteAfter,
+		Avatar:          data.Avatar,
+		Profile:         data.Profile,
+		Preferences:     data.Preferences,
+		FormerUserNames: data.FormerUserNames,
+	}
+}
+
+// EffectiveStatus is the status of an account as of now. Accounts written
+// before statuses existed have none and count as active. Suspended accounts
+// whose suspension has run out have the status they had before, or active.
+func EffectiveStatus(data *UserAccount) string {
+	switch {
+	case data.Status == "":
+		return StatusActive
+	case data.Status == StatusSuspended && data.Suspension != nil && data.Suspension.Until != nil && !data.Suspension.Until.After(time.Now()):
+		if data.PreviousStatus != "" {
+			return data.PreviousStatus
+		}
+		return StatusActive
+	}
+
+	return data.Status
+}
+
+// StatusChange is how Transition changes the status of an account. The
+// deletion date and suspension of the account are replaced by those of the
+// change, and removed when it has none.
+type StatusChange struct {
+	// Status is the new status. When empty, the status kept by an earlier
+	// change with KeepStatus is restored, or active when there is none.
+	Status string
+	// KeepStatus keeps the current status so that it can be restored, unless
+	// it is already the new one, in which case the kept status is left as
+	// it is.
+	KeepStatus  bool
+	DeleteAfter *time.Time
+	Suspension  *Suspension
+}
+
+// ListOptions pages through the accounts in the order of their IDs.
+type ListOptions struct {
+	// IncludeInactive lists every account that is not deleted, rather than
+	// only those whose effective status is active.
+	IncludeInactive bool
+	// After is the ID of the last account of the previous page.
+	After primitive.ObjectID
+	// Limit is the size of the page; zero lists every account.
+	Limit int
+}
+
+// UserRepository stores user accounts. Deleted accounts are only kept as
+// tombstones, which are never returned.
+type UserRepository interface {
+	// Create stores a new account and returns its ID.
+	Create(ctx context.Context, data *NewUserAccount) (primitive.ObjectID, error)
+	// Get returns the account with the given ID.
+	Get(ctx context.Context, id primitive.ObjectID) (*UserAccount, error)
+	// GetMany returns the accounts with the given IDs, in no particular order.
+	// Unknown IDs are left out.
+	GetMany(ctx context.Context, ids []primitive.ObjectID) ([]*UserAccount, error)
+	// GetByUserName returns the account with the given user name or, when
+	// none has it, the account that had it until a change made since
+	// formerSince. User names are compared regardless of their case, as they
+	// are unique regardless of it.
+	GetByUserName(ctx context.Context, userName string, formerSince time.Time) (*UserAccount, error)
+	// GetWithPassword returns the account with the given ID, password included.
+	GetWithPassword(ctx context.Context, id primitive.ObjectID) (*EditUserAccount, error)
+	// GetByEmailWithPassword returns the account with the given email,
+	// password included.
+	GetByEmailWithPassword(ctx context.Context, email string) (*EditUserAccount, error)
+	// Update copies the fields named by mask, or every updatable field when
+	// mask is empty, from data to the account with the given ID. A change of
+	// the user name adds the former one to FormerUserNames.
+	Update(ctx context.Context, id primitive.ObjectID, data *EditUserAccount, mask []string) (*UserAccount, error)
+	// Transition applies change to the account with the given ID, provided
+	// its status is one of from. When it is not, the current account is
+	// returned along with a *TransitionError.
+	Transition(ctx context.Context, id primitive.ObjectID, from []string, change StatusChange) (*UserAccount, error)
+	// SetProfile replaces the profile of the account with the given ID,
+	// provided it is still at version, and stores it at the next version.
+	// When it is not, the current account is returned along with a
+	// *VersionError.
+	SetProfile(ctx context.Context, id primitive.ObjectID, profile *Profile, version int64) (*UserAccount, error)
+	// SetPreferences replaces the preferences of the account with the given
+	// ID the way SetProfile replaces its profile.
+	SetPreferences(ctx context.Context, id primitive.ObjectID, prefs *Preferences, version int64) (*UserAccount, error)
+	// List returns a page of accounts.
+	List(ctx context.Context, opts ListOptions) ([]*UserAccount, error)
+	// Purge replaces every account pending deletion since before now with a
+	// tombstone, removes the documents they own and returns their IDs. The
+	// accounts are pending cleanup from then on.
+	Purge(ctx context.Context, now time.Time) ([]primitive.ObjectID, error)
+	// PendingCleanup returns the IDs of the purged accounts whose data kept
+	// outside of the repository is yet to be deleted, until ConfirmCleanup
+	// says it is.
+	PendingCleanup(ctx context.Context) ([]primitive.ObjectID, error)
+	// ConfirmCleanup records that nothing is left outside of the repository
+	// of the purged accounts with the given IDs.
+	ConfirmCleanup(ctx context.Context, ids []primitive.ObjectID) error
+	// Export calls send with every document stored about the account with
+	// the given ID, as relaxed extended JSON, grouped by collection. The
+	// account comes first, without its password.
+	Export(ctx context.Context, id primitive.ObjectID, send func(collection string, document []byte) error) error
+}
@@ -0,0 +1,818 @@
+// Package repotest checks that implementations of the repository interfaces
+// have the semantics the User service relies on, so that every store behaves
+// the same.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/allen-woods/the-supertask/services/user/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// checkTimeout bounds each check, so that an unreachable store fails it rather
+// than hanging.
+const checkTimeout = time.Minute
+
+// TestUserRepository runs every check of a user repository as a subtest of t,
+// each against a new, empty repository returned by newRepo.
+func TestUserRepository(t *testing.T, newRepo func(t *testing.T) repository.UserRepository) {
+	checks := []struct {
+		name  string
+		check func(*testing.T, context.Context, repository.UserRepository)
+	}{
+		{"create and get", checkCreateAndGet},
+		{"get many", checkGetMany},
+		{"unique constraints", checkUnique},
+		{"field masks", checkFieldMasks},
+		{"user names", checkUserNames},
+		{"transitions", checkTransitions},
+		{"versions", checkVersions},
+		{"pagination", checkPagination},
+		{"purge", checkPurge},
+		{"export", checkExport},
+	}
+
+	for _, c := range checks {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+			defer cancel()
+
+			c.check(t, ctx, newRepo(t))
+		})
+	}
+}
+
+// TestAuditRepository runs every check of an audit repository as a subtest of
+// t, each against a new, empty repository returned by newRepo.
+func TestAuditRepository(t *testing.T, newRepo func(t *testing.T) repository.AuditRepository) {
+	checks := []struct {
+		name  string
+		check func(*testing.T, context.Context, repository.AuditRepository)
+	}{
+		{"filters", checkAuditFilters},
+		{"pagination", checkAuditPagination},
+	}
+
+	for _, c := range checks {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+			defer cancel()
+
+			c.check(t, ctx, newRepo(t))
+		})
+	}
+}
+
+// newAccount returns a distinct account, numbered n.
+func newAccount(n int) *repository.NewUserAccount {
+	return &repository.NewUserAccount{
+		Email:    fmt.Sprintf("user%d@example.com", n),
+		Name:     fmt.Sprintf("User %d", n),
+		UserName: fmt.Sprintf("user%d", n),
+		Password: fmt.Sprintf("hash%d", n),
+		Status:   repository.StatusActive,
+		Role:     repository.RoleUser,
+	}
+}
+
+// create creates the account numbered n.
+func create(t *testing.T, ctx context.Context, r repository.UserRepository, n int) primitive.ObjectID {
+	t.Helper()
+
+	id, err := r.Create(ctx, newAccount(n))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	return id
+}
+
+func checkCreateAndGet(t *testing.T, ctx context.Context, r repository.UserRepository) {
+	id := create(t, ctx, r, 1)
+
+	want := newAccount(1)
+
+	got, err := r.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.ID != id || got.Email != want.Email || got.Name != want.Name || got.UserName != want.UserName || got.Status != want.Status || got.Role != want.Role {
+		t.Fatalf("Get returned %+v, want the account created as %+v", got, want)
+	}
+
+	withPassword, err := r.GetWithPassword(ctx, id)
+	if err != nil {
+		t.Fatalf("GetWithPassword: %v", err)
+	}
+	if withPassword.Password != want.Password {
+		t.Fatalf("GetWithPassword returned password %q, want %q", withPassword.Password, want.Password)
+	}
+
+	byEmail, err := r.GetByEmailWithPassword(ctx, want.Email)
+	if err != nil {
+		t.Fatalf("GetByEmailWithPassword: %v", err)
+	}
+	if byEmail.ID != id || byEmail.Password != want.Password {
+		t.Fatalf("GetByEmailWithPassword returned %+v, want the account created", byEmail)
+	}
+
+	_, err = r.Get(ctx, primitive.NewObjectID())
+	if err != repository.ErrNotFound {
+		t.Fatalf("Get of an unknown ID returned %v, want ErrNotFound", err)
+	}
+
+	_, err = r.GetByEmailWithPassword(ctx, "nobody@example.com")
+	if err != repository.ErrNotFound {
+		t.Fatalf("GetByEmailWithPassword of an unknown email returned %v, want ErrNotFound", err)
+	}
+}
+
+// checkGetMany checks that GetMany returns each known account asked for once,
+// and leaves unknown IDs out.
+func checkGetMany(t *testing.T, ctx context.Context, r repository.UserRepository) {
+	ids := []primitive.ObjectID{}
+	for n := 1; n <= 3; n++ {
+		id := create(t, ctx, r, n)
+		ids = append(ids, id)
+	}
+
+	got, err := r.GetMany(ctx, []primitive.ObjectID{ids[0], ids[2], primitive.NewObjectID(), ids[0]})
+	if err != nil {
+		t.Fatalf("GetMany: %v", err)
+	}
+
+	want := map[primitive.ObjectID]string{ids[0]: newAccount(1).Email, ids[2]: newAccount(3).Email}
+	if len(got) != len(want) {
+		t.Fatalf("GetMany returned %d accounts, want %d", len(got), len(want))
+	}
+	for _, data := range got {
+		if want[data.ID] != data.Email {
+			t.Fatalf("GetMany returned %+v, which was not asked for", data)
+		}
+	}
+
+	none, err := r.GetMany(ctx, nil)
+	if err != nil {
+		t.Fatalf("GetMany of no IDs: %v", err)
+	}
+	if len(none) != 0 {
+		t.Fatalf("GetMany of no IDs returned %d accounts, want none", len(none))
+	}
+}
+
+// wantDuplicate checks that err is a *DuplicateError on field.
+func wantDuplicate(t *testing.T, op string, err error, field string) {
+	t.Helper()
+
+	var dup *repository.DuplicateError
+	if !errors.As(err, &dup) || dup.Field != field {
+		t.Fatalf("%s returned %v, want a duplicate %s", op, err, field)
+	}
+}
+
+func checkUnique(t *testing.T, ctx context.Context, r repository.UserRepository) {
+	create(t, ctx, r, 1)
+
+	id := create(t, ctx, r, 2)
+
+	sameEmail := newAccount(3)
+	sameEmail.Email = newAccount(1).Email
+
+	_, err := r.Create(ctx, sameEmail)
+	wantDuplicate(t, "Create", err, repository.FieldEmail)
+
+	sameUserName := newAccount(3)
+	sameUserName.UserName = newAccount(1).UserName
+
+	_, err = r.Create(ctx, sameUserName)
+	wantDuplicate(t, "Create", err, repository.FieldUserName)
+
+	// User names are the same whatever their case.
+	sameUserName.UserName = strings.ToUpper(newAccount(1).UserName)
+
+	_, err = r.Create(ctx, sameUserName)
+	wantDuplicate(t, "Create with another case", err, repository.FieldUserName)
+
+	_, err = r.Update(ctx, id, &repository.EditUserAccount{Email: newAccount(1).Email}, []string{repository.FieldEmail})
+	wantDuplicate(t, "Update", err, repository.FieldEmail)
+
+	// Keeping its own email is not a duplicate.
+	_, err = r.Update(ctx, id, &repository.EditUserAccount{Email: newAccount(2).Email}, []string{repository.FieldEmail})
+	if err != nil {
+		t.Fatalf("Update to the same email: %v", err)
+	}
+}
+
+func checkFieldMasks(t *testing.T, ctx context.Context, r repository.UserRepository) {
+	id := create(t, ctx, r, 1)
+
+	edit := &repository.EditUserAccount{
+		Email:    "changed@example.com",
+		Name:     "Changed",
+		UserName: "changed",
+		Password: "changedHash",
+		Avatar:   "changedAvatar",
+	}
+
+	got, err := r.Update(ctx, id, edit, []string{repository.FieldName})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	want := newAccount(1)
+	if got.Name != edit.Name || got.Email != want.Email || got.UserName != want.UserName {
+		t.Fatalf("Update of the name returned %+v, want only the name changed", got)
+	}
+
+	withPassword, err := r.GetWithPassword(ctx, id)
+	if err != nil {
+		t.Fatalf("GetWithPassword: %v", err)
+	}
+	if withPassword.Password != want.Password || withPassword.Name != edit.Name {
+		t.Fatalf("Update of the name stored %+v, want only the name changed", withPassword)
+	}
+
+	got, err = r.Update(ctx, id, edit, nil)
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got.Email != edit.Email || got.UserName != edit.UserName || got.Status != want.Status {
+		t.Fatalf("Update without a mask returned %+v, want every field changed", got)
+	}
+	if got.Avatar != "" {
+		t.Fatalf("Update without a mask set the avatar to %q, want it only changed when named", got.Avatar)
+	}
+
+	got, err = r.Update(ctx, id, edit, []string{repository.FieldAvatar})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got.Avatar != edit.Avatar {
+		t.Fatalf("Update of the avatar returned avatar %q, want %q", got.Avatar, edit.Avatar)
+	}
+
+	withPassword, err = r.GetWithPassword(ctx, id)
+	if err != nil {
+		t.Fatalf("GetWithPassword: %v", err)
+	}
+	if withPassword.Password != edit.Password {
+		t.Fatalf("Update without a mask stored password %q, want %q", withPassword.Password, edit.Password)
+	}
+
+	_, err = r.Update(ctx, id, edit, []string{"role"})
+	if err != repository.ErrUnknownField {
+		t.Fatalf("Update of the role returned %v, want ErrUnknownField", err)
+	}
+
+	_, err = r.Update(ctx, primitive.NewObjectID(), edit, []string{repository.FieldName})
+	if err != repository.ErrNotFound {
+		t.Fatalf("Update of an unknown ID returned %v, want ErrNotFound", err)
+	}
+}
+
+func checkUserNames(t *testing.T, ctx context.Context, r repository.UserRepository) {
+	id := create(t, ctx, r, 1)
+
+	otherID := create(t, ctx, r, 2)
+
+	before := time.Now().Add(-time.Minute)
+	want := newAccount(1)
+
+	got, err := r.GetByUserName(ctx, want.UserName, before)
+	if err != nil {
+		t.Fatalf("GetByUserName: %v", err)
+	}
+	if got.ID != id || got.UserName != want.UserName || len(got.FormerUserNames) != 0 {
+		t.Fatalf("GetByUserName returned %+v, want the account created as %+v", got, want)
+	}
+
+	got, err = r.GetByUserName(ctx, strings.ToUpper(want.UserName), before)
+	if err != nil {
+		t.Fatalf("GetByUserName with another case: %v", err)
+	}
+	if got.ID != id {
+		t.Fatalf("GetByUserName with another case returned %+v, want the account created as %+v", got, want)
+	}
+
+	edit := &repository.EditUserAccount{UserName: "$renamed", Name: "Renamed"}
+
+	got, err = r.Update(ctx, id, edit, []string{repository.FieldName})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if len(got.FormerUserNames) != 0 {
+		t.Fatalf("Update of the name returned former user names %+v, want none", got.FormerUserNames)
+	}
+
+	got, err = r.Update(ctx, id, edit, []string{repository.FieldUserName})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got.UserName != edit.UserName || len(got.FormerUserNames) != 1 || got.FormerUserNames[0].UserName != want.UserName || got.FormerUserNames[0].ChangedAt.Before(before) {
+		t.Fatalf("Update of the user name returned %+v, want %q as a former user name", got, want.UserName)
+	}
+
+	got, err = r.Update(ctx, id, edit, []string{repository.FieldUserName})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if len(got.FormerUserNames) != 1 {
+		t.Fatalf("Update to the same user name returned former user names %+v, want one", got.FormerUserNames)
+	}
+
+	got, err = r.GetByUserName(ctx, edit.UserName, before)
+	if err != nil {
+		t.Fatalf("GetByUserName: %v", err)
+	}
+	if got.ID != id {
+		t.Fatalf("GetByUserName of the new user name returned %+v, want the renamed account", got)
+	}
+
+	got, err = r.GetByUserName(ctx, want.UserName, before)
+	if err != nil {
+		t.Fatalf("GetByUserName of the former user name: %v", err)
+	}
+	if got.ID != id || got.UserName != edit.UserName {
+		t.Fatalf("GetByUserName of the former user name returned %+v, want the renamed account", got)
+	}
+
+	got, err = r.GetByUserName(ctx, strings.ToUpper(want.UserName), before)
+	if err != nil {
+		t.Fatalf("GetByUserName of the former user name with another case: %v", err)
+	}
+	if got.ID != id {
+		t.Fatalf("GetByUserName of the former user name with another case returned %+v, want the renamed account", got)
+	}
+
+	_, err = r.GetByUserName(ctx, want.UserName, time.Now().Add(time.Minute))
+	if err != repository.ErrNotFound {
+		t.Fatalf("GetByUserName of a user name changed too long ago returned %v, want ErrNotFound", err)
+	}
+
+	// Whoever has a user name comes before whoever had it.
+	_, err = r.Update(ctx, otherID, &repository.EditUserAccount{UserName: want.UserName}, []string{repository.FieldUserName})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	got, err = r.GetByUserName(ctx, want.UserName, before)
+	if err != nil {
+		t.Fatalf("GetByUserName: %v", err)
+	}
+	if got.ID != otherID {
+		t.Fatalf("GetByUserName of a user name taken by another account returned %+v, want the account having it", got)
+	}
+
+	_, err = r.GetByUserName(ctx, "unknown", before)
+	if err != repository.ErrNotFound {
+		t.Fatalf("GetByUserName of an unknown user name returned %v, want ErrNotFound", err)
+	}
+}
+
+func checkTransitions(t *testing.T, ctx context.Context, r repository.UserRepository) {
+	id := create(t, ctx, r, 1)
+
+	deleteAfter := time.Now().Add(time.Hour)
+
+	got, err := r.Transition(ctx, id, []string{repository.StatusActive}, repository.StatusChange{
+		Status:      repository.StatusPendingDeletion,
+		KeepStatus:  true,
+		DeleteAfter: &deleteAfter,
+	})
+	if err != nil {
+		t.Fatalf("Transition to pending deletion: %v", err)
+	}
+	if got.Status != repository.StatusPendingDeletion || got.PreviousStatus != repository.StatusActive || got.DeleteAfter == nil || !got.DeleteAfter.Equal(deleteAfter.UTC().Truncate(time.Millisecond)) {
+		t.Fatalf("Transition to pending deletion returned %+v", got)
+	}
+
+	got, err = r.Transition(ctx, id, []string{repository.StatusActive}, repository.StatusChange{Status: repository.StatusSuspended})
+	var transitionErr *repository.TransitionError
+	if !errors.As(err, &transitionErr) || transitionErr.Status != repository.StatusPendingDeletion {
+		t.Fatalf("Transition from the wrong status returned %v, want a TransitionError from %s", err, repository.StatusPendingDeletion)
+	}
+	if got == nil || got.ID != id || got.Status != repository.StatusPendingDeletion {
+		t.Fatalf("Transition from the wrong status returned %+v, want the current account", got)
+	}
+
+	got, err = r.Transition(ctx, id, []string{repository.StatusPendingDeletion}, repository.StatusChange{})
+	if err != nil {
+		t.Fatalf("Transition back: %v", err)
+	}
+	if got.Status != repository.StatusActive || got.PreviousStatus != "" || got.DeleteAfter != nil {
+		t.Fatalf("Transition back returned %+v, want the previous status restored", got)
+	}
+
+	until := time.Now().Add(time.Hour)
+	suspension := &repository.Suspension{Reason: "$reason", Until: &until, At: time.Now()}
+
+	got, err = r.Transition(ctx, id, []string{repository.StatusActive}, repository.StatusChange{Status: repository.StatusSuspended, KeepStatus: true, Suspension: suspension})
+	if err != nil {
+		t.Fatalf("Transition to suspended: %v", err)
+	}
+	if got.Status != repository.StatusSuspended || got.PreviousStatus != repository.StatusActive || got.Suspension == nil || got.Suspension.Reason != suspension.Reason || got.Suspension.Until == nil {
+		t.Fatalf("Transition to suspended returned %+v", got)
+	}
+
+	// Keeping the status it already has leaves the kept one as it is.
+	got, err = r.Transition(ctx, id, []string{repository.StatusSuspended}, repository.StatusChange{Status: repository.StatusSuspended, KeepStatus: true, Suspension: suspension})
+	if err != nil {
+		t.Fatalf("Transition to suspended again: %v", err)
+	}
+	if got.Status != repository.StatusSuspended || got.PreviousStatus != repository.StatusActive {
+		t.Fatalf("Transition to suspended again returned %+v, want the previous status kept", got)
+	}
+
+	got, err = r.Transition(ctx, id, []string{repository.StatusSuspended}, repository.StatusChange{})
+	if err != nil {
+		t.Fatalf("Transition out of suspended: %v", err)
+	}
+	if got.Status != repository.StatusActive || got.PreviousStatus != "" || got.Suspension != nil {
+		t.Fatalf("Transition out of suspended returned %+v, want the previous status restored and the suspension removed", got)
+	}
+
+	// Accounts suspended before being verified go back to being unverified.
+	pending := newAccount(2)
+	pending.Status = repository.StatusPendingVerification
+
+	pendingID, err := r.Create(ctx, pending)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	_, err = r.Transition(ctx, pendingID, []string{repository.StatusPendingVerification}, repository.StatusChange{Status: repository.StatusSuspended, KeepStatus: true, Suspension: suspension})
+	if err != nil {
+		t.Fatalf("Transition to suspended: %v", err)
+	}
+
+	got, err = r.Transition(ctx, pendingID, []string{repository.StatusSuspended}, repository.StatusChange{})
+	if err != nil {
+		t.Fatalf("Transition out of suspended: %v", err)
+	}
+	if got.Status != repository.StatusPendingVerification {
+		t.Fatalf("Transition out of suspended returned %+v, want %s restored", got, repository.StatusPendingVerification)
+	}
+
+	_, err = r.Transition(ctx, primitive.NewObjectID(), []string{repository.StatusActive}, repository.StatusChange{})
+	if err != repository.ErrNotFound {
+		t.Fatalf("Transition of an unknown ID returned %v, want ErrNotFound", err)
+	}
+}
+
+func checkVersions(t *testing.T, ctx context.Context, r repository.UserRepository) {
+	id := create(t, ctx, r, 1)
+
+	profile := &repository.Profile{
+		DisplayName: "$display",
+		Bio:         "Bio",
+		Links:       []repository.ProfileLink{{Label: "Blog", URL: "https://example.com"}, {URL: "https://example.org"}},
+		Timezone:    "Europe/Paris",
+		Version:     42,
+	}
+
+	got, err := r.SetProfile(ctx, id, profile, 0)
+	if err != nil {
+		t.Fatalf("SetProfile: %v", err)
+	}
+	if got.Profile == nil || got.Profile.Version != 1 || got.Profile.DisplayName != profile.DisplayName || len(got.Profile.Links) != 2 || got.Profile.Links[0] != profile.Links[0] || got.Profile.Links[1] != profile.Links[1] {
+		t.Fatalf("SetProfile returned profile %+v, want %+v at version 1", got.Profile, profile)
+	}
+
+	changed := &repository.Profile{Bio: "Changed"}
+
+	got, err = r.SetProfile(ctx, id, changed, 0)
+	var versionErr *repository.VersionError
+	if !errors.As(err, &versionErr) || versionErr.Current != 1 {
+		t.Fatalf("SetProfile at a stale version returned %v, want a VersionError at version 1", err)
+	}
+	if got == nil || got.ID != id || got.Profile == nil || got.Profile.Bio != profile.Bio {
+		t.Fatalf("SetProfile at a stale version returned %+v, want the current account", got)
+	}
+
+	got, err = r.SetProfile(ctx, id, changed, 1)
+	if err != nil {
+		t.Fatalf("SetProfile: %v", err)
+	}
+	if got.Profile.Version != 2 || got.Profile.Bio != changed.Bio || got.Profile.DisplayName != "" || len(got.Profile.Links) != 0 {
+		t.Fatalf("SetProfile returned profile %+v, want it replaced at version 2", got.Profile)
+	}
+
+	prefs := repository.DefaultPreferences()
+	prefs.Theme = repository.ThemeDark
+	prefs.Notifications.SecurityAlerts = false
+	prefs.Privacy.ProfileVisibility = repository.VisibilityHidden
+
+	got, err = r.SetPreferences(ctx, id, prefs, 0)
+	if err != nil {
+		t.Fatalf("SetPreferences: %v", err)
+	}
+	if got.Preferences == nil || got.Preferences.Version != 1 || got.Preferences.Theme != prefs.Theme || got.Preferences.Notifications != prefs.Notifications || got.Preferences.DefaultViews != prefs.DefaultViews || got.Preferences.Privacy != prefs.Privacy {
+		t.Fatalf("SetPreferences returned preferences %+v, want %+v at version 1", got.Preferences, prefs)
+	}
+	if got.Profile == nil || got.Profile.Version != 2 {
+		t.Fatalf("SetPreferences returned profile %+v, want it left at version 2", got.Profile)
+	}
+
+	_, err = r.SetPreferences(ctx, id, prefs, 2)
+	if !errors.As(err, &versionErr) || versionErr.Current != 1 {
+		t.Fatalf("SetPreferences at a future version returned %v, want a VersionError at version 1", err)
+	}
+
+	edit := &repository.EditUserAccount{Name: "Changed"}
+
+	got, err = r.Update(ctx, id, edit, []string{repository.FieldName})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got.Profile == nil || got.Profile.Version != 2 || got.Preferences == nil || got.Preferences.Version != 1 {
+		t.Fatalf("Update of the name returned %+v, want the profile and preferences left alone", got)
+	}
+
+	_, err = r.SetProfile(ctx, primitive.NewObjectID(), changed, 0)
+	if err != repository.ErrNotFound {
+		t.Fatalf("SetProfile of an unknown ID returned %v, want ErrNotFound", err)
+	}
+
+	_, err = r.SetPreferences(ctx, primitive.NewObjectID(), prefs, 0)
+	if err != repository.ErrNotFound {
+		t.Fatalf("SetPreferences of an unknown ID returned %v, want ErrNotFound", err)
+	}
+}
+
+func checkPagination(t *testing.T, ctx context.Context, r repository.UserRepository) {
+	ids := []primitive.ObjectID{}
+
+	for n := 1; n <= 5; n++ {
+		id := create(t, ctx, r, n)
+		ids = append(ids, id)
+	}
+
+	_, err := r.Transition(ctx, ids[2], []string{repository.StatusActive}, repository.StatusChange{
+		Status:     repository.StatusSuspended,
+		Suspension: &repository.Suspension{Reason: "Spam", At: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("Transition to suspended: %v", err)
+	}
+
+	listed := []primitive.ObjectID{}
+	opts := repository.ListOptions{Limit: 2}
+
+	for page := 0; ; page++ {
+		if page > len(ids) {
+			t.Fatal("List does not stop")
+		}
+
+		accounts, err := r.List(ctx, opts)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if len(accounts) > opts.Limit {
+			t.Fatalf("List returned %d accounts, want at most %d", len(accounts), opts.Limit)
+		}
+
+		for _, data := range accounts {
+			listed = append(listed, data.ID)
+		}
+
+		if len(accounts) < opts.Limit {
+			break
+		}
+
+		opts.After = accounts[len(accounts)-1].ID
+	}
+
+	want := []primitive.ObjectID{ids[0], ids[1], ids[3], ids[4]}
+	if fmt.Sprint(listed) != fmt.Sprint(want) {
+		t.Fatalf("List returned %v, want the active accounts %v in order", listed, want)
+	}
+
+	all, err := r.List(ctx, repository.ListOptions{IncludeInactive: true})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(all) != len(ids) {
+		t.Fatalf("List of inactive accounts returned %d accounts, want %d", len(all), len(ids))
+	}
+}
+
+func checkPurge(t *testing.T, ctx context.Context, r repository.UserRepository) {
+	due := create(t, ctx, r, 1)
+
+	notDue := create(t, ctx, r, 2)
+
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+
+	for id, deleteAfter := range map[primitive.ObjectID]*time.Time{due: &past, notDue: &future} {
+		_, err := r.Transition(ctx, id, []string{repository.StatusActive}, repository.StatusChange{
+			Status:      repository.StatusPendingDeletion,
+			KeepStatus:  true,
+			DeleteAfter: deleteAfter,
+		})
+		if err != nil {
+			t.Fatalf("Transition to pending deletion: %v", err)
+		}
+	}
+
+	purged, err := r.Purge(ctx, now)
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if len(purged) != 1 || purged[0] != due {
+		t.Fatalf("Purge returned %v, want [%v]", purged, due)
+	}
+
+	_, err = r.Get(ctx, due)
+	if err != repository.ErrNotFound {
+		t.Fatalf("Get of a purged account returned %v, want ErrNotFound", err)
+	}
+
+	_, err = r.Get(ctx, notDue)
+	if err != nil {
+		t.Fatalf("Get of an account not yet due: %v", err)
+	}
+
+	all, err := r.List(ctx, repository.ListOptions{IncludeInactive: true})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(all) != 1 {
+		t.Fatalf("List returned %d accounts after a purge, want 1", len(all))
+	}
+
+	// The email of a purged account is free again.
+	create(t, ctx, r, 1)
+
+	purged, err = r.Purge(ctx, now)
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if len(purged) != 0 {
+		t.Fatalf("Purge returned %v again, want none", purged)
+	}
+
+	// Purged accounts are pending cleanup until it is confirmed.
+	pending, err := r.PendingCleanup(ctx)
+	if err != nil {
+		t.Fatalf("PendingCleanup: %v", err)
+	}
+	if len(pending) != 1 || pending[0] != due {
+		t.Fatalf("PendingCleanup returned %v, want [%v]", pending, due)
+	}
+
+	err = r.ConfirmCleanup(ctx, []primitive.ObjectID{due, notDue})
+	if err != nil {
+		t.Fatalf("ConfirmCleanup: %v", err)
+	}
+
+	pending, err = r.PendingCleanup(ctx)
+	if err != nil {
+		t.Fatalf("PendingCleanup: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("PendingCleanup returned %v after ConfirmCleanup, want none", pending)
+	}
+}
+
+func checkExport(t *testing.T, ctx context.Context, r repository.UserRepository) {
+	id := create(t, ctx, r, 1)
+
+	type document struct {
+		collection string
+		fields     bson.M
+	}
+
+	documents := []document{}
+
+	err := r.Export(ctx, id, func(collection string, data []byte) error {
+		fields := bson.M{}
+
+		err := bson.UnmarshalExtJSON(data, false, &fields)
+		if err != nil {
+			return err
+		}
+
+		documents = append(documents, document{collection, fields})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	if len(documents) == 0 || documents[0].collection != "users" {
+		t.Fatalf("Export sent %v, want the account first", documents)
+	}
+
+	account := documents[0].fields
+	if account["email"] != newAccount(1).Email {
+		t.Fatalf("Export sent %v, want the account created", account)
+	}
+	if _, ok := account["password"]; ok {
+		t.Fatal("Export sent the password")
+	}
+
+	err = r.Export(ctx, primitive.NewObjectID(), func(string, []byte) error { return nil })
+	if err != repository.ErrNotFound {
+		t.Fatalf("Export of an unknown ID returned %v, want ErrNotFound", err)
+	}
+}
+
+// insertEvents appends events in order, a millisecond apart from start.
+func insertEvents(t *testing.T, ctx context.Context, r repository.AuditRepository, start time.Time, events ...repository.AuditEvent) {
+	t.Helper()
+
+	for i, e := range events {
+		e.Time = start.Add(time.Duration(i) * time.Millisecond)
+		e.Outcome = repository.OutcomeSuccess
+
+		err := r.Insert(ctx, &e)
+		if err != nil {
+			t.Fatalf("Insert: %v", err)
+		}
+	}
+}
+
+func actions(events []*repository.AuditEvent) []string {
+	names := []string{}
+	for _, e := range events {
+		names = append(names, e.Action)
+	}
+	return names
+}
+
+func checkAuditFilters(t *testing.T, ctx context.Context, r repository.AuditRepository) {
+	start := time.Now().UTC().Truncate(time.Millisecond)
+
+	insertEvents(t, ctx, r, start,
+		repository.AuditEvent{Action: "a", ActorID: "alice", TargetID: "alice"},
+		repository.AuditEvent{Action: "b", ActorID: "admin", TargetID: "alice"},
+		repository.AuditEvent{Action: "c", ActorID: "bob", TargetID: "bob"},
+		repository.AuditEvent{Action: "a", ActorID: "alice", TargetID: "bob"},
+	)
+
+	since := start.Add(time.Millisecond)
+	until := start.Add(3 * time.Millisecond)
+
+	cases := []struct {
+		q    repository.AuditQuery
+		want string
+	}{
+		{repository.AuditQuery{}, "[a c b a]"},
+		{repository.AuditQuery{ActorID: "alice"}, "[a a]"},
+		{repository.AuditQuery{TargetID: "alice"}, "[b a]"},
+		{repository.AuditQuery{UserID: "alice"}, "[a b a]"},
+		{repository.AuditQuery{UserID: "bob", Action: "a"}, "[a]"},
+		{repository.AuditQuery{Since: &since, Until: &until}, "[c b]"},
+	}
+
+	for _, c := range cases {
+		events, more, err := r.List(ctx, c.q)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if got := fmt.Sprint(actions(events)); got != c.want || more {
+			t.Fatalf("List of %+v returned %s (more: %t), want %s", c.q, got, more, c.want)
+		}
+	}
+}
+
+func checkAuditPagination(t *testing.T, ctx context.Context, r repository.AuditRepository) {
+	start := time.Now().UTC().Truncate(time.Millisecond)
+
+	insertEvents(t, ctx, r, start,
+		repository.AuditEvent{Action: "1", TargetID: "alice"},
+		repository.AuditEvent{Action: "2", TargetID: "alice"},
+		repository.AuditEvent{Action: "3", TargetID: "alice"},
+	)
+
+	q := repository.AuditQuery{TargetID: "alice", Limit: 2}
+
+	events, more, err := r.List(ctx, q)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if got := fmt.Sprint(actions(events)); got != "[3 2]" || !more {
+		t.Fatalf("List of the first page returned %s (more: %t), want [3 2] and more", got, more)
+	}
+
+	q.After = events[len(events)-1].ID
+
+	events, more, err = r.List(ctx, q)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if got := fmt.Sprint(actions(events)); got != "[1]" || more {
+		t.Fatalf("List of the last page returned %s (more: %t), want [1] and no more", got, more)
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	userpb "github.com/allen-woods/the-supertask/services/user/proto"
+	"github.com/allen-woods/the-supertask/services/user/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Metadata keys under which the API passes who is behind a call, so that the
+// call can be audited.
+const (
+	auditActorIDKey   = "audit-actor-id"
+	auditIPKey        = "audit-ip"
+	auditUserAgentKey = "audit-user-agent"
+)
+
+// Actions audited by the User service.
+const (
+	actionSignedUp          = "user.signedUp"
+	actionLoggedIn          = "user.loggedIn"
+	actionReauthenticated   = "user.reauthenticated"
+	actionUpdated           = "user.updated"
+	actionPasswordChanged   = "user.passwordChanged"
+	actionDeletionRequested = "user.deletionRequested"
+	actionDeletionCancelled = "user.deletionCancelled"
+	actionSuspended         = "user.suspended"
+	actionReinstated        = "user.reinstated"
+	actionPurged            = "user.purged"
+	actionDataExported      = "user.dataExported"
+)
+
+// Bounds on the page size of ListAuditEvents.
+const (
+	defaultAuditPageSize = 20
+	maxAuditPageSize     = 100
+)
+
+// recordAuditEvent appends e to the audit log. The caller, IP and user agent
+// are taken from the call metadata unless set. Failing to audit an action does
+// not fail the action, so errors are only logged.
+func (s *UserCRUDService) recordAuditEvent(ctx context.Context, e repository.AuditEvent) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	first := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	if e.ActorID == "" {
+		e.ActorID = first(auditActorIDKey)
+	}
+	if e.IP == "" {
+		e.IP = first(auditIPKey)
+	}
+	if e.UserAgent == "" {
+		e.UserAgent = first(auditUserAgentKey)
+	}
+
+	e.Time = time.Now().UTC()
+
+	// The action already happened, so it is audited even if the call was
+	// cancelled in the meantime.
+	insertCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := s.audit.Insert(insertCtx, &e)
+	if err != nil {
+		logging.FromContext(ctx).Error("Unable to record audit event",
+			zap.String("action", e.Action),
+			zap.String("targetId", e.TargetID),
+			zap.Error(err),
+		)
+	}
+}
+
+// RecordAuditEvent appends an event reported by another service, such as the
+// API, to the audit log.
+func (s *UserCRUDService) RecordAuditEvent(ctx context.Context, req *userpb.RecordAuditEventReq) (*userpb.RecordAuditEventRes, error) {
+	event := req.GetEvent()
+
+	if event.GetAction() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Audit events must have an action")
+	}
+
+	e := repository.AuditEvent{
+		Action:    event.GetAction(),
+		ActorID:   event.GetActorId(),
+		TargetID:  event.GetTargetId(),
+		IP:        event.GetIp(),
+		UserAgent: event.GetUserAgent(),
+		Outcome:   repository.OutcomeSuccess,
+		Detail:    event.GetDetail(),
+	}
+
+	if event.GetOutcome() == userpb.AuditOutcome_AUDIT_OUTCOME_FAILURE {
+		e.Outcome = repository.OutcomeFailure
+	}
+
+	s.recordAuditEvent(ctx, e)
+
+	return &userpb.RecordAuditEventRes{}, nil
+}
+
+// ListAuditEvents pages through the audit log, newest first.
+func (s *UserCRUDService) ListAuditEvents(ctx context.Context, req *userpb.ListAuditEventsReq) (*userpb.ListAuditEventsRes, error) {
+	q := repository.AuditQuery{
+		ActorID:  req.GetActorId(),
+		TargetID: req.GetTargetId(),
+		UserID:   req.GetUserId(),
+		Action:   req.GetAction(),
+		Limit:    int(req.GetFirst()),
+	}
+
+	if req.GetSince() != nil {
+		since := req.GetSince().AsTime()
+		q.Since = &since
+	}
+	if req.GetUntil() != nil {
+		until := req.GetUntil().AsTime()
+		q.Until = &until
+	}
+
+	if req.GetAfter() != "" {
+		after, err := primitive.ObjectIDFromHex(req.GetAfter())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Invalid cursor: %v", err))
+		}
+		q.After = after
+	}
+
+	if q.Limit <= 0 {
+		q.Limit = defaultAuditPageSize
+	}
+	if q.Limit > maxAuditPageSize {
+		q.Limit = maxAuditPageSize
+	}
+
+	found, hasNextPage, err := s.audit.List(ctx, q)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Unknown internal error: %v", err))
+	}
+
+	events := []*userpb.AuditEvent{}
+
+	for _, e := range found {
+		events = append(events, auditEventMessage(e))
+	}
+
+	return &userpb.ListAuditEventsRes{
+		Events:      events,
+		HasNextPage: hasNextPage,
+	}, nil
+}
+
+func auditEventMessage(e *repository.AuditEvent) *userpb.AuditEvent {
+	outcome := userpb.AuditOutcome_AUDIT_OUTCOME_SUCCESS
+	if e.Outcome == repository.OutcomeFailure {
+		outcome = userpb.AuditOutcome_AUDIT_OUTCOME_FAILURE
+	}
+
+	return &userpb.AuditEvent{
+		Id:        e.ID.Hex(),
+		Action:    e.Action,
+		ActorId:   e.ActorID,
+		TargetId:  e.TargetID,
+		Ip:        e.IP,
+		UserAgent: e.UserAgent,
+		Time:      timestamppb.New(e.Time),
+		Outcome:   outcome,
+		Detail:    e.Detail,
+	}
+}
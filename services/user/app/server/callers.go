@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	userpb "github.com/allen-woods/the-supertask/services/user/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// callerAPI is the name the API calls the service under.
+const callerAPI = "api"
+
+// Callers present their service token as "authorization: Bearer <token>".
+const (
+	serviceTokenKey    = "authorization"
+	serviceTokenScheme = "Bearer "
+)
+
+// callerKey is the context key of the name of the calling service.
+type callerKey struct{}
+
+// withCaller returns a copy of ctx carrying the name of the calling service.
+func withCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// callerFromContext returns the name of the calling service, or "" when the
+// caller was not authenticated.
+func callerFromContext(ctx context.Context) string {
+	caller, _ := ctx.Value(callerKey{}).(string)
+	return caller
+}
+
+// callers tells which service is behind a call, from the client certificate
+// it presented or else from its service token.
+type callers struct {
+	// names are the callers by DNS name in their client certificate.
+	names map[string]string
+	// tokens are the callers by service token.
+	tokens map[string]string
+}
+
+// newCallers returns callers knowing the API by apiToken, and by apiName in
+// its client certificate. Empty tokens and names are never accepted.
+func newCallers(apiToken string, apiName string) *callers {
+	c := &callers{names: map[string]string{}, tokens: map[string]string{}}
+
+	if apiToken != "" {
+		c.tokens[apiToken] = callerAPI
+	}
+	if apiName != "" {
+		c.names[apiName] = callerAPI
+	}
+
+	return c
+}
+
+// identify returns the name of the service behind the call of ctx, or "" when
+// it is unknown.
+func (c *callers) identify(ctx context.Context) string {
+	// Only certificates verified against the client CA are trusted; any of
+	// their DNS names may be known.
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(info.State.VerifiedChains) > 0 {
+			for _, name := range info.State.VerifiedChains[0][0].DNSNames {
+				if caller, ok := c.names[name]; ok {
+					return caller
+				}
+			}
+		}
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	for _, value := range md.Get(serviceTokenKey) {
+		if !strings.HasPrefix(value, serviceTokenScheme) {
+			continue
+		}
+
+		token := []byte(strings.TrimPrefix(value, serviceTokenScheme))
+
+		// Every token is compared, in constant time, so that the time taken
+		// does not tell how close a guess was.
+		caller := ""
+		for known, name := range c.tokens {
+			if subtle.ConstantTimeCompare(token, []byte(known)) == 1 {
+				caller = name
+			}
+		}
+		if caller != "" {
+			return caller
+		}
+	}
+
+	return ""
+}
+
+// methodRules returns the rules of every method of the services in files, by
+// full method name, such as "/user.UserCRUD/CreateUser". Methods without
+// rules are left out.
+func methodRules(files ...protoreflect.FileDescriptor) map[string]*userpb.MethodRules {
+	rules := map[string]*userpb.MethodRules{}
+
+	for _, file := range files {
+		services := file.Services()
+
+		for i := 0; i < services.Len(); i++ {
+			methods := services.Get(i).Methods()
+
+			for j := 0; j < methods.Len(); j++ {
+				m := methods.Get(j)
+
+				r, _ := proto.GetExtension(m.Options(), userpb.E_Method).(*userpb.MethodRules)
+				if r != nil {
+					rules["/"+string(m.Parent().FullName())+"/"+string(m.Name())] = r
+				}
+			}
+		}
+	}
+
+	return rules
+}
+
+// authorize returns an error unless caller may call the method with the given
+// rules.
+func authorize(rules *userpb.MethodRules, caller string, method string) error {
+	allowed := rules.GetCallers()
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	if caller == "" {
+		return status.Errorf(codes.Unauthenticated, "Caller not authenticated")
+	}
+
+	for _, name := range allowed {
+		if name == caller {
+			return nil
+		}
+	}
+
+	return status.Errorf(codes.PermissionDenied, "Caller %q may not call %s", caller, method)
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/allen-woods/the-supertask/pkg/mtls"
+	"github.com/allen-woods/the-supertask/pkg/settings"
+	"github.com/allen-woods/the-supertask/services/user/config"
+	userpb "github.com/allen-woods/the-supertask/services/user/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// debugCommand is the subcommand calling a running service from a terminal.
+const debugCommand = "debug"
+
+const debugUsage = `Usage:
+  user-service debug [flags] <method> [<request>]
+  user-service debug -list
+
+Calls a method of UserCRUD on a running service, such as ReadUser, with the
+request given as JSON, "-" to read it from standard input, or {} when left
+out. Responses, every message of streams included, are printed as JSON:
+
+  user-service debug ReadUser '{"id": "5f4e3d2c1b0a998877665544"}'
+  user-service debug ListUsers '{"includeInactive": true}'
+
+The connection is secured and authenticated the way the service is
+configured, from the same configuration file and environment variables,
+unless flags say otherwise. The certificate of the service is presented as
+the client certificate, and checked against tls.serverCA.
+
+Flags:
+`
+
+// runDebug runs the debug subcommand with args, and returns its exit status.
+func runDebug(args []string) int {
+	cfg, err := config.Load(nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to read the configuration of the service: %v\n", err)
+		return 2
+	}
+
+	fs := flag.NewFlagSet(debugCommand, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), debugUsage)
+		fs.PrintDefaults()
+	}
+
+	list := fs.Bool("list", false, "list the methods and their request messages")
+	address := fs.String("address", net.JoinHostPort("localhost", strconv.Itoa(cfg.GRPC.Port)), "host:port of the User service")
+	timeout := fs.Duration("timeout", 10*time.Second, "how long to wait for the call to end")
+	// The token is not a default of its flag, lest it is printed in the
+	// usage.
+	rawToken := fs.String("token", "", "service token to call as the API, or a file: or env: reference to it; defaults to auth.apiToken")
+	insecure := fs.Bool("insecure", cfg.TLS.Insecure, "dial without TLS")
+	cert := fs.String("cert", cfg.TLS.Cert, "PEM client certificate")
+	key := fs.String("key", cfg.TLS.Key, "PEM private key of the client certificate")
+	ca := fs.String("ca", cfg.TLS.ServerCA, "PEM certificates of the authorities signing the server certificate")
+	serverName := fs.String("server-name", "", "name expected in the server certificate; defaults to the host of -address")
+
+	err = fs.Parse(args)
+	if err == flag.ErrHelp {
+		return 0
+	}
+	if err != nil {
+		return 2
+	}
+
+	token := cfg.Auth.APIToken
+	if *rawToken != "" {
+		token, err = settings.ParseSecret(*rawToken)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid -token: %v\n", err)
+			return 2
+		}
+	}
+
+	service := userpb.File_user_proto_user_proto.Services().ByName("UserCRUD")
+
+	if *list {
+		listMethods(os.Stdout, service)
+		return 0
+	}
+
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		return 2
+	}
+
+	method := service.Methods().ByName(protoreflect.Name(methodName(fs.Arg(0))))
+	if method == nil {
+		fmt.Fprintf(os.Stderr, "Unknown method %q, see -list\n", fs.Arg(0))
+		return 2
+	}
+	if method.IsStreamingClient() {
+		fmt.Fprintf(os.Stderr, "%s streams requests, which is not supported\n", method.Name())
+		return 2
+	}
+
+	req, err := debugRequest(method, fs.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid request: %v\n", err)
+		return 2
+	}
+
+	creds := grpc.WithInsecure()
+
+	if !*insecure {
+		if *ca == "" {
+			fmt.Fprintln(os.Stderr, "Either -ca or tls.serverCA must be set to check the certificate of the service")
+			return 2
+		}
+
+		certs, err := mtls.Load(mtls.Files{Cert: *cert, Key: *key, CA: *ca})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Unable to load the TLS certificates: %v\n", err)
+			return 1
+		}
+
+		name := *serverName
+		if name == "" {
+			name, _, _ = net.SplitHostPort(*address)
+		}
+
+		creds = grpc.WithTransportCredentials(credentials.NewTLS(certs.ClientConfig(name)))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	if token.Value() != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, serviceTokenKey, serviceTokenScheme+token.Value())
+	}
+
+	conn, err := grpc.DialContext(ctx, *address, creds, grpc.WithBlock())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to connect to %s: %v\n", *address, err)
+		return 1
+	}
+	defer conn.Close()
+
+	err = debugCall(ctx, conn, method, req, os.Stdout)
+	if err != nil {
+		printStatus(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+// methodName returns the bare name of a method given as ReadUser,
+// user.UserCRUD/ReadUser or /user.UserCRUD/ReadUser.
+func methodName(s string) string {
+	return s[strings.LastIndex(s, "/")+1:]
+}
+
+// listMethods writes the methods of service to w, with their request and
+// response messages.
+func listMethods(w io.Writer, service protoreflect.ServiceDescriptor) {
+	methods := service.Methods()
+
+	for i := 0; i < methods.Len(); i++ {
+		m := methods.Get(i)
+
+		output := string(m.Output().Name())
+		if m.IsStreamingServer() {
+			output = "stream " + output
+		}
+
+		fmt.Fprintf(w, "%s(%s) returns (%s)\n", m.Name(), m.Input().Name(), output)
+	}
+}
+
+// debugRequest returns the request of method read from raw, which is JSON, "-"
+// to read it from standard input, or empty for an empty request.
+func debugRequest(method protoreflect.MethodDescriptor, raw string) (proto.Message, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(method.Input().FullName())
+	if err != nil {
+		return nil, err
+	}
+
+	req := mt.New().Interface()
+
+	b := []byte(raw)
+	if raw == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(strings.TrimSpace(string(b))) == 0 {
+		return req, nil
+	}
+
+	err = protojson.Unmarshal(b, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// debugCall calls method with req over conn, and writes every response to w
+// as JSON.
+func debugCall(ctx context.Context, conn *grpc.ClientConn, method protoreflect.MethodDescriptor, req proto.Message, w io.Writer) error {
+	fullMethod := fmt.Sprintf("/%s/%s", method.Parent().FullName(), method.Name())
+
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(method.Output().FullName())
+	if err != nil {
+		return err
+	}
+
+	if !method.IsStreamingServer() {
+		res := mt.New().Interface()
+
+		err := conn.Invoke(ctx, fullMethod, req, res)
+		if err != nil {
+			return err
+		}
+
+		return printMessage(w, res)
+	}
+
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, fullMethod)
+	if err != nil {
+		return err
+	}
+
+	err = stream.SendMsg(req)
+	if err != nil {
+		return err
+	}
+
+	err = stream.CloseSend()
+	if err != nil {
+		return err
+	}
+
+	for {
+		res := mt.New().Interface()
+
+		err := stream.RecvMsg(res)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = printMessage(w, res)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// printMessage writes m to w as indented JSON.
+func printMessage(w io.Writer, m proto.Message) error {
+	b, err := protojson.MarshalOptions{Multiline: true, Indent: "  ", EmitUnpopulated: true}.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s\n", b)
+	return err
+}
+
+// printStatus writes the status of the failed call err to w, as JSON along
+// with its details, if any.
+func printStatus(w io.Writer, err error) {
+	s := status.Convert(err)
+
+	fmt.Fprintf(w, "ERROR: %s: %s\n", s.Code(), s.Message())
+
+	if len(s.Proto().GetDetails()) > 0 {
+		printMessage(w, s.Proto())
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+
+	userpb "github.com/allen-woods/the-supertask/services/user/proto"
+	"github.com/allen-woods/the-supertask/services/user/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// auditEventsCollection is the collection the audit events about a user are
+// exported as.
+const auditEventsCollection = "auditEvents"
+
+// ExportUserData streams every document stored about the user with the given
+// ID: the user document itself, without its password, followed by the
+// documents of each owned collection and the audit events about the user,
+// without the staff behind them. Accounts pending deletion can still be
+// exported until they are purged.
+func (s *UserCRUDService) ExportUserData(req *userpb.ExportUserDataReq, stream userpb.UserCRUD_ExportUserDataServer) error {
+	ctx := stream.Context()
+
+	id, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert ObjectId: %v", err))
+	}
+
+	send := func(collection string, document []byte) error {
+		return stream.Send(&userpb.ExportUserDataRes{
+			Collection: collection,
+			Document:   string(document),
+		})
+	}
+
+	err = s.users.Export(ctx, id, send)
+	// Errors of the stream are passed on as they are.
+	if _, ok := status.FromError(err); !ok {
+		return userError(err, req.GetId())
+	}
+	if err != nil {
+		return err
+	}
+
+	q := repository.AuditQuery{UserID: id.Hex(), Limit: maxAuditPageSize}
+
+	for {
+		events, more, err := s.audit.List(ctx, q)
+		if err != nil {
+			return status.Errorf(codes.Internal, fmt.Sprintf("Could not export %s: %v", auditEventsCollection, err))
+		}
+
+		for _, e := range events {
+			// Staff acting on the account are not identified to its owner,
+			// as in their security activity.
+			if e.ActorID != "" && e.ActorID != id.Hex() {
+				e.ActorID = ""
+				e.IP = ""
+				e.UserAgent = ""
+			}
+
+			document, err := bson.MarshalExtJSON(e, false, false)
+			if err != nil {
+				return status.Errorf(codes.Internal, fmt.Sprintf("Could not encode data: %v", err))
+			}
+
+			err = send(auditEventsCollection, document)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !more {
+			break
+		}
+
+		q.After = events[len(events)-1].ID
+	}
+
+	s.recordAuditEvent(ctx, repository.AuditEvent{Action: actionDataExported, TargetID: id.Hex(), Outcome: repository.OutcomeSuccess})
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// userCRUDServiceName is the name health is reported under for the UserCRUD
+// service, besides the empty name standing for the whole server.
+const userCRUDServiceName = "user.UserCRUD"
+
+// Bounds on checking the connectivity to MongoDB.
+const (
+	healthCheckInterval = 5 * time.Second
+	healthCheckTimeout  = 2 * time.Second
+)
+
+// reportHealth keeps the status reported by hs, the grpc.health.v1 service,
+// in line with whether MongoDB can be reached. Once notReady is closed, on
+// shutdown, every service is reported NOT_SERVING for good.
+func reportHealth(hs *health.Server, db *mongo.Client, notReady <-chan struct{}) {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	reachable := true
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+		err := db.Ping(ctx, readpref.Primary())
+		cancel()
+
+		status := healthpb.HealthCheckResponse_SERVING
+
+		switch {
+		case err != nil:
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+			if reachable {
+				zap.L().Warn("Unable to reach MongoDB, reporting NOT_SERVING", zap.Error(err))
+			}
+		case !reachable:
+			zap.L().Info("MongoDB is reachable again, reporting SERVING")
+		}
+
+		reachable = err == nil
+
+		// The shutdown may have started during the ping, and must not be
+		// reported SERVING over.
+		select {
+		case <-notReady:
+			hs.Shutdown()
+			return
+		default:
+		}
+
+		hs.SetServingStatus("", status)
+		hs.SetServingStatus(userCRUDServiceName, status)
+
+		select {
+		case <-notReady:
+			hs.Shutdown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	userpb "github.com/allen-woods/the-supertask/services/user/proto"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// interceptors are the steps every RPC goes through before reaching the
+// service, in this order:
+//
+//  1. the calling service is identified, for the later steps and the logs;
+//  2. the call is logged once it has ended;
+//  3. panics are turned into Internal errors;
+//  4. callers not allowed by the rules of the method are refused;
+//  5. the deadline is capped by the timeout of the method;
+//  6. requests breaking the rules of their fields are refused.
+type interceptors struct {
+	callers *callers
+	rules   map[string]*userpb.MethodRules
+
+	// defaultTimeout caps the deadline of unary methods without a timeout
+	// of their own; 0 leaves it uncapped.
+	defaultTimeout time.Duration
+}
+
+// unary runs a unary call through every step.
+func (i *interceptors) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res interface{}, err error) {
+	ctx = i.identify(ctx)
+	start := time.Now()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(ctx, r)
+		}
+		logCall(ctx, info.FullMethod, start, err)
+	}()
+
+	rules := i.rules[info.FullMethod]
+
+	err = authorize(rules, callerFromContext(ctx), info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := rules.GetTimeout().AsDuration()
+	if rules.GetTimeout() == nil {
+		timeout = i.defaultTimeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err = validate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// stream runs a streaming call through every step. Streams are only given a
+// deadline by the timeout of their method, and every message received is
+// validated.
+func (i *interceptors) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	ctx := i.identify(ss.Context())
+	start := time.Now()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(ctx, r)
+		}
+		logCall(ctx, info.FullMethod, start, err)
+	}()
+
+	rules := i.rules[info.FullMethod]
+
+	err = authorize(rules, callerFromContext(ctx), info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	if rules.GetTimeout() != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, rules.GetTimeout().AsDuration())
+		defer cancel()
+	}
+
+	return handler(srv, &validatingStream{ServerStream: ss, ctx: ctx})
+}
+
+// identify returns a copy of ctx carrying the calling service, which its
+// logger adds to every line.
+func (i *interceptors) identify(ctx context.Context) context.Context {
+	caller := i.callers.identify(ctx)
+	if caller == "" {
+		return ctx
+	}
+
+	ctx = withCaller(ctx, caller)
+	return logging.WithLogger(ctx, logging.FromContext(ctx).With(zap.String("callerService", caller)))
+}
+
+// recovered logs the panic r of a call, and returns the error the caller is
+// given instead, which tells nothing about it.
+func recovered(ctx context.Context, r interface{}) error {
+	logging.FromContext(ctx).Error("Recovered from panic", zap.Any("panic", r), zap.Stack("stack"))
+
+	return status.Errorf(codes.Internal, "Unknown internal error")
+}
+
+// serverErrors are the codes that are the fault of the service rather than of
+// its caller, and logged as errors.
+var serverErrors = map[codes.Code]bool{
+	codes.Unknown:          true,
+	codes.Internal:         true,
+	codes.Unavailable:      true,
+	codes.DataLoss:         true,
+	codes.Unimplemented:    true,
+	codes.DeadlineExceeded: true,
+}
+
+// quietMethods are polled, and only logged at the debug level when they
+// succeed.
+var quietMethods = map[string]bool{
+	"/grpc.health.v1.Health/Check": true,
+}
+
+// logCall writes the access log line of a call of method that started at
+// start and ended with err.
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+
+	level := zapcore.InfoLevel
+	switch {
+	case code == codes.OK && quietMethods[method]:
+		level = zapcore.DebugLevel
+	case serverErrors[code]:
+		level = zapcore.ErrorLevel
+	case code != codes.OK:
+		level = zapcore.WarnLevel
+	}
+
+	ce := logging.FromContext(ctx).Check(level, "Handled RPC")
+	if ce == nil {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("code", code.String()),
+		zap.Duration("duration", time.Since(start)),
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		fields = append(fields, zap.String("peer", p.Addr.String()))
+	}
+	if err != nil {
+		fields = append(fields, zap.String("error", status.Convert(err).Message()))
+	}
+
+	ce.Write(fields...)
+}
+
+// validatingStream is a grpc.ServerStream with another context, validating
+// every message it receives.
+type validatingStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *validatingStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *validatingStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err != nil {
+		return err
+	}
+
+	return validate(m)
+}
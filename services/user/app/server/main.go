@@ -2,53 +2,134 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
-	"os/signal"
-
-	userpb "../proto"
-	"go.mongodb.org/mongo-driver/bson"
+	"time"
+
+	"github.com/allen-woods/the-supertask/pkg/lifecycle"
+	"github.com/allen-woods/the-supertask/pkg/logging"
+	"github.com/allen-woods/the-supertask/pkg/mtls"
+	"github.com/allen-woods/the-supertask/pkg/tracing"
+	"github.com/allen-woods/the-supertask/services/user/config"
+	userpb "github.com/allen-woods/the-supertask/services/user/proto"
+	"github.com/allen-woods/the-supertask/services/user/repository"
+	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc"
+	channelzsvc "google.golang.org/grpc/channelz/service"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// listPageSize is how many accounts ListUsers reads from the repository at a
+// time.
+const listPageSize = 100
+
+// ownedCollections are the collections holding documents that belong to a
+// user through their "ownerId" field. They are exported and purged along with
+// the user. None is yet: the data exports and avatars of a user are kept by
+// the API, which deletes them once PurgeUsers has returned the user, until it
+// confirms so with ConfirmPurgedUsers.
+var ownedCollections = []string{}
+
 // UserCRUDService is the server struct of the User gRPC microservice.
-type UserCRUDService struct{}
+type UserCRUDService struct {
+	users repository.UserRepository
+	audit repository.AuditRepository
+
+	deletionGracePeriod    time.Duration
+	userNameRedirectPeriod time.Duration
+}
+
+// NewUserCRUDService returns a service storing accounts in users and auditing
+// what happens to them in audit. Deleted accounts are purged once
+// deletionGracePeriod has passed. Former user names find their account, and
+// are kept from others, until userNameRedirectPeriod has passed.
+func NewUserCRUDService(users repository.UserRepository, audit repository.AuditRepository, deletionGracePeriod time.Duration, userNameRedirectPeriod time.Duration) *UserCRUDService {
+	return &UserCRUDService{
+		users:                  users,
+		audit:                  audit,
+		deletionGracePeriod:    deletionGracePeriod,
+		userNameRedirectPeriod: userNameRedirectPeriod,
+	}
+}
+
+// userError converts an error of the user repository to the status returned
+// by the service.
+func userError(err error, id string) error {
+	switch e := err.(type) {
+	case *repository.DuplicateError:
+		return status.Errorf(codes.AlreadyExists, fmt.Sprintf("Another user has the same %s", e.Field))
+	case *repository.TransitionError:
+		return status.Errorf(codes.FailedPrecondition, fmt.Sprintf("User with ID %s is %s", id, e.Status))
+	case *repository.VersionError:
+		return status.Errorf(codes.Aborted, fmt.Sprintf("The %s of user with ID %s was changed in the meantime, it is now at version %d", e.Field, id, e.Current))
+	}
+
+	switch err {
+	case repository.ErrNotFound:
+		return status.Errorf(codes.NotFound, fmt.Sprintf("Could not find user with ID %s", id))
+	case repository.ErrUnknownField:
+		return status.Errorf(codes.InvalidArgument, fmt.Sprintf("Invalid update mask: %v", err))
+	}
+
+	return status.Errorf(codes.Internal, fmt.Sprintf("Unknown internal error: %v", err))
+}
 
 // CreateUser is the "create" method for User CRUD in the User gRPC microservice.
 func (s *UserCRUDService) CreateUser(ctx context.Context, req *userpb.CreateUserReq) (*userpb.CreateUserRes, error) {
 	user := req.GetUser()
 
-	data := NewUserAccount{
+	data := &repository.NewUserAccount{
 		Email:    user.GetEmail(),
 		Name:     user.GetName(),
 		UserName: user.GetUserName(),
 		Password: user.GetPassword(),
+		Status:   repository.StatusActive,
+		Role:     repository.RoleUser,
 	}
 
-	result, err := userdb.InsertOne(mongoCtx, data)
+	err := s.checkUserName(ctx, primitive.NilObjectID, data.UserName)
 	if err != nil {
-		return nil, status.Errorf(
-			codes.Internal,
-			fmt.Sprintf("Internal error: %v", err),
-		)
+		return nil, err
 	}
 
-	id := result.InsertedID.(primitive.ObjectID)
+	id, err := s.users.Create(ctx, data)
+	if err != nil {
+		return nil, userError(err, "")
+	}
+
+	s.recordAuditEvent(ctx, repository.AuditEvent{
+		Action:   actionSignedUp,
+		ActorID:  id.Hex(),
+		TargetID: id.Hex(),
+		Outcome:  repository.OutcomeSuccess,
+	})
 
 	response := &userpb.CreateUserRes{
-		User: &userpb.User{
-			Id:       id.Hex(),
+		User: userMessage(&repository.UserAccount{
+			ID:       id,
 			Email:    data.Email,
 			Name:     data.Name,
 			UserName: data.UserName,
-		},
+			Status:   data.Status,
+			Role:     data.Role,
+		}),
 	}
 
 	return response, nil
@@ -61,27 +142,58 @@ func (s *UserCRUDService) ReadUser(ctx context.Context, req *userpb.ReadUserReq)
 		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert to ObjectId: %v", err))
 	}
 
-	result := userdb.FindOne(ctx, bson.M{"_id": id})
-
-	data := UserAccount{}
+	data, err := s.users.Get(ctx, id)
+	if err != nil {
+		return nil, userError(err, req.GetId())
+	}
 
-	if err := result.Decode(&data); err != nil {
-		return nil, status.Errorf(codes.NotFound, fmt.Sprintf("Could not find user with Object ID %s: %v", req.GetId(), err))
+	data, err = s.reinstateIfSuspensionExpired(ctx, data)
+	if err != nil {
+		return nil, userError(err, req.GetId())
 	}
 
 	response := &userpb.ReadUserRes{
-		User: &userpb.User{
-			Id:       id.Hex(),
-			Email:    data.Email,
-			Name:     data.Name,
-			UserName: data.UserName,
-		},
+		User: userMessage(data),
 	}
 
 	return response, nil
 }
 
+// BatchReadUsers is the "read" method for many users at once, with a single
+// query, so that the API can resolve the users referenced by a GraphQL
+// operation together. Unknown and deleted users are left out.
+func (s *UserCRUDService) BatchReadUsers(ctx context.Context, req *userpb.BatchReadUsersReq) (*userpb.BatchReadUsersRes, error) {
+	ids := make([]primitive.ObjectID, 0, len(req.GetIds()))
+	for _, hex := range req.GetIds() {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert to ObjectId: %v", err))
+		}
+		ids = append(ids, id)
+	}
+
+	accounts, err := s.users.GetMany(ctx, ids)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Unknown internal error: %v", err))
+	}
+
+	res := &userpb.BatchReadUsersRes{Users: make([]*userpb.User, 0, len(accounts))}
+
+	for _, data := range accounts {
+		current, err := s.reinstateIfSuspensionExpired(ctx, data)
+		if err != nil {
+			return nil, userError(err, data.ID.Hex())
+		}
+
+		res.Users = append(res.Users, userMessage(current))
+	}
+
+	return res, nil
+}
+
 // UpdateUser is the "update" method for User CRUD in the User gRPC microservice.
+// Only the fields named by the update mask are changed; without one, every
+// field is.
 func (s *UserCRUDService) UpdateUser(ctx context.Context, req *userpb.UpdateUserReq) (*userpb.UpdateUserRes, error) {
 	user := req.GetUser()
 
@@ -93,183 +205,546 @@ func (s *UserCRUDService) UpdateUser(ctx context.Context, req *userpb.UpdateUser
 		)
 	}
 
-	update := bson.M{
-		"email":    user.GetEmail(),
-		"name":     user.GetName(),
-		"userName": user.GetUserName(),
-		"password": user.GetPassword(),
+	mask := req.GetUpdateMask().GetPaths()
+	if len(mask) == 0 {
+		mask = repository.UpdatableFields
 	}
 
-	filter := bson.M{"_id": id}
-
-	result := userdb.FindOneAndUpdate(ctx, filter, bson.M{"$set": update}, options.FindOneAndUpdate().SetReturnDocument(1))
+	data := &repository.EditUserAccount{
+		Email:    user.GetEmail(),
+		Name:     user.GetName(),
+		UserName: user.GetUserName(),
+		Password: user.GetPassword(),
+		Avatar:   user.GetAvatar(),
+	}
 
-	decoded := UserAccount{}
+	for _, field := range mask {
+		if field == repository.FieldUserName {
+			err := s.checkUserName(ctx, id, data.UserName)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
 
-	err = result.Decode(&decoded)
+	updated, err := s.users.Update(ctx, id, data, mask)
 	if err != nil {
-		return nil, status.Errorf(
-			codes.NotFound,
-			fmt.Sprintf("Could not find user with supplied ID: %v", err),
-		)
+		return nil, userError(err, user.GetId())
+	}
+
+	s.recordAuditEvent(ctx, repository.AuditEvent{Action: actionUpdated, TargetID: id.Hex(), Outcome: repository.OutcomeSuccess})
+
+	for _, field := range mask {
+		if field == repository.FieldPassword && data.Password != "" {
+			s.recordAuditEvent(ctx, repository.AuditEvent{Action: actionPasswordChanged, TargetID: id.Hex(), Outcome: repository.OutcomeSuccess})
+		}
 	}
 
 	return &userpb.UpdateUserRes{
-		User: &userpb.User{
-			Id:       decoded.ID.Hex(),
-			Email:    decoded.Email,
-			Name:     decoded.Name,
-			UserName: decoded.UserName,
-		},
+		User: userMessage(updated),
 	}, nil
 }
 
 // DeleteUser is the "delete" method for User CRUD in the User gRPC microservice.
+// It moves the account to pending deletion until the grace period has passed;
+// the account is only purged from the database by PurgeUsers.
 func (s *UserCRUDService) DeleteUser(ctx context.Context, req *userpb.DeleteUserReq) (*userpb.DeleteUserRes, error) {
 	id, err := primitive.ObjectIDFromHex(req.GetId())
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert ObjectId: %v", err))
 	}
 
-	_, err = userdb.DeleteOne(ctx, bson.M{"_id": id})
+	deleteAfter := time.Now().Add(s.deletionGracePeriod).UTC()
+
+	// The previous status is kept, to be restored should the deletion be
+	// cancelled.
+	change := repository.StatusChange{
+		Status:      repository.StatusPendingDeletion,
+		KeepStatus:  true,
+		DeleteAfter: &deleteAfter,
+	}
+
+	data, err := s.users.Transition(ctx, id, []string{repository.StatusPendingVerification, repository.StatusActive}, change)
+	if e, ok := err.(*repository.TransitionError); ok && e.Status == repository.StatusPendingDeletion {
+		// Deleting twice keeps the original schedule.
+		err = nil
+	}
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, fmt.Sprintf("Could not find/delete user with ID %s: %v", req.GetId(), err))
+		return nil, userError(err, req.GetId())
 	}
 
+	s.recordAuditEvent(ctx, repository.AuditEvent{
+		Action:   actionDeletionRequested,
+		TargetID: id.Hex(),
+		Outcome:  repository.OutcomeSuccess,
+		Detail:   "Purge scheduled after " + data.DeleteAfter.Format(time.RFC3339),
+	})
+
 	return &userpb.DeleteUserRes{
-		Success: true,
+		Success:     true,
+		DeleteAfter: timestamppb.New(*data.DeleteAfter),
 	}, nil
 }
 
-// ListUsers is the "index" method for the User gRPC microservice.
-func (s *UserCRUDService) ListUsers(req *userpb.ListUsersReq, stream userpb.UserCRUDService_ListUsersServer) error {
-	data := &UserAccount{}
+// CancelUserDeletion reverts a DeleteUser request that is still in its grace
+// period, restoring the status the account had before.
+func (s *UserCRUDService) CancelUserDeletion(ctx context.Context, req *userpb.CancelUserDeletionReq) (*userpb.CancelUserDeletionRes, error) {
+	id, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert ObjectId: %v", err))
+	}
 
-	cursor, err := userdb.Find(context.Background(), bson.M{})
+	data, err := s.users.Transition(ctx, id, []string{repository.StatusPendingDeletion}, repository.StatusChange{})
 	if err != nil {
-		return status.Errorf(codes.Internal, fmt.Sprintf("Unknown internal error: %v", err))
+		return nil, userError(err, req.GetId())
 	}
 
-	defer cursor.Close(context.Background())
+	s.recordAuditEvent(ctx, repository.AuditEvent{Action: actionDeletionCancelled, TargetID: id.Hex(), Outcome: repository.OutcomeSuccess})
 
-	for cursor.Next(context.Background()) {
-		err := cursor.Decode(data)
-		if err != nil {
-			return status.Errorf(codes.Unavailable, fmt.Sprintf("Could not decode data: %v", err))
+	return &userpb.CancelUserDeletionRes{
+		User: userMessage(data),
+	}, nil
+}
+
+// SuspendUser stops a user from logging in, either until the given time or
+// until the user is reinstated.
+func (s *UserCRUDService) SuspendUser(ctx context.Context, req *userpb.SuspendUserReq) (*userpb.SuspendUserRes, error) {
+	id, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert ObjectId: %v", err))
+	}
+
+	if req.GetReason() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "A reason for the suspension is required")
+	}
+
+	suspension := &repository.Suspension{
+		Reason: req.GetReason(),
+		At:     time.Now().UTC(),
+	}
+
+	if req.GetUntil() != nil {
+		until := req.GetUntil().AsTime()
+		if !until.After(suspension.At) {
+			return nil, status.Errorf(codes.InvalidArgument, "A suspension must end in the future")
 		}
+		suspension.Until = &until
+	}
 
-		stream.Send(&userpb.ListUsersRes{
-			User: &userpb.User{
-				Id:       data.ID.Hex(),
-				Email:    data.Email,
-				Name:     data.Name,
-				UserName: data.UserName,
-			},
-		})
+	change := repository.StatusChange{
+		Status:     repository.StatusSuspended,
+		KeepStatus: true,
+		Suspension: suspension,
+	}
+
+	// Suspending a suspended user replaces the reason and end of the
+	// suspension, and keeps the status from before the first one.
+	data, err := s.users.Transition(ctx, id, []string{repository.StatusPendingVerification, repository.StatusActive, repository.StatusSuspended}, change)
+	if err != nil {
+		return nil, userError(err, req.GetId())
 	}
 
-	if err := cursor.Err(); err != nil {
-		return status.Errorf(codes.Internal, fmt.Sprintf("Unknown cursor error: %v", err))
+	detail := "Reason: " + suspension.Reason
+	if suspension.Until != nil {
+		detail += " (until " + suspension.Until.Format(time.RFC3339) + ")"
 	}
 
-	return nil
+	s.recordAuditEvent(ctx, repository.AuditEvent{Action: actionSuspended, TargetID: id.Hex(), Outcome: repository.OutcomeSuccess, Detail: detail})
+
+	return &userpb.SuspendUserRes{
+		User: userMessage(data),
+	}, nil
 }
 
-// NewUserAccount is the struct used for a new User signing up. It contains hashed and salted password information.
-type NewUserAccount struct {
-	Email    string `bson:"email"`
-	Name     string `bson:"name"`
-	UserName string `bson:"userName"`
-	Password string `bson:"password"`
+// ReinstateUser lifts the suspension of a user, who gets back the status they
+// had before it.
+func (s *UserCRUDService) ReinstateUser(ctx context.Context, req *userpb.ReinstateUserReq) (*userpb.ReinstateUserRes, error) {
+	id, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert ObjectId: %v", err))
+	}
+
+	data, err := s.users.Transition(ctx, id, []string{repository.StatusSuspended}, repository.StatusChange{})
+	if err != nil {
+		return nil, userError(err, req.GetId())
+	}
+
+	s.recordAuditEvent(ctx, repository.AuditEvent{Action: actionReinstated, TargetID: id.Hex(), Outcome: repository.OutcomeSuccess})
+
+	return &userpb.ReinstateUserRes{
+		User: userMessage(data),
+	}, nil
 }
 
-// UserAccount is the struct used for registered User accounts. It does not contain password information.
-type UserAccount struct {
-	ID       primitive.ObjectID `bson:"_id,omitempty"`
-	Email    string             `bson:"email"`
-	Name     string             `bson:"name"`
-	UserName string             `bson:"userName"`
+// PurgeUsers permanently removes the personal data of every user whose
+// deletion grace period has passed, along with the documents they own, and
+// returns the IDs of every purged user whose cleanup by the API is yet to be
+// confirmed: those purged now, and those whose cleanup failed before.
+func (s *UserCRUDService) PurgeUsers(ctx context.Context, req *userpb.PurgeUsersReq) (*userpb.PurgeUsersRes, error) {
+	purged, err := s.users.Purge(ctx, time.Now())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Could not purge users: %v", err))
+	}
+
+	for _, id := range purged {
+		s.recordAuditEvent(ctx, repository.AuditEvent{Action: actionPurged, TargetID: id.Hex(), Outcome: repository.OutcomeSuccess})
+	}
+
+	pending, err := s.users.PendingCleanup(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Could not list the purged users pending cleanup: %v", err))
+	}
+
+	ids := []string{}
+
+	for _, id := range pending {
+		ids = append(ids, id.Hex())
+	}
+
+	return &userpb.PurgeUsersRes{
+		Ids: ids,
+	}, nil
 }
 
-// EditUserAccount is the struct used for an account that needs to be updated by its owner. It contains hashed and salted password information because this user is "Me" in the API.
-type EditUserAccount struct {
-	ID       primitive.ObjectID `bson:"_id,omitempty"`
-	Email    string             `bson:"email"`
-	Name     string             `bson:"name"`
-	UserName string             `bson:"userName"`
-	Password string             `bson:"password"`
+// ConfirmPurgedUsers records that the API has deleted everything it kept about
+// the given purged users, which PurgeUsers then no longer returns.
+func (s *UserCRUDService) ConfirmPurgedUsers(ctx context.Context, req *userpb.ConfirmPurgedUsersReq) (*userpb.ConfirmPurgedUsersRes, error) {
+	ids := make([]primitive.ObjectID, 0, len(req.GetIds()))
+	for _, hex := range req.GetIds() {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert to ObjectId: %v", err))
+		}
+		ids = append(ids, id)
+	}
+
+	err := s.users.ConfirmCleanup(ctx, ids)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Could not confirm the cleanup of purged users: %v", err))
+	}
+
+	return &userpb.ConfirmPurgedUsersRes{}, nil
 }
 
-var db *mongo.Client
-var userdb *mongo.Collection
-var mongoCtx context.Context
+// AuthenticateUser checks a plain text password against the stored hash of the
+// user with the given ID, or the given email when no ID is supplied. Suspended
+// users are refused even when the password is right.
+func (s *UserCRUDService) AuthenticateUser(ctx context.Context, req *userpb.AuthenticateUserReq) (*userpb.AuthenticateUserRes, error) {
+	var data *repository.EditUserAccount
+	var err error
 
-func main() {
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
+	// Confirming the password of a known user is not logging in.
+	action := actionLoggedIn
 
-	// Use the default port for a gRPC microservice (50051).
-	servicePort := 50051
+	if req.GetId() != "" {
+		action = actionReauthenticated
 
-	// Use the preferred IP of 0.0.0.0 rather than "localhost".
-	serviceIP := "0.0.0.0"
+		id, err := primitive.ObjectIDFromHex(req.GetId())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert ObjectId: %v", err))
+		}
 
-	// Use the default port for MongoDB.
-	mongoPort := 27017
+		// A caller looking up by ID already knows the account, so there
+		// is nothing to hide by saying that it does not exist.
+		data, err = s.users.GetWithPassword(ctx, id)
+		if err != nil {
+			return nil, userError(err, req.GetId())
+		}
+	} else {
+		data, err = s.users.GetByEmailWithPassword(ctx, req.GetEmail())
+		if err != nil && err != repository.ErrNotFound {
+			return nil, userError(err, "")
+		}
+	}
 
-	fmt.Printf("Starting server on port :%d...", servicePort)
+	// Unknown users and wrong passwords get the same error, so that the
+	// response does not reveal which accounts exist.
+	if err == repository.ErrNotFound {
+		s.recordAuditEvent(ctx, repository.AuditEvent{Action: action, Outcome: repository.OutcomeFailure, Detail: "Unknown email"})
+		return nil, status.Errorf(codes.Unauthenticated, "Invalid credentials")
+	}
 
-	listenPort := fmt.Sprintf(":%d", servicePort)
+	if bcrypt.CompareHashAndPassword([]byte(data.Password), []byte(req.GetPassword())) != nil {
+		s.recordAuditEvent(ctx, repository.AuditEvent{Action: action, TargetID: data.ID.Hex(), Outcome: repository.OutcomeFailure, Detail: "Wrong password"})
+		return nil, status.Errorf(codes.Unauthenticated, "Invalid credentials")
+	}
 
-	listener, err := net.Listen("tcp", listenPort)
+	account, err := s.reinstateIfSuspensionExpired(ctx, data.UserAccount())
 	if err != nil {
-		log.Fatalf("Unable to listen on port %d: %v", servicePort, err)
+		return nil, userError(err, data.ID.Hex())
 	}
 
-	opts := []grpc.ServerOption{}
+	if account.Status == repository.StatusSuspended {
+		msg := "Account suspended: " + account.Suspension.Reason
+		if account.Suspension.Until != nil {
+			msg += " (until " + account.Suspension.Until.Format(time.RFC3339) + ")"
+		}
+		s.recordAuditEvent(ctx, repository.AuditEvent{Action: action, TargetID: account.ID.Hex(), Outcome: repository.OutcomeFailure, Detail: "Account suspended"})
+		return nil, status.Error(codes.PermissionDenied, msg)
+	}
 
-	s := grpc.NewServer(opts...)
+	s.recordAuditEvent(ctx, repository.AuditEvent{Action: action, ActorID: account.ID.Hex(), TargetID: account.ID.Hex(), Outcome: repository.OutcomeSuccess})
 
-	srv := &userpb.UserCRUDService{}
+	return &userpb.AuthenticateUserRes{
+		User: userMessage(account),
+	}, nil
+}
 
-	userpb.RegisterUserCRUDService(s, srv)
+// ListUsers is the "index" method for the User gRPC microservice.
+func (s *UserCRUDService) ListUsers(req *userpb.ListUsersReq, stream userpb.UserCRUD_ListUsersServer) error {
+	ctx := stream.Context()
 
-	fmt.Println("Connecting to MongoDB...")
+	opts := repository.ListOptions{
+		IncludeInactive: req.GetIncludeInactive(),
+		Limit:           listPageSize,
+	}
 
-	mongoCtx = context.Background()
+	for {
+		accounts, err := s.users.List(ctx, opts)
+		if err != nil {
+			return status.Errorf(codes.Internal, fmt.Sprintf("Unknown internal error: %v", err))
+		}
 
-	mongoURI := fmt.Sprintf("mongodb://%s:%d", serviceIP, mongoPort)
+		for _, data := range accounts {
+			err := stream.Send(&userpb.ListUsersRes{
+				User: userMessage(data),
+			})
+			if err != nil {
+				return err
+			}
+		}
 
-	db, err = mongo.Connect(mongoCtx, options.Client().ApplyURI(mongoURI))
+		if len(accounts) < opts.Limit {
+			return nil
+		}
+
+		opts.After = accounts[len(accounts)-1].ID
+	}
+}
+
+// userMessage converts a stored account to the message returned by the service.
+func userMessage(data *repository.UserAccount) *userpb.User {
+	user := &userpb.User{
+		Id:          data.ID.Hex(),
+		Email:       data.Email,
+		Name:        data.Name,
+		UserName:    data.UserName,
+		Status:      statusMessage(repository.EffectiveStatus(data)),
+		Role:        roleMessage(data.Role),
+		Avatar:      data.Avatar,
+		Profile:     profileMessage(data.Profile),
+		Preferences: preferencesMessage(data.Preferences),
+	}
+
+	if data.DeleteAfter != nil {
+		user.DeleteAfter = timestamppb.New(*data.DeleteAfter)
+	}
+
+	if user.Status == userpb.Status_STATUS_SUSPENDED && data.Suspension != nil {
+		user.SuspensionReason = data.Suspension.Reason
+
+		if data.Suspension.Until != nil {
+			user.SuspendedUntil = timestamppb.New(*data.Suspension.Until)
+		}
+	}
+
+	return user
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == debugCommand {
+		os.Exit(runDebug(os.Args[2:]))
+	}
+
+	cfg, err := config.Load(os.Args[1:])
+	if err == flag.ErrHelp {
+		return
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
-	err = db.Ping(mongoCtx, nil)
+
+	if cfg.PrintOnly() {
+		err := cfg.Print(os.Stdout)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	logger, err := logging.New(cfg.Logging.Format, cfg.Logging.Level)
 	if err != nil {
-		log.Fatalf("Could not connect to MongoDB:\n%v\n", err)
-	} else {
-		fmt.Println("Connected to MongoDB!")
+		log.Fatalf("Unable to set up logging: %v", err)
 	}
+	defer logger.Sync()
+	defer logging.Install(logger)()
+
+	logger.Info("Starting server", zap.String("address", cfg.ListenAddress()))
 
-	userdb = db.Database("theSupertask").Collection("users")
+	listener, err := net.Listen("tcp", cfg.ListenAddress())
+	if err != nil {
+		logger.Fatal("Unable to listen", zap.String("address", cfg.ListenAddress()), zap.Error(err))
+	}
 
-	go func() {
-		if err := s.Serve(listener); err != nil {
-			log.Fatalf("Failed to serve: %v", err)
+	// gRPC is served over mutual TLS, unless explicitly told otherwise for
+	// development.
+	var certs *mtls.Certificates
+	if cfg.TLS.Insecure {
+		logger.Warn("Serving gRPC without TLS, which is only fit for development")
+	} else {
+		certs, err = mtls.Load(cfg.TLSFiles())
+		if err != nil {
+			logger.Fatal("Unable to load the TLS certificates", zap.Error(err))
 		}
-	}()
-	successMsg := fmt.Sprintf("Server successfully started on port :%d", servicePort)
-	fmt.Println(successMsg)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.TracingOptions())
+	if err != nil {
+		logger.Fatal("Unable to set up tracing", zap.Error(err))
+	}
+
+	logger.Info("Connecting to MongoDB")
+
+	mongoCtx := context.Background()
+
+	db, err := mongo.Connect(mongoCtx, options.Client().ApplyURI(cfg.Mongo.URI.Value()).SetMonitor(mongoMonitor()))
+	if err != nil {
+		logger.Fatal("Unable to create the MongoDB client", zap.Error(err))
+	}
+	err = db.Ping(mongoCtx, nil)
+	if err != nil {
+		logger.Fatal("Could not connect to MongoDB", zap.Error(err))
+	}
+
+	logger.Info("Connected to MongoDB")
+
+	database := db.Database(cfg.Mongo.Database)
+
+	owned := []*mongo.Collection{}
+	for _, name := range ownedCollections {
+		owned = append(owned, database.Collection(name))
+	}
+
+	users, err := repository.NewMongoUserRepository(mongoCtx, database.Collection("users"), owned...)
+	if err != nil {
+		logger.Fatal("Could not create the user indexes", zap.Error(err))
+	}
+
+	audit, err := repository.NewMongoAuditRepository(mongoCtx, database.Collection("auditEvents"), cfg.Audit.Retention)
+	if err != nil {
+		logger.Fatal("Could not create the audit log indexes", zap.Error(err))
+	}
+
+	if cfg.Auth.APIToken.Value() == "" && cfg.TLS.Insecure {
+		logger.Warn("No API token is set without TLS, methods restricted to the API will be refused")
+	}
+
+	// Every RPC is counted and timed, by method and status code, and spanned
+	// under the trace of its caller. Its log lines carry the request ID of
+	// the caller. It then goes through the interceptors of the service.
+	grpc_prometheus.EnableHandlingTimeHistogram()
+
+	chain := &interceptors{
+		callers:        newCallers(cfg.Auth.APIToken.Value(), cfg.Auth.APIName),
+		rules:          methodRules(userpb.File_user_proto_user_proto),
+		defaultTimeout: cfg.GRPC.DefaultTimeout,
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(logging.UnaryServerInterceptor, otelgrpc.UnaryServerInterceptor(), grpc_prometheus.UnaryServerInterceptor, chain.unary),
+		grpc.ChainStreamInterceptor(logging.StreamServerInterceptor, otelgrpc.StreamServerInterceptor(), grpc_prometheus.StreamServerInterceptor, chain.stream),
+	}
+
+	if certs != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(certs.ServerConfig())))
+	}
+	s := grpc.NewServer(opts...)
+
+	svc := NewUserCRUDService(users, audit, cfg.Accounts.DeletionGracePeriod, cfg.Accounts.UserNameRedirectPeriod)
+
+	srv := &userpb.UserCRUDService{
+		CreateUser:         svc.CreateUser,
+		ReadUser:           svc.ReadUser,
+		ReadUserByUserName: svc.ReadUserByUserName,
+		BatchReadUsers:     svc.BatchReadUsers,
+		UpdateUser:         svc.UpdateUser,
+		UpdateProfile:      svc.UpdateProfile,
+		UpdatePreferences:  svc.UpdatePreferences,
+		DeleteUser:         svc.DeleteUser,
+		ListUsers:          svc.ListUsers,
+		CancelUserDeletion: svc.CancelUserDeletion,
+		ExportUserData:     svc.ExportUserData,
+		PurgeUsers:         svc.PurgeUsers,
+		ConfirmPurgedUsers: svc.ConfirmPurgedUsers,
+		AuthenticateUser:   svc.AuthenticateUser,
+		SuspendUser:        svc.SuspendUser,
+		ReinstateUser:      svc.ReinstateUser,
+		RecordAuditEvent:   svc.RecordAuditEvent,
+		ListAuditEvents:    svc.ListAuditEvents,
+	}
+
+	userpb.RegisterUserCRUDService(s, srv)
+
+	// Health is reported NOT_SERVING until MongoDB has been checked, once the
+	// service is ready.
+	hs := health.NewServer()
+	hs.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	hs.SetServingStatus(userCRUDServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(s, hs)
+
+	if cfg.Debug.Reflection {
+		reflection.Register(s)
+	}
+	if cfg.Debug.Channelz {
+		channelzsvc.RegisterChannelzServiceToServer(s)
+	}
 
-	c := make(chan os.Signal)
+	grpc_prometheus.Register(s)
 
-	signal.Notify(c, os.Interrupt)
+	runner := lifecycle.New()
+	runner.DrainTimeout = cfg.Shutdown.DrainTimeout
+	runner.NotReadyDelay = cfg.Shutdown.NotReadyDelay
+	runner.CloseTimeout = cfg.Shutdown.CloseTimeout
 
-	<-c
+	// The gRPC server is drained before the MongoDB client its RPCs use is
+	// disconnected.
+	runner.Go("gRPC server", func() error {
+		return s.Serve(listener)
+	})
+	runner.StopGRPC("gRPC server", s)
+
+	if certs != nil {
+		runner.Go("certificate reloading", func() error {
+			certs.Watch(runner.Context(), mtls.DefaultReloadInterval)
+			return nil
+		})
+	}
+
+	if cfg.Metrics.Port != 0 {
+		runner.ServeHTTP("metrics server", &http.Server{
+			Addr:     cfg.MetricsAddress(),
+			Handler:  promhttp.Handler(),
+			ErrorLog: zap.NewStdLog(logger.Named("metrics")),
+		})
+	}
+
+	runner.OnShutdown("MongoDB client", db.Disconnect)
+	// Spans are flushed last, once every RPC has ended.
+	runner.OnShutdown("tracer provider", shutdownTracing)
+
+	runner.SetReady()
+
+	runner.Go("health reporting", func() error {
+		reportHealth(hs, db, runner.NotReady())
+		return nil
+	})
+
+	logger.Info("Server successfully started", zap.String("address", cfg.ListenAddress()))
+
+	err = runner.Run()
+	if err != nil {
+		logger.Fatal("Unable to shut down cleanly", zap.Error(err))
+	}
 
-	fmt.Println("\nStopping the server...")
-	s.Stop()
-	listener.Close()
-	fmt.Println("Closing MongoDB connection...")
-	db.Disconnect(mongoCtx)
-	fmt.Println("Done.")
+	logger.Info("Done")
 }
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// mongoCommandDuration times the commands sent to MongoDB, such as find or
+// insert, by whether they succeeded.
+var mongoCommandDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "user_service",
+	Subsystem: "mongo",
+	Name:      "command_duration_seconds",
+	Help:      "Duration of MongoDB commands, by command and outcome.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"command", "outcome"})
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName names the spans the service starts itself.
+const tracerName = "github.com/allen-woods/the-supertask/services/user"
+
+// mongoMonitor times every MongoDB command for Prometheus, and spans it under
+// the RPC it was sent for. Spans do not record the commands themselves, which
+// may hold password hashes.
+func mongoMonitor() *event.CommandMonitor {
+	// spans holds the span of every command in flight, by request ID.
+	var spans sync.Map
+
+	finish := func(requestID int64, command string, nanos int64, err string) {
+		outcome := "ok"
+		if err != "" {
+			outcome = "error"
+		}
+
+		mongoCommandDuration.WithLabelValues(command, outcome).Observe(time.Duration(nanos).Seconds())
+
+		span, ok := spans.Load(requestID)
+		if !ok {
+			return
+		}
+		spans.Delete(requestID)
+
+		if err != "" {
+			span.(trace.Span).SetStatus(codes.Error, err)
+		}
+		span.(trace.Span).End()
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			_, span := otel.Tracer(tracerName).Start(ctx, "mongo "+e.CommandName,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					semconv.DBSystemMongodb,
+					semconv.DBNameKey.String(e.DatabaseName),
+					semconv.DBOperationKey.String(e.CommandName),
+				),
+			)
+
+			if collection, ok := commandCollection(e.Command); ok {
+				span.SetAttributes(semconv.DBMongoDBCollectionKey.String(collection))
+			}
+
+			spans.Store(e.RequestID, span)
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			finish(e.RequestID, e.CommandName, e.DurationNanos, "")
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			finish(e.RequestID, e.CommandName, e.DurationNanos, e.Failure)
+		},
+	}
+}
+
+// commandCollection is the collection a command is run on, which is the value
+// of its first element for those that run on one, such as {find: "users"}.
+func commandCollection(command bson.Raw) (string, bool) {
+	elem, err := command.IndexErr(0)
+	if err != nil {
+		return "", false
+	}
+
+	return elem.Value().StringValueOK()
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	userpb "github.com/allen-woods/the-supertask/services/user/proto"
+	"github.com/allen-woods/the-supertask/services/user/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/text/language"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Fields of a profile that UpdateProfile can change, named as in FieldMask
+// paths. Every one of them is changed given no mask.
+var profileFields = []string{"displayName", "bio", "links", "location", "timezone", "locale", "pronouns"}
+
+// Fields of preferences that UpdatePreferences can change, named as in
+// FieldMask paths. Every one of them is changed given no mask.
+var preferenceFields = []string{"theme", "notifications", "defaultViews", "privacy"}
+
+// Stored values of the enums of preferences, by enum.
+var (
+	themes = map[userpb.Theme]string{
+		userpb.Theme_THEME_SYSTEM: repository.ThemeSystem,
+		userpb.Theme_THEME_LIGHT:  repository.ThemeLight,
+		userpb.Theme_THEME_DARK:   repository.ThemeDark,
+	}
+	taskViews = map[userpb.TaskView]string{
+		userpb.TaskView_TASK_VIEW_LIST:     repository.TaskViewList,
+		userpb.TaskView_TASK_VIEW_BOARD:    repository.TaskViewBoard,
+		userpb.TaskView_TASK_VIEW_CALENDAR: repository.TaskViewCalendar,
+	}
+	calendarViews = map[userpb.CalendarView]string{
+		userpb.CalendarView_CALENDAR_VIEW_DAY:   repository.CalendarViewDay,
+		userpb.CalendarView_CALENDAR_VIEW_WEEK:  repository.CalendarViewWeek,
+		userpb.CalendarView_CALENDAR_VIEW_MONTH: repository.CalendarViewMonth,
+	}
+	visibilities = map[userpb.ProfileVisibility]string{
+		userpb.ProfileVisibility_PROFILE_VISIBILITY_PUBLIC:  repository.VisibilityPublic,
+		userpb.ProfileVisibility_PROFILE_VISIBILITY_MEMBERS: repository.VisibilityMembers,
+		userpb.ProfileVisibility_PROFILE_VISIBILITY_HIDDEN:  repository.VisibilityHidden,
+	}
+)
+
+// UpdateProfile changes the fields of the profile of a user named by the
+// update mask, or every field without one. The changes are only stored when
+// the profile is still at the version they were made to, so that two editors
+// cannot overwrite each other unknowingly.
+func (s *UserCRUDService) UpdateProfile(ctx context.Context, req *userpb.UpdateProfileReq) (*userpb.UpdateProfileRes, error) {
+	id, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert ObjectId: %v", err))
+	}
+
+	current, err := s.users.Get(ctx, id)
+	if err != nil {
+		return nil, userError(err, req.GetId())
+	}
+
+	mask := req.GetUpdateMask().GetPaths()
+	if len(mask) == 0 {
+		mask = profileFields
+	}
+
+	profile := &repository.Profile{}
+	if current.Profile != nil {
+		*profile = *current.Profile
+	}
+
+	for _, field := range mask {
+		problem := applyProfileField(profile, req.GetProfile(), field)
+		if problem != "" {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid profile: %s", problem)
+		}
+	}
+
+	updated, err := s.users.SetProfile(ctx, id, profile, req.GetProfile().GetVersion())
+	if err != nil {
+		return nil, userError(err, req.GetId())
+	}
+
+	s.recordAuditEvent(ctx, repository.AuditEvent{
+		Action:   actionUpdated,
+		TargetID: id.Hex(),
+		Outcome:  repository.OutcomeSuccess,
+		Detail:   "Profile: " + strings.Join(mask, ", "),
+	})
+
+	return &userpb.UpdateProfileRes{
+		User: userMessage(updated),
+	}, nil
+}
+
+// applyProfileField copies the field of src named by path to dst, and returns
+// what is wrong with it, or "".
+func applyProfileField(dst *repository.Profile, src *userpb.Profile, path string) string {
+	switch path {
+	case "displayName":
+		dst.DisplayName = strings.TrimSpace(src.GetDisplayName())
+	case "bio":
+		dst.Bio = strings.TrimSpace(src.GetBio())
+	case "location":
+		dst.Location = strings.TrimSpace(src.GetLocation())
+	case "pronouns":
+		dst.Pronouns = strings.TrimSpace(src.GetPronouns())
+	case "links":
+		dst.Links = nil
+		for i, l := range src.GetLinks() {
+			u, err := url.Parse(l.GetUrl())
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Sprintf("links[%d].url must be an http or https URL", i)
+			}
+			dst.Links = append(dst.Links, repository.ProfileLink{Label: strings.TrimSpace(l.GetLabel()), URL: u.String()})
+		}
+	case "timezone":
+		// Local is the time zone of the server, not one of the user.
+		tz := src.GetTimezone()
+		if tz != "" {
+			if _, err := time.LoadLocation(tz); err != nil || tz == "Local" {
+				return "timezone must be an IANA time zone, such as Europe/Paris"
+			}
+		}
+		dst.Timezone = tz
+	case "locale":
+		// Tags are stored in their canonical form, such as en-US for en_us.
+		locale := src.GetLocale()
+		if locale != "" {
+			tag, err := language.Parse(locale)
+			if err != nil {
+				return "locale must be a BCP 47 language tag, such as en-US"
+			}
+			locale = tag.String()
+		}
+		dst.Locale = locale
+	default:
+		return fmt.Sprintf("unknown field %s", path)
+	}
+
+	return ""
+}
+
+// UpdatePreferences changes the preferences of a user named by the update
+// mask, or every one without it, with the same versioning as UpdateProfile.
+// Users who never set theirs start from the defaults.
+func (s *UserCRUDService) UpdatePreferences(ctx context.Context, req *userpb.UpdatePreferencesReq) (*userpb.UpdatePreferencesRes, error) {
+	id, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert ObjectId: %v", err))
+	}
+
+	current, err := s.users.Get(ctx, id)
+	if err != nil {
+		return nil, userError(err, req.GetId())
+	}
+
+	mask := req.GetUpdateMask().GetPaths()
+	if len(mask) == 0 {
+		mask = preferenceFields
+	}
+
+	prefs := repository.DefaultPreferences()
+	if current.Preferences != nil {
+		*prefs = *current.Preferences
+	}
+
+	for _, field := range mask {
+		problem := applyPreferenceField(prefs, req.GetPreferences(), field)
+		if problem != "" {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid preferences: %s", problem)
+		}
+	}
+
+	updated, err := s.users.SetPreferences(ctx, id, prefs, req.GetPreferences().GetVersion())
+	if err != nil {
+		return nil, userError(err, req.GetId())
+	}
+
+	return &userpb.UpdatePreferencesRes{
+		User: userMessage(updated),
+	}, nil
+}
+
+// applyPreferenceField copies the field of src named by path to dst, and
+// returns what is wrong with it, or "". Naming a message copies every field
+// of it.
+func applyPreferenceField(dst *repository.Preferences, src *userpb.Preferences, path string) string {
+	n := src.GetNotifications()
+	v := src.GetDefaultViews()
+	p := src.GetPrivacy()
+
+	switch path {
+	case "theme":
+		theme, ok := themes[src.GetTheme()]
+		if !ok {
+			return "theme is required"
+		}
+		dst.Theme = theme
+	case "notifications":
+		dst.Notifications = repository.NotificationPreferences{
+			SecurityAlerts: n.GetSecurityAlerts(),
+			TaskReminders:  n.GetTaskReminders(),
+			ProductUpdates: n.GetProductUpdates(),
+		}
+	case "notifications.securityAlerts":
+		dst.Notifications.SecurityAlerts = n.GetSecurityAlerts()
+	case "notifications.taskReminders":
+		dst.Notifications.TaskReminders = n.GetTaskReminders()
+	case "notifications.productUpdates":
+		dst.Notifications.ProductUpdates = n.GetProductUpdates()
+	case "defaultViews":
+		for _, field := range []string{"defaultViews.tasks", "defaultViews.calendar"} {
+			if problem := applyPreferenceField(dst, src, field); problem != "" {
+				return problem
+			}
+		}
+	case "defaultViews.tasks":
+		tasks, ok := taskViews[v.GetTasks()]
+		if !ok {
+			return "defaultViews.tasks is required"
+		}
+		dst.DefaultViews.Tasks = tasks
+	case "defaultViews.calendar":
+		calendar, ok := calendarViews[v.GetCalendar()]
+		if !ok {
+			return "defaultViews.calendar is required"
+		}
+		dst.DefaultViews.Calendar = calendar
+	case "privacy", "privacy.profileVisibility":
+		visibility, ok := visibilities[p.GetProfileVisibility()]
+		if !ok {
+			return "privacy.profileVisibility is required"
+		}
+		dst.Privacy.ProfileVisibility = visibility
+	default:
+		return fmt.Sprintf("unknown field %s", path)
+	}
+
+	return ""
+}
+
+// profileMessage converts a stored profile to the message returned by the
+// service. Users who never set theirs have an empty one, at version 0.
+func profileMessage(p *repository.Profile) *userpb.Profile {
+	if p == nil {
+		return &userpb.Profile{}
+	}
+
+	profile := &userpb.Profile{
+		DisplayName: p.DisplayName,
+		Bio:         p.Bio,
+		Location:    p.Location,
+		Timezone:    p.Timezone,
+		Locale:      p.Locale,
+		Pronouns:    p.Pronouns,
+		Version:     p.Version,
+	}
+
+	for _, l := range p.Links {
+		profile.Links = append(profile.Links, &userpb.ProfileLink{Label: l.Label, Url: l.URL})
+	}
+
+	return profile
+}
+
+// preferencesMessage converts stored preferences to the message returned by
+// the service. Users who never set theirs have the defaults.
+func preferencesMessage(p *repository.Preferences) *userpb.Preferences {
+	if p == nil {
+		p = repository.DefaultPreferences()
+	}
+
+	prefs := &userpb.Preferences{
+		Notifications: &userpb.NotificationPreferences{
+			SecurityAlerts: p.Notifications.SecurityAlerts,
+			TaskReminders:  p.Notifications.TaskReminders,
+			ProductUpdates: p.Notifications.ProductUpdates,
+		},
+		DefaultViews: &userpb.DefaultViews{},
+		Privacy:      &userpb.PrivacyPreferences{},
+		Version:      p.Version,
+	}
+
+	for e, value := range themes {
+		if value == p.Theme {
+			prefs.Theme = e
+		}
+	}
+	for e, value := range taskViews {
+		if value == p.DefaultViews.Tasks {
+			prefs.DefaultViews.Tasks = e
+		}
+	}
+	for e, value := range calendarViews {
+		if value == p.DefaultViews.Calendar {
+			prefs.DefaultViews.Calendar = e
+		}
+	}
+
+	visibility := p.Privacy.ProfileVisibility
+	if visibility == "" {
+		visibility = repository.VisibilityPublic
+	}
+	for e, value := range visibilities {
+		if value == visibility {
+			prefs.Privacy.ProfileVisibility = e
+		}
+	}
+
+	return prefs
+}
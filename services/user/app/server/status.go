@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+
+	userpb "github.com/allen-woods/the-supertask/services/user/proto"
+	"github.com/allen-woods/the-supertask/services/user/repository"
+)
+
+// reinstateIfSuspensionExpired records that a suspension has run out, and
+// returns the account as it is now. It is applied lazily, whenever an account
+// with an expired suspension is looked up.
+func (s *UserCRUDService) reinstateIfSuspensionExpired(ctx context.Context, data *repository.UserAccount) (*repository.UserAccount, error) {
+	if data.Status != repository.StatusSuspended || repository.EffectiveStatus(data) == repository.StatusSuspended {
+		return data, nil
+	}
+
+	reinstated, err := s.users.Transition(ctx, data.ID, []string{repository.StatusSuspended}, repository.StatusChange{})
+
+	// The account changed in the meantime, and is returned as it is.
+	if _, ok := err.(*repository.TransitionError); ok {
+		return reinstated, nil
+	}
+
+	return reinstated, err
+}
+
+func statusMessage(s string) userpb.Status {
+	switch s {
+	case repository.StatusPendingVerification:
+		return userpb.Status_STATUS_PENDING_VERIFICATION
+	case repository.StatusActive:
+		return userpb.Status_STATUS_ACTIVE
+	case repository.StatusSuspended:
+		return userpb.Status_STATUS_SUSPENDED
+	case repository.StatusPendingDeletion:
+		return userpb.Status_STATUS_PENDING_DELETION
+	case repository.StatusDeleted:
+		return userpb.Status_STATUS_DELETED
+	}
+
+	return userpb.Status_STATUS_UNSPECIFIED
+}
+
+func roleMessage(r string) userpb.Role {
+	if r == repository.RoleAdmin {
+		return userpb.Role_ROLE_ADMIN
+	}
+
+	return userpb.Role_ROLE_USER
+}
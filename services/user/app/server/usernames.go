@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	userpb "github.com/allen-woods/the-supertask/services/user/proto"
+	"github.com/allen-woods/the-supertask/services/user/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// reservedUserNames cannot be taken by anyone, whatever their case, as they
+// could pass for the staff or the routes of the app.
+var reservedUserNames = map[string]bool{
+	"abuse":         true,
+	"admin":         true,
+	"administrator": true,
+	"api":           true,
+	"blobs":         true,
+	"graphql":       true,
+	"help":          true,
+	"hostmaster":    true,
+	"login":         true,
+	"logout":        true,
+	"me":            true,
+	"moderator":     true,
+	"noreply":       true,
+	"null":          true,
+	"official":      true,
+	"postmaster":    true,
+	"root":          true,
+	"security":      true,
+	"settings":      true,
+	"signup":        true,
+	"staff":         true,
+	"support":       true,
+	"supertask":     true,
+	"system":        true,
+	"undefined":     true,
+	"webmaster":     true,
+}
+
+// ReadUserByUserName is the "read" method for public profile pages. A user
+// name changed within the redirect period still finds the account, whose
+// current user name then tells where the page has moved.
+func (s *UserCRUDService) ReadUserByUserName(ctx context.Context, req *userpb.ReadUserByUserNameReq) (*userpb.ReadUserByUserNameRes, error) {
+	data, err := s.users.GetByUserName(ctx, req.GetUserName(), time.Now().Add(-s.userNameRedirectPeriod))
+	if err == repository.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, fmt.Sprintf("Could not find user with user name %s", req.GetUserName()))
+	}
+	if err != nil {
+		return nil, userError(err, "")
+	}
+
+	reinstated, err := s.reinstateIfSuspensionExpired(ctx, data)
+	if err != nil {
+		return nil, userError(err, data.ID.Hex())
+	}
+
+	return &userpb.ReadUserByUserNameRes{
+		User: userMessage(reinstated),
+	}, nil
+}
+
+// checkUserName returns an error when the account with the given ID, or a new
+// one when it is nil, may not take userName: because it is reserved, or
+// because it was another account's within the redirect period, so that links
+// to their former page never lead to someone else. The unique index of user
+// names rules out the rest.
+func (s *UserCRUDService) checkUserName(ctx context.Context, id primitive.ObjectID, userName string) error {
+	if reservedUserNames[strings.ToLower(userName)] {
+		return status.Errorf(codes.InvalidArgument, fmt.Sprintf("The user name %s is reserved", userName))
+	}
+
+	data, err := s.users.GetByUserName(ctx, userName, time.Now().Add(-s.userNameRedirectPeriod))
+	if err == repository.ErrNotFound || err == nil && data.ID == id {
+		return nil
+	}
+	if err != nil {
+		return userError(err, id.Hex())
+	}
+
+	return userError(&repository.DuplicateError{Field: repository.FieldUserName}, id.Hex())
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"net/mail"
+	"unicode/utf8"
+
+	userpb "github.com/allen-woods/the-supertask/services/user/proto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// validate checks req against the rules its fields are annotated with in the
+// proto files, and returns an InvalidArgument status naming the first field
+// breaking them.
+func validate(req interface{}) error {
+	m, ok := req.(proto.Message)
+	if !ok {
+		return nil
+	}
+
+	problem := validateMessage(m.ProtoReflect(), "")
+	if problem != "" {
+		return status.Errorf(codes.InvalidArgument, "Invalid request: %s", problem)
+	}
+
+	return nil
+}
+
+// validateMessage returns what is wrong with the first field of m breaking its
+// rules, or "" when every field follows them. Fields are named from prefix.
+func validateMessage(m protoreflect.Message, prefix string) string {
+	fields := m.Descriptor().Fields()
+
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		path := prefix + string(fd.Name())
+
+		rules, _ := proto.GetExtension(fd.Options(), userpb.E_Field).(*userpb.FieldRules)
+
+		if !m.Has(fd) {
+			if rules.GetRequired() {
+				return path + " is required"
+			}
+			continue
+		}
+
+		switch {
+		case fd.IsList():
+			list := m.Get(fd).List()
+			if max := rules.GetMaxItems(); max > 0 && uint32(list.Len()) > max {
+				return fmt.Sprintf("%s must have at most %d items", path, max)
+			}
+			for j := 0; j < list.Len(); j++ {
+				item := fmt.Sprintf("%s[%d]", path, j)
+				switch {
+				case fd.Message() != nil:
+					if problem := validateMessage(list.Get(j).Message(), item+"."); problem != "" {
+						return problem
+					}
+				case fd.Kind() == protoreflect.StringKind:
+					if problem := validateString(list.Get(j).String(), rules); problem != "" {
+						return item + " " + problem
+					}
+				}
+			}
+		case fd.IsMap():
+		case fd.Message() != nil:
+			if problem := validateMessage(m.Get(fd).Message(), path+"."); problem != "" {
+				return problem
+			}
+		case fd.Kind() == protoreflect.StringKind:
+			if problem := validateString(m.Get(fd).String(), rules); problem != "" {
+				return path + " " + problem
+			}
+		}
+	}
+
+	return ""
+}
+
+// validateString returns what is wrong with v given rules, or "".
+func validateString(v string, rules *userpb.FieldRules) string {
+	n := uint32(utf8.RuneCountInString(v))
+
+	if min := rules.GetMinLen(); n < min {
+		return fmt.Sprintf("must be at least %d characters long", min)
+	}
+	if max := rules.GetMaxLen(); max > 0 && n > max {
+		return fmt.Sprintf("must be at most %d characters long", max)
+	}
+
+	if rules.GetObjectId() {
+		if _, err := primitive.ObjectIDFromHex(v); err != nil {
+			return "must be an ObjectId"
+		}
+	}
+
+	if rules.GetEmail() {
+		addr, err := mail.ParseAddress(v)
+		if err != nil || addr.Address != v {
+			return "must be an email address"
+		}
+	}
+
+	return ""
+}